@@ -18,6 +18,7 @@ import (
 	mcpsrv "github.com/mark3labs/mcp-go/server"
 	"go.uber.org/fx"
 
+	"github.com/namnv2496/mocktool/internal/domain"
 	"github.com/namnv2496/mocktool/internal/tools"
 )
 
@@ -61,6 +62,7 @@ func registerTool(s *mcpsrv.MCPServer, reg *tools.Registry, t tools.Tool) {
 		if err != nil {
 			return mcplib.NewToolResultErrorFromErr("encode args", err), nil
 		}
+		ctx = tools.WithCaller(ctx, tools.Caller{Actor: clientName(ctx), Source: domain.AuditSourceMCP})
 		result, err := reg.Invoke(ctx, t.Name, raw)
 		if err != nil {
 			return mcplib.NewToolResultErrorFromErr(t.Name+" failed", err), nil
@@ -74,6 +76,17 @@ func registerTool(s *mcpsrv.MCPServer, reg *tools.Registry, t tools.Tool) {
 	s.AddTool(mcpTool, handler)
 }
 
+// clientName identifies the MCP client for the audit log by the name it sent
+// in its initialize request.
+func clientName(ctx context.Context) string {
+	if session, ok := mcpsrv.ClientSessionFromContext(ctx).(mcpsrv.SessionWithClientInfo); ok {
+		if name := session.GetClientInfo().Name; name != "" {
+			return name
+		}
+	}
+	return "mcp"
+}
+
 // Start runs the SSE server on cfg.Addr until ctx is cancelled.
 func Start(ctx context.Context, sse *mcpsrv.SSEServer, addr string) error {
 	errCh := make(chan error, 1)
@@ -13,6 +13,7 @@ import (
 	"github.com/namnv2496/mocktool/internal/repository/ratelimiter"
 	"github.com/namnv2496/mocktool/internal/tools"
 	"github.com/namnv2496/mocktool/internal/usecase"
+	"github.com/namnv2496/mocktool/pkg/security"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 )
@@ -54,14 +55,37 @@ func InvokeServer(invokers ...any) *fx.App {
 			fx.Annotate(repository.NewFeatureRepository, fx.As(new(repository.IFeatureRepository))),
 			fx.Annotate(repository.NewScenarioRepository, fx.As(new(repository.IScenarioRepository))),
 			fx.Annotate(repository.NewAccountScenarioRepository, fx.As(new(repository.IAccountScenarioRepository))),
+			fx.Annotate(repository.NewAccountGroupRepository, fx.As(new(repository.IAccountGroupRepository))),
 			fx.Annotate(repository.NewMockAPIRepository, fx.As(new(repository.IMockAPIRepository))),
 			fx.Annotate(repository.NewGRPCMockAPIRepository, fx.As(new(repository.IGRPCMockAPIRepository))),
+			fx.Annotate(repository.NewGRPCDescriptorRepository, fx.As(new(repository.IGRPCDescriptorRepository))),
+			fx.Annotate(repository.NewGRPCProxyRepository, fx.As(new(repository.IGRPCProxyRepository))),
+			fx.Annotate(repository.NewRevisionRepository, fx.As(new(repository.IRevisionRepository))),
+			fx.Annotate(repository.NewAuditRepository, fx.As(new(repository.IAuditRepository))),
+			fx.Annotate(repository.NewRenameRepository, fx.As(new(repository.IRenameRepository))),
+			fx.Annotate(repository.NewTrashRepository, fx.As(new(repository.ITrashRepository))),
+			fx.Annotate(repository.NewBundleRepository, fx.As(new(repository.IBundleRepository))),
+			fx.Annotate(repository.NewSessionProfileRepository, fx.As(new(repository.ISessionProfileRepository))),
+			fx.Annotate(repository.NewSessionRepository, fx.As(new(repository.ISessionRepository))),
 
 			usecase.NewStatsStore,
+			usecase.NewGRPCDescriptorRegistry,
+			usecase.NewGRPCServiceIndex,
+			usecase.NewActivationReaper,
+			usecase.NewTrashReaper,
 			fx.Annotate(controller.NewMockController, fx.As(new(controller.IMockController))),
 			fx.Annotate(controller.NewFowardController, fx.As(new(controller.IForwardController))),
 			fx.Annotate(usecase.NewForwardUC, fx.As(new(usecase.IForwardUC))),
 			fx.Annotate(usecase.NewGRPCForwardUC, fx.As(new(usecase.IGRPCForwardUC))),
+			fx.Annotate(usecase.NewGRPCProxyUC, fx.As(new(usecase.IGRPCProxyUC))),
+			fx.Annotate(usecase.NewRevisionUC, fx.As(new(usecase.IRevisionUC))),
+			fx.Annotate(usecase.NewCloneUC, fx.As(new(usecase.ICloneUC))),
+			fx.Annotate(usecase.NewRenameUC, fx.As(new(usecase.IRenameUC))),
+			fx.Annotate(usecase.NewTrashUC, fx.As(new(usecase.ITrashUC))),
+			fx.Annotate(usecase.NewBundleUC, fx.As(new(usecase.IBundleUC))),
+			fx.Annotate(usecase.NewSessionUC, fx.As(new(usecase.ISessionUC))),
+			fx.Annotate(usecase.NewReadinessUC, fx.As(new(usecase.IReadinessUC))),
+			usecase.NewGRPCTranscoder,
 			fx.Annotate(controller.NewGRPCController, fx.As(new(controller.IGRPCController))),
 			// load test
 			fx.Annotate(controller.NewLoadTestController, fx.As(new(controller.ILoadTestController))),
@@ -71,6 +95,7 @@ func InvokeServer(invokers ...any) *fx.App {
 			fx.Annotate(ratelimiter.NewLimiter, fx.As(new(ratelimiter.ILimiter))),
 
 			repository.NewMongoConnect,
+			newServerTLS,
 
 			// AI chat assistant
 			buildToolsDeps,
@@ -86,6 +111,22 @@ func InvokeServer(invokers ...any) *fx.App {
 	return app
 }
 
+// newServerTLS returns nil when TLS is disabled, so every listener stays
+// plaintext.
+func newServerTLS(cfg *configs.Config) (*security.ServerTLS, error) {
+	if !cfg.TLSConfig.Enabled {
+		return nil, nil
+	}
+	return security.NewServerTLS(security.TLSOptions{
+		CertFile:     cfg.TLSConfig.CertFile,
+		KeyFile:      cfg.TLSConfig.KeyFile,
+		AutoCADir:    cfg.TLSConfig.AutoCADir,
+		Hosts:        cfg.TLSConfig.Hosts,
+		ClientAuth:   cfg.TLSConfig.ClientAuth,
+		ClientCAFile: cfg.TLSConfig.ClientCAFile,
+	})
+}
+
 func newChatHandler(reg *tools.Registry, cfg *configs.Config) *chat.Handler {
 	return chat.NewWithEndpoint(reg, cfg.OpenAIConfig.APIKey, cfg.OpenAIConfig.Model, cfg.OpenAIConfig.APIEndpoint)
 }
@@ -96,6 +137,10 @@ func startServer(
 	mockController controller.IMockController,
 	grpcController controller.IGRPCController,
 	stats *usecase.StatsStore,
+	grpcServiceIndex *usecase.GRPCServiceIndex,
+	activationReaper *usecase.ActivationReaper,
+	trashReaper *usecase.TrashReaper,
+	config *configs.Config,
 ) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -104,6 +149,15 @@ func startServer(
 			// Start stats reset worker
 			stats.StartResetWorker(ctx)
 
+			// Start gRPC reflection index refresh worker
+			grpcServiceIndex.StartRefreshWorker(context.Background(), config.AppConfig.GRPCReflectionRefresh)
+
+			// Start expired scenario activation reaper
+			activationReaper.StartWorker(context.Background(), config.AppConfig.ActivationReapInterval)
+
+			// Start purge of deleted features and scenarios past their retention
+			trashReaper.StartWorker(context.Background(), config.AppConfig.TrashPurgeInterval)
+
 			// Start forward controller in background
 			go func() {
 				if err := forwardController.StartMockServer(); err != nil {
@@ -118,6 +172,13 @@ func startServer(
 				}
 			}()
 
+			// Start gRPC-Web / Connect mock server in background
+			go func() {
+				if err := grpcController.StartGRPCWebServer(); err != nil {
+					slog.Error("gRPC-Web mock server error", "error", err)
+				}
+			}()
+
 			// Start mock controller in background
 			if err := mockController.StartHttpServer(); err != nil {
 				slog.Error("Mock server error", "error", err)
@@ -131,6 +192,9 @@ func startServer(
 
 			// Stop stats reset worker
 			stats.StopResetWorker()
+			grpcServiceIndex.StopRefreshWorker()
+			activationReaper.StopWorker()
+			trashReaper.StopWorker()
 
 			// Give servers time to finish processing requests
 			time.Sleep(2 * time.Second)
@@ -9,6 +9,7 @@ import (
 	"github.com/namnv2496/mocktool/cmd/slackbot"
 	"github.com/namnv2496/mocktool/internal/configs"
 	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/internal/usecase"
 )
 
 var slackBotCmd = &cobra.Command{
@@ -32,7 +33,20 @@ func buildSlackBotApp() *fx.App {
 			fx.Annotate(repository.NewScenarioRepository, fx.As(new(repository.IScenarioRepository))),
 			fx.Annotate(repository.NewAccountScenarioRepository, fx.As(new(repository.IAccountScenarioRepository))),
 			fx.Annotate(repository.NewMockAPIRepository, fx.As(new(repository.IMockAPIRepository))),
+			fx.Annotate(repository.NewGRPCMockAPIRepository, fx.As(new(repository.IGRPCMockAPIRepository))),
+			fx.Annotate(repository.NewGRPCDescriptorRepository, fx.As(new(repository.IGRPCDescriptorRepository))),
+			fx.Annotate(repository.NewRevisionRepository, fx.As(new(repository.IRevisionRepository))),
+			fx.Annotate(repository.NewAuditRepository, fx.As(new(repository.IAuditRepository))),
+			fx.Annotate(repository.NewRenameRepository, fx.As(new(repository.IRenameRepository))),
+			fx.Annotate(repository.NewTrashRepository, fx.As(new(repository.ITrashRepository))),
+			fx.Annotate(repository.NewBundleRepository, fx.As(new(repository.IBundleRepository))),
 			fx.Annotate(repository.NewCache, fx.As(new(repository.ICache))),
+			usecase.NewGRPCDescriptorRegistry,
+			fx.Annotate(usecase.NewRevisionUC, fx.As(new(usecase.IRevisionUC))),
+			fx.Annotate(usecase.NewCloneUC, fx.As(new(usecase.ICloneUC))),
+			fx.Annotate(usecase.NewRenameUC, fx.As(new(usecase.IRenameUC))),
+			fx.Annotate(usecase.NewTrashUC, fx.As(new(usecase.ITrashUC))),
+			fx.Annotate(usecase.NewBundleUC, fx.As(new(usecase.IBundleUC))),
 			buildToolsDeps,
 		),
 		slackbot.Module(),
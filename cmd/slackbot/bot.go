@@ -17,6 +17,7 @@ import (
 	"github.com/slack-go/slack/socketmode"
 	"go.uber.org/fx"
 
+	"github.com/namnv2496/mocktool/internal/domain"
 	"github.com/namnv2496/mocktool/internal/tools"
 )
 
@@ -120,7 +121,7 @@ func (b *Bot) handleEventsAPI(ctx context.Context, ev slackevents.EventsAPIEvent
 	}
 	switch e := ev.InnerEvent.Data.(type) {
 	case *slackevents.AppMentionEvent:
-		b.respond(ctx, e.Channel, threadOf(e.ThreadTimeStamp, e.TimeStamp), b.stripMention(e.Text))
+		b.respond(ctx, e.User, e.Channel, threadOf(e.ThreadTimeStamp, e.TimeStamp), b.stripMention(e.Text))
 	case *slackevents.MessageEvent:
 		// Only respond to direct messages and threaded replies to the bot.
 		// Skip the bot's own messages and edits.
@@ -128,15 +129,18 @@ func (b *Bot) handleEventsAPI(ctx context.Context, ev slackevents.EventsAPIEvent
 			return
 		}
 		if e.ChannelType == "im" || e.ThreadTimeStamp != "" {
-			b.respond(ctx, e.Channel, threadOf(e.ThreadTimeStamp, e.TimeStamp), e.Text)
+			b.respond(ctx, e.User, e.Channel, threadOf(e.ThreadTimeStamp, e.TimeStamp), e.Text)
 		}
 	}
 }
 
-func (b *Bot) respond(ctx context.Context, channel, threadTS, text string) {
+// respond runs the tools on behalf of user, the Slack user ID recorded in the
+// audit log.
+func (b *Bot) respond(ctx context.Context, user, channel, threadTS, text string) {
 	if strings.TrimSpace(text) == "" {
 		return
 	}
+	ctx = tools.WithCaller(ctx, tools.Caller{Actor: user, Source: domain.AuditSourceSlack})
 	reply := func(ctx context.Context, msg string) error {
 		_, _, err := b.api.PostMessageContext(
 			ctx, channel,
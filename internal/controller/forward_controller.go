@@ -22,6 +22,7 @@ import (
 	"github.com/namnv2496/mocktool/internal/repository/ratelimiter"
 	"github.com/namnv2496/mocktool/internal/usecase"
 	"github.com/namnv2496/mocktool/pkg/errorcustome"
+	"github.com/namnv2496/mocktool/pkg/security"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -30,20 +31,26 @@ type IForwardController interface {
 }
 
 type ForwardController struct {
-	config    *configs.Config
-	forwardUc usecase.IForwardUC
-	TestWay   int
+	config         *configs.Config
+	forwardUc      usecase.IForwardUC
+	grpcTranscoder *usecase.GRPCTranscoder
+	serverTLS      *security.ServerTLS
+	TestWay        int
 }
 
 func NewFowardController(
 	config *configs.Config,
 	forwardUc usecase.IForwardUC,
+	grpcTranscoder *usecase.GRPCTranscoder,
+	serverTLS *security.ServerTLS,
 	flags entity.ServiceFlags,
 ) IForwardController {
 	return &ForwardController{
-		config:    config,
-		forwardUc: forwardUc,
-		TestWay:   flags.TestWay,
+		config:         config,
+		forwardUc:      forwardUc,
+		grpcTranscoder: grpcTranscoder,
+		serverTLS:      serverTLS,
+		TestWay:        flags.TestWay,
 	}
 }
 
@@ -97,8 +104,10 @@ func (_self *ForwardController) StartMockServer() error {
 		}
 		return _self.responseMockData(c)
 	})
+	// REST routes of gRPC methods annotated with google.api.http
+	c.Any("/*", _self.transcodeGRPC)
 	fmt.Println("Start http response server")
-	if err := c.Start(_self.config.AppConfig.FowardHTTPPort); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if err := startEcho(c, _self.config.AppConfig.FowardHTTPPort, _self.serverTLS); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slog.Error("failed to start server", "error", err)
 		return err
 	}
@@ -158,3 +167,22 @@ func (_self *ForwardController) responsePublicMockData(c echo.Context) error {
 		return nil
 	}
 }
+
+// transcodeGRPC answers REST calls from the gRPC mocks, like grpc-gateway in
+// front of the real service. The call metadata comes from the X-Feature-Name,
+// X-Account-Id and X-Scenario headers.
+func (_self *ForwardController) transcodeGRPC(c echo.Context) error {
+	req := c.Request()
+	if !_self.grpcTranscoder.Matches(req) {
+		return echo.ErrNotFound
+	}
+	featureName, accountId, scenario := webCallMetadata(req.Header)
+	if featureName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Header X-Feature-Name is required")
+	}
+	statusCode, body, ok := _self.grpcTranscoder.Transcode(req.Context(), req, featureName, accountId, scenario)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "no gRPC method is bound to this route")
+	}
+	return c.Blob(statusCode, echo.MIMEApplicationJSON, body)
+}
@@ -1,18 +1,20 @@
 package controller
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"net"
 	"strings"
-	"time"
 
 	"google.golang.org/grpc"
+	channelzsvc "google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/encoding"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	grpc_reflection_v1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
 	grpc_reflection_v1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -22,8 +24,11 @@ import (
 	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/namnv2496/mocktool/internal/configs"
-	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/internal/domain"
 	"github.com/namnv2496/mocktool/internal/usecase"
+	"github.com/namnv2496/mocktool/pkg/observability"
+	"github.com/namnv2496/mocktool/pkg/security"
+	"github.com/namnv2496/mocktool/pkg/utils"
 )
 
 func init() {
@@ -63,23 +68,37 @@ func (rawBytesCodec) Unmarshal(data []byte, v any) error {
 
 type IGRPCController interface {
 	StartGRPCServer() error
+	StartGRPCWebServer() error
 }
 
 type GRPCController struct {
 	config       *configs.Config
 	grpcForward  usecase.IGRPCForwardUC
-	grpcMockRepo repository.IGRPCMockAPIRepository
+	serviceIndex *usecase.GRPCServiceIndex
+	descriptors  *usecase.GRPCDescriptorRegistry
+	proxy        usecase.IGRPCProxyUC
+	readiness    usecase.IReadinessUC
+	serverTLS    *security.ServerTLS
+	registered   map[string]grpc.ServiceInfo // services registered on the server
 }
 
 func NewGRPCController(
 	config *configs.Config,
 	grpcForward usecase.IGRPCForwardUC,
-	grpcMockRepo repository.IGRPCMockAPIRepository,
+	serviceIndex *usecase.GRPCServiceIndex,
+	descriptors *usecase.GRPCDescriptorRegistry,
+	proxy usecase.IGRPCProxyUC,
+	readiness usecase.IReadinessUC,
+	serverTLS *security.ServerTLS,
 ) IGRPCController {
 	return &GRPCController{
 		config:       config,
 		grpcForward:  grpcForward,
-		grpcMockRepo: grpcMockRepo,
+		serviceIndex: serviceIndex,
+		descriptors:  descriptors,
+		proxy:        proxy,
+		readiness:    readiness,
+		serverTLS:    serverTLS,
 	}
 }
 
@@ -90,63 +109,103 @@ func (_self *GRPCController) StartGRPCServer() error {
 		return err
 	}
 
-	srv := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.UnknownServiceHandler(_self.unknownServiceHandler),
-	)
+		grpc.ChainUnaryInterceptor(observability.GRPCUnaryServerInterceptor(_self.knownMethod, _self.serviceIndex.HasFeature)),
+		grpc.ChainStreamInterceptor(observability.GRPCStreamServerInterceptor(_self.knownMethod, _self.serviceIndex.HasFeature)),
+	}
+	if _self.serverTLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(_self.serverTLS.Config())))
+	}
+	srv := grpc.NewServer(opts...)
 
 	// Register dynamic reflection so Postman / grpcurl can discover services.
-	dynSvcs := &dynamicServices{repo: _self.grpcMockRepo}
-	reflSrv := reflection.NewServer(reflection.ServerOptions{
+	// Both v1 and v1alpha are served; newer clients try v1 first.
+	dynSvcs := &dynamicServices{index: _self.serviceIndex, descriptors: _self.descriptors}
+	reflOpts := reflection.ServerOptions{
 		Services:           dynSvcs,
 		DescriptorResolver: dynSvcs,
-	})
-	grpc_reflection_v1alpha.RegisterServerReflectionServer(srv, reflSrv)
+	}
+	grpc_reflection_v1.RegisterServerReflectionServer(srv, reflection.NewServerV1(reflOpts))
+	grpc_reflection_v1alpha.RegisterServerReflectionServer(srv, reflection.NewServer(reflOpts))
+
+	// Health checks for Kubernetes probes and client-side load balancers,
+	// channelz to inspect connections while debugging.
+	healthpb.RegisterHealthServer(srv, &grpcHealthServer{controller: _self})
+	channelzsvc.RegisterChannelzServiceToServer(srv)
+	_self.registered = srv.GetServiceInfo()
 
 	slog.Info("gRPC mock server listening", "addr", addr)
 	return srv.Serve(lis)
 }
 
+// knownMethod reports whether a method belongs to a registered service or
+// has a mock, an uploaded descriptor or a proxy. Metrics of other methods are
+// bucketed, since any client can call any name.
+func (_self *GRPCController) knownMethod(fullMethod string) bool {
+	service, method := utils.SplitGRPCMethod(fullMethod)
+	if _, ok := _self.registered[service]; ok {
+		return true
+	}
+	return _self.serviceIndex.HasMethod(service, method) ||
+		_self.descriptors.FindMethod(fullMethod) != nil ||
+		(_self.proxy != nil && _self.proxy.Find(service) != nil)
+}
+
 func (_self *GRPCController) unknownServiceHandler(_ any, stream grpc.ServerStream) error {
 	ctx := stream.Context()
 	fullMethod, _ := grpc.Method(ctx)
 
 	md, _ := metadata.FromIncomingContext(ctx)
 
-	featureName := firstMDValue(md, "x-feature-name")
+	featureName, accountId, scenario := grpcCallMetadata(md)
 	if featureName == "" {
 		return status.Error(codes.InvalidArgument, "x-feature-name metadata is required")
 	}
 
-	scenario := firstMDValue(md, "x-scenario")
-	if scenario == "" {
-		return status.Error(codes.InvalidArgument, "x-scenario metadata is required")
-	}
-
-	var reqBytes []byte
-	if err := stream.RecvMsg(&reqBytes); err != nil {
-		return status.Errorf(codes.Internal, "receive request: %v", err)
-	}
-
-	result, grpcCode, err := _self.grpcForward.HandleCall(
-		context.WithoutCancel(ctx),
+	// The stream context is kept so scripted delays stop when the client
+	// goes away.
+	return _self.serveCall(
+		ctx,
 		fullMethod,
-		reqBytes,
+		rawStream{stream},
+		md,
 		featureName,
+		accountId,
 		scenario,
 	)
-	if err != nil {
-		return err
-	}
-	if grpcCode != codes.OK {
-		return status.Error(grpcCode, "mock status")
+}
+
+// rawStream adapts grpc.ServerStream with rawBytesCodec to usecase.GRPCStream.
+type rawStream struct {
+	grpc.ServerStream
+}
+
+func (s rawStream) Recv() ([]byte, error) {
+	var b []byte
+	if err := s.RecvMsg(&b); err != nil {
+		return nil, err
 	}
+	return b, nil
+}
 
-	respBytes, err := proto.Marshal(result)
+func (s rawStream) Send(m proto.Message) error {
+	b, err := proto.Marshal(m)
 	if err != nil {
 		return status.Errorf(codes.Internal, "marshal response: %v", err)
 	}
+	return s.SendMsg(b)
+}
 
-	return stream.SendMsg(respBytes)
+// grpcCallMetadata reads the feature, account and scenario of a call. The
+// scenario follows the activation made in the admin UI for the account (or
+// the global one); x-scenario pins a scenario explicitly.
+func grpcCallMetadata(md metadata.MD) (featureName string, accountId *string, scenario string) {
+	featureName = firstMDValue(md, "x-feature-name")
+	if v := firstMDValue(md, "x-account-id"); v != "" {
+		accountId = &v
+	}
+	return featureName, accountId, firstMDValue(md, "x-scenario")
 }
 
 func firstMDValue(md metadata.MD, key string) string {
@@ -157,33 +216,37 @@ func firstMDValue(md metadata.MD, key string) string {
 }
 
 // dynamicServices implements ServiceInfoProvider and protodesc.Resolver so that
-// gRPC reflection advertises services and methods from the mock database.
+// gRPC reflection advertises services and methods from the mock index.
 // Postman and grpcurl can then discover services without a .proto file.
+// Services with an uploaded descriptor are advertised with their real files.
 type dynamicServices struct {
-	repo repository.IGRPCMockAPIRepository
+	index       *usecase.GRPCServiceIndex
+	descriptors *usecase.GRPCDescriptorRegistry
 }
 
-// GetServiceInfo returns the unique service names stored in the mock database.
+// GetServiceInfo returns the services that have mocks together with every
+// service that has an uploaded descriptor.
 func (d *dynamicServices) GetServiceInfo() map[string]grpc.ServiceInfo {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	mocks, err := d.repo.ListAll(ctx)
-	if err != nil {
-		return map[string]grpc.ServiceInfo{}
-	}
 	info := make(map[string]grpc.ServiceInfo)
-	for _, m := range mocks {
-		info[m.ServiceName] = grpc.ServiceInfo{}
+	for _, name := range d.descriptors.ServiceNames() {
+		info[name] = grpc.ServiceInfo{}
+	}
+	for _, name := range d.index.ServiceNames() {
+		info[name] = grpc.ServiceInfo{}
 	}
 	return info
 }
 
 // FindFileByPath satisfies protodesc.Resolver. It delegates well-known types
-// to the global registry and synthesises one file per service name.
+// to the global registry, then to uploaded descriptors, and synthesises one
+// file per service name.
 func (d *dynamicServices) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
 	if fd, err := protoregistry.GlobalFiles.FindFileByPath(path); err == nil {
 		return fd, nil
 	}
+	if fd, err := d.descriptors.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
 	const prefix, suffix = "mocktool/", ".proto"
 	if strings.HasPrefix(path, prefix) && strings.HasSuffix(path, suffix) {
 		svcName := path[len(prefix) : len(path)-len(suffix)]
@@ -193,11 +256,15 @@ func (d *dynamicServices) FindFileByPath(path string) (protoreflect.FileDescript
 }
 
 // FindDescriptorByName satisfies protodesc.Resolver. It delegates well-known
-// types to the global registry and falls back to our synthetic service files.
+// types to the global registry, then to uploaded descriptors, and falls back
+// to our synthetic service files.
 func (d *dynamicServices) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
 	if desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
 		return desc, nil
 	}
+	if desc, err := d.descriptors.FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
 	fd, err := d.buildServiceFile(string(name))
 	if err != nil {
 		return nil, protoregistry.NotFound
@@ -210,32 +277,24 @@ func (d *dynamicServices) FindDescriptorByName(name protoreflect.FullName) (prot
 }
 
 // buildServiceFile synthesises a FileDescriptor for one service from the
-// mock database. All methods use google.protobuf.Struct for input/output so
+// mock index. All methods use google.protobuf.Struct for input/output so
 // that Postman / grpcurl can call them without a hand-written .proto file.
 func (d *dynamicServices) buildServiceFile(fullServiceName string) (protoreflect.FileDescriptor, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	mocks, err := d.repo.ListAll(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	seen := map[string]bool{}
 	var methods []*descriptorpb.MethodDescriptorProto
-	for _, m := range mocks {
-		if m.ServiceName == fullServiceName && !seen[m.MethodName] {
-			seen[m.MethodName] = true
-			mn := m.MethodName
-			methods = append(methods, &descriptorpb.MethodDescriptorProto{
-				Name:       &mn,
-				InputType:  strPtr(".google.protobuf.Struct"),
-				OutputType: strPtr(".google.protobuf.Struct"),
-			})
-		}
+	for _, m := range d.index.Methods(fullServiceName) {
+		mn := m.Method
+		clientStreaming := m.StreamType == domain.GRPCStreamClient || m.StreamType == domain.GRPCStreamBidi
+		serverStreaming := m.StreamType == domain.GRPCStreamServer || m.StreamType == domain.GRPCStreamBidi
+		methods = append(methods, &descriptorpb.MethodDescriptorProto{
+			Name:            &mn,
+			InputType:       strPtr(".google.protobuf.Struct"),
+			OutputType:      strPtr(".google.protobuf.Struct"),
+			ClientStreaming: &clientStreaming,
+			ServerStreaming: &serverStreaming,
+		})
 	}
 	if len(methods) == 0 {
-		return nil, fmt.Errorf("service %q not found in mock database", fullServiceName)
+		return nil, fmt.Errorf("service %q not found in mock index", fullServiceName)
 	}
 
 	pkg, short := "", fullServiceName
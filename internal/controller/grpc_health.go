@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+// healthWatchInterval is how often Watch re-evaluates the status.
+var healthWatchInterval = 5 * time.Second
+
+// grpcHealthServer implements grpc.health.v1.Health. The status follows
+// Mongo and Redis readiness, unless a gRPC mock for
+// grpc.health.v1.Health/Check in the caller's scenario answers first.
+type grpcHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	controller *GRPCController
+}
+
+func (_self *grpcHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, err := _self.status(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	if st == healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// List reports the overall status and the status of every mocked service.
+func (_self *grpcHealthServer) List(ctx context.Context, _ *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	services := append([]string{""}, _self.serviceNames()...)
+	statuses := make(map[string]*healthpb.HealthCheckResponse, len(services))
+	for _, service := range services {
+		st, err := _self.status(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		statuses[service] = &healthpb.HealthCheckResponse{Status: st}
+	}
+	return &healthpb.HealthListResponse{Statuses: statuses}, nil
+}
+
+// Watch sends the current status, then every change until the client leaves.
+func (_self *grpcHealthServer) Watch(req *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(healthWatchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		st, err := _self.status(ctx, req.GetService())
+		if err != nil {
+			return err
+		}
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// status resolves the status of service, "" being the whole server. An
+// error is only returned when a mock answers with an error status.
+func (_self *grpcHealthServer) status(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	if st, ok, err := _self.mockedStatus(ctx, service); ok || err != nil {
+		return st, err
+	}
+	if service != "" && service != healthpb.Health_ServiceDesc.ServiceName && !_self.isKnownService(service) {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, nil
+	}
+	if _self.controller.readiness != nil {
+		if err := _self.controller.readiness.Check(ctx); err != nil {
+			slog.Warn("gRPC health check failed", "service", service, "error", err)
+			return healthpb.HealthCheckResponse_NOT_SERVING, nil
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING, nil
+}
+
+// mockedStatus looks up a grpc.health.v1.Health/Check mock for service in
+// the scenario selected by the call metadata. Calls without x-feature-name
+// (like Kubernetes probes) are never mocked, and calls no mock answers fall
+// back to the readiness checks.
+func (_self *grpcHealthServer) mockedStatus(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	featureName, accountId, scenario := grpcCallMetadata(md)
+	if featureName == "" {
+		return 0, false, nil
+	}
+
+	reqBytes, err := _self.healthRequest(service)
+	if err != nil {
+		return 0, false, status.Errorf(codes.Internal, "marshal health request: %v", err)
+	}
+	msg, _, err := _self.controller.grpcForward.HandleCall(ctx, healthCheckMethod, reqBytes, featureName, accountId, scenario)
+	switch {
+	case errors.Is(err, usecase.ErrNoGRPCMock):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, err
+	}
+
+	raw, err := protojson.Marshal(msg)
+	if err != nil {
+		return 0, false, status.Errorf(codes.Internal, "encode mocked health status: %v", err)
+	}
+	var resp healthpb.HealthCheckResponse
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, &resp); err != nil {
+		return 0, false, status.Errorf(codes.Internal, "mocked health status: %v", err)
+	}
+	return resp.GetStatus(), true, nil
+}
+
+// healthRequest encodes the Check request the way the mocks expect it: the
+// real message when grpc.health.v1 was uploaded, a Struct otherwise.
+func (_self *grpcHealthServer) healthRequest(service string) ([]byte, error) {
+	if _self.controller.descriptors.FindMethod(healthCheckMethod) != nil {
+		return proto.Marshal(&healthpb.HealthCheckRequest{Service: service})
+	}
+	fields := map[string]any{}
+	if service != "" {
+		fields["service"] = service
+	}
+	req, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(req)
+}
+
+func (_self *grpcHealthServer) isKnownService(service string) bool {
+	for _, name := range _self.serviceNames() {
+		if name == service {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceNames lists the services served by the mocks.
+func (_self *grpcHealthServer) serviceNames() []string {
+	infos := (&dynamicServices{index: _self.controller.serviceIndex, descriptors: _self.controller.descriptors}).GetServiceInfo()
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
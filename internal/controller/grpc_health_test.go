@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+	usecaseMocks "github.com/namnv2496/mocktool/mocks/usecase"
+)
+
+func setupHealthServer(t *testing.T) (*grpcHealthServer, *usecaseMocks.MockIGRPCForwardUC, *usecaseMocks.MockIReadinessUC) {
+	ctrl := gomock.NewController(t)
+	forward := usecaseMocks.NewMockIGRPCForwardUC(ctrl)
+	readiness := usecaseMocks.NewMockIReadinessUC(ctrl)
+	return &grpcHealthServer{controller: &GRPCController{grpcForward: forward, readiness: readiness}}, forward, readiness
+}
+
+func TestGRPCHealth_Readiness(t *testing.T) {
+	server, _, readiness := setupHealthServer(t)
+
+	readiness.EXPECT().Check(gomock.Any()).Return(nil)
+	resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	readiness.EXPECT().Check(gomock.Any()).Return(errors.New("mongo: timeout"))
+	resp, err = server.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	_, err = server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "demo.v1.Unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCHealth_MockedStatus(t *testing.T) {
+	server, forward, readiness := setupHealthServer(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-feature-name", "checkout", "x-scenario", "outage"))
+
+	forward.EXPECT().
+		HandleCall(gomock.Any(), healthCheckMethod, gomock.Any(), "checkout", gomock.Nil(), "outage").
+		DoAndReturn(func(_ context.Context, _ string, reqBytes []byte, _ string, _ *string, _ string) (proto.Message, codes.Code, error) {
+			var req structpb.Struct
+			require.NoError(t, proto.Unmarshal(reqBytes, &req))
+			assert.Equal(t, "demo.v1.UserService", req.Fields["service"].GetStringValue())
+			out, _ := structpb.NewStruct(map[string]any{"status": "NOT_SERVING"})
+			return out, codes.OK, nil
+		})
+	resp, err := server.Check(ctx, &healthpb.HealthCheckRequest{Service: "demo.v1.UserService"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	// Without a mock the real readiness answers, whatever the lookup returned.
+	for _, miss := range []error{
+		status.Error(codes.NotFound, "mock not found"),
+		status.Error(codes.FailedPrecondition, "no active scenario for feature checkout"),
+		status.Error(codes.Internal, "lookup: connection refused"),
+	} {
+		forward.EXPECT().
+			HandleCall(gomock.Any(), healthCheckMethod, gomock.Any(), "checkout", gomock.Nil(), "outage").
+			Return(nil, status.Code(miss), errors.Join(usecase.ErrNoGRPCMock, miss))
+		readiness.EXPECT().Check(gomock.Any()).Return(nil)
+		resp, err = server.Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err, miss)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status, miss)
+	}
+
+	// A mocked error status is returned as is.
+	forward.EXPECT().
+		HandleCall(gomock.Any(), healthCheckMethod, gomock.Any(), "checkout", gomock.Nil(), "outage").
+		Return(nil, codes.Unavailable, status.Error(codes.Unavailable, "draining"))
+	_, err = server.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+type fakeHealthWatchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *healthpb.HealthCheckResponse
+}
+
+func (s *fakeHealthWatchStream) Context() context.Context { return s.ctx }
+
+func (s *fakeHealthWatchStream) Send(resp *healthpb.HealthCheckResponse) error {
+	s.sent <- resp
+	return nil
+}
+
+func TestGRPCHealth_WatchSendsChanges(t *testing.T) {
+	server, _, readiness := setupHealthServer(t)
+	interval := healthWatchInterval
+	healthWatchInterval = 10 * time.Millisecond
+	t.Cleanup(func() { healthWatchInterval = interval })
+
+	gomock.InOrder(
+		readiness.EXPECT().Check(gomock.Any()).Return(errors.New("redis: refused")).Times(2),
+		readiness.EXPECT().Check(gomock.Any()).Return(nil).AnyTimes(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeHealthWatchStream{ctx: ctx, sent: make(chan *healthpb.HealthCheckResponse, 4)}
+	done := make(chan error, 1)
+	go func() { done <- server.Watch(&healthpb.HealthCheckRequest{}, stream) }()
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, (<-stream.sent).Status)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, (<-stream.sent).Status, "only changes are sent")
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-done))
+}
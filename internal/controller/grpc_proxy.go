@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+	"github.com/namnv2496/mocktool/pkg/observability"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+// serveCall runs a call against the mocks and, when it matches no mock or
+// its feature has no active scenario, forwards it to the upstream of its
+// service instead. Streaming calls are passed through with the messages the
+// mocks already read replayed first. md is the incoming call metadata,
+// forwarded to the upstream.
+func (_self *GRPCController) serveCall(
+	ctx context.Context,
+	fullMethod string,
+	stream usecase.GRPCStream,
+	md metadata.MD,
+	featureName string,
+	accountId *string,
+	scenario string,
+) error {
+	if _self.proxy == nil {
+		_, err := _self.grpcForward.HandleStream(ctx, fullMethod, stream, featureName, accountId, scenario)
+		return err
+	}
+
+	ps := &proxyStream{GRPCStream: stream}
+	code, err := _self.grpcForward.HandleStream(ctx, fullMethod, ps, featureName, accountId, scenario)
+	if (code != codes.NotFound && code != codes.FailedPrecondition) || ps.sent {
+		return err
+	}
+	serviceName, _ := utils.SplitGRPCMethod(fullMethod)
+	if _self.proxy.Find(serviceName) == nil {
+		return err
+	}
+	observability.SetGRPCMatch(ctx, observability.GRPCMatchProxy)
+	if _self.isStreamingMethod(fullMethod) || len(ps.received) > 1 {
+		ps.rewind()
+		_, err = _self.proxy.ForwardStream(ctx, fullMethod, md, ps)
+		return err
+	}
+	// Without an active scenario the request was never read.
+	if len(ps.received) == 0 {
+		if _, recvErr := ps.Recv(); recvErr != nil {
+			return err
+		}
+	}
+	_, err = _self.proxy.Forward(ctx, fullMethod, ps.received[0], md, stream)
+	return err
+}
+
+func (_self *GRPCController) isStreamingMethod(fullMethod string) bool {
+	method := _self.descriptors.FindMethod(fullMethod)
+	return method != nil && (method.IsStreamingClient() || method.IsStreamingServer())
+}
+
+// proxyStream keeps the requests read by the mocks so they can be replayed
+// to the upstream, and whether a response was already sent.
+type proxyStream struct {
+	usecase.GRPCStream
+	received  [][]byte
+	eof       bool
+	replaying bool
+	next      int
+	sent      bool
+}
+
+func (s *proxyStream) Recv() ([]byte, error) {
+	if s.replaying {
+		if s.next < len(s.received) {
+			s.next++
+			return s.received[s.next-1], nil
+		}
+		if s.eof {
+			return nil, io.EOF
+		}
+		return s.GRPCStream.Recv()
+	}
+	b, err := s.GRPCStream.Recv()
+	switch {
+	case err == nil:
+		s.received = append(s.received, b)
+	case err == io.EOF:
+		s.eof = true
+	}
+	return b, err
+}
+
+// rewind makes Recv return the kept requests again before reading on.
+func (s *proxyStream) rewind() {
+	s.replaying = true
+	s.next = 0
+}
+
+func (s *proxyStream) Send(m proto.Message) error {
+	s.sent = true
+	return s.GRPCStream.Send(m)
+}
+
+// headerMetadata converts the HTTP headers of a gRPC-Web or Connect call,
+// leaving out the headers of the HTTP and web protocols themselves.
+func headerMetadata(h http.Header) metadata.MD {
+	md := metadata.MD{}
+	for k, vals := range h {
+		k = strings.ToLower(k)
+		switch {
+		case strings.HasPrefix(k, "connect-"), strings.HasPrefix(k, "accept"),
+			k == "content-length", k == "connection", k == "host",
+			k == "x-grpc-web", k == "x-user-agent":
+			continue
+		}
+		md[k] = vals
+	}
+	return md
+}
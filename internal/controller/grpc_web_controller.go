@@ -0,0 +1,495 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+	"github.com/namnv2496/mocktool/pkg/observability"
+)
+
+// gRPC-Web and Connect share the HTTP listener started by StartGRPCWebServer.
+// Both are translated to usecase.GRPCStream so they serve exactly the same
+// mocks as the native gRPC port.
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+	connectJSONContentType = "application/json"
+	connectProtoType       = "application/proto"
+
+	frameFlagCompressed = 0x01
+	frameFlagTrailer    = 0x80
+)
+
+func (_self *GRPCController) StartGRPCWebServer() error {
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{http.MethodPost, http.MethodOptions},
+		AllowHeaders:  []string{"*"},
+		ExposeHeaders: []string{"*", "grpc-status", "grpc-message", "grpc-status-details-bin"},
+	}))
+	e.POST("/*", _self.serveGRPCWeb)
+
+	addr := _self.config.AppConfig.GRPCWebPort
+	slog.Info("gRPC-Web / Connect mock server listening", "addr", addr)
+	if err := startEcho(e, addr, _self.serverTLS); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (_self *GRPCController) serveGRPCWeb(c echo.Context) error {
+	contentType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	switch {
+	case strings.HasPrefix(contentType, grpcWebTextContentType):
+		return _self.handleGRPCWeb(c, true)
+	case strings.HasPrefix(contentType, grpcWebContentType):
+		return _self.handleGRPCWeb(c, false)
+	case contentType == connectJSONContentType || contentType == connectProtoType:
+		return _self.handleConnectUnary(c, contentType == connectJSONContentType)
+	}
+	return echo.NewHTTPError(http.StatusUnsupportedMediaType, "expected gRPC-Web or Connect unary content type")
+}
+
+/* ---------- gRPC-Web ---------- */
+
+func (_self *GRPCController) handleGRPCWeb(c echo.Context, text bool) error {
+	req := c.Request()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if text {
+		if body, err = decodeGRPCWebText(body); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid base64 body")
+		}
+	}
+
+	stream := &webStream{resp: c.Response(), text: text, contentType: req.Header.Get(echo.HeaderContentType)}
+	stream.in, err = splitGRPCFrames(body)
+	if err != nil {
+		stream.finish(status.Error(codes.InvalidArgument, err.Error()))
+		return nil
+	}
+
+	ctx, cancel := webCallContext(req)
+	defer cancel()
+	featureName, accountId, scenario := webCallMetadata(req.Header)
+	if featureName == "" {
+		stream.finish(status.Error(codes.InvalidArgument, "x-feature-name metadata is required"))
+		return nil
+	}
+	callErr := _self.serveWebCall(ctx, req, stream, featureName, accountId, scenario)
+	stream.finish(callErr)
+	return nil
+}
+
+// serveWebCall serves a gRPC-Web or Connect call with the metrics, span and
+// log line the gRPC interceptors record for native calls.
+func (_self *GRPCController) serveWebCall(
+	ctx context.Context,
+	req *http.Request,
+	stream usecase.GRPCStream,
+	featureName string,
+	accountId *string,
+	scenario string,
+) error {
+	md := headerMetadata(req.Header)
+	ctx, done := observability.StartGRPCCall(ctx, req.URL.Path, md, _self.knownMethod, _self.serviceIndex.HasFeature)
+	err := _self.serveCall(ctx, req.URL.Path, stream, md, featureName, accountId, scenario)
+	done(err)
+	return err
+}
+
+// webStream implements usecase.GRPCStream over a gRPC-Web HTTP exchange.
+// Messages are written as length-prefixed frames as soon as they are sent,
+// so server streaming works; the status goes in a final trailer frame.
+type webStream struct {
+	resp        *echo.Response
+	text        bool
+	contentType string
+	in          [][]byte
+	header      metadata.MD
+	trailer     metadata.MD
+}
+
+func (s *webStream) Recv() ([]byte, error) {
+	if len(s.in) == 0 {
+		return nil, io.EOF
+	}
+	b := s.in[0]
+	s.in = s.in[1:]
+	return b, nil
+}
+
+func (s *webStream) Send(m proto.Message) error {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return status.Errorf(codes.Internal, "marshal response: %v", err)
+	}
+	return s.writeFrame(0, b)
+}
+
+func (s *webStream) SetHeader(md metadata.MD) error {
+	if s.resp.Committed {
+		return errors.New("headers already sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *webStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+func (s *webStream) writeFrame(flag byte, payload []byte) error {
+	if !s.resp.Committed {
+		h := s.resp.Header()
+		h.Set(echo.HeaderContentType, s.contentType)
+		for k, vals := range s.header {
+			for _, v := range vals {
+				h.Add(k, encodeMetadataValue(k, v))
+			}
+		}
+		s.resp.WriteHeader(http.StatusOK)
+	}
+	frame := make([]byte, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+	if s.text {
+		frame = []byte(base64.StdEncoding.EncodeToString(frame))
+	}
+	if _, err := s.resp.Write(frame); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	s.resp.Flush()
+	return nil
+}
+
+// finish writes the trailer frame carrying the call status.
+func (s *webStream) finish(callErr error) {
+	st := status.Convert(callErr)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "grpc-status: %d\r\n", st.Code())
+	if st.Message() != "" {
+		fmt.Fprintf(&buf, "grpc-message: %s\r\n", url.PathEscape(st.Message()))
+	}
+	if len(st.Proto().GetDetails()) > 0 {
+		if b, err := proto.Marshal(st.Proto()); err == nil {
+			fmt.Fprintf(&buf, "grpc-status-details-bin: %s\r\n", base64.RawStdEncoding.EncodeToString(b))
+		}
+	}
+	for k, vals := range s.trailer {
+		for _, v := range vals {
+			fmt.Fprintf(&buf, "%s: %s\r\n", k, encodeMetadataValue(k, v))
+		}
+	}
+	if err := s.writeFrame(frameFlagTrailer, buf.Bytes()); err != nil {
+		slog.Debug("write gRPC-Web trailers", "error", err)
+	}
+}
+
+// splitGRPCFrames splits a gRPC-Web request body into message payloads.
+func splitGRPCFrames(body []byte) ([][]byte, error) {
+	var msgs [][]byte
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, errors.New("truncated frame header")
+		}
+		flag, size := body[0], binary.BigEndian.Uint32(body[1:5])
+		if uint32(len(body)-5) < size {
+			return nil, errors.New("truncated frame")
+		}
+		if flag&frameFlagCompressed != 0 {
+			return nil, errors.New("compressed messages are not supported")
+		}
+		if flag&frameFlagTrailer == 0 {
+			msgs = append(msgs, body[5:5+size])
+		}
+		body = body[5+size:]
+	}
+	return msgs, nil
+}
+
+// decodeGRPCWebText decodes a grpc-web-text body, which may be several
+// independently padded base64 chunks.
+func decodeGRPCWebText(body []byte) ([]byte, error) {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(body))
+	var out []byte
+	for len(s) > 0 {
+		seg := s
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			j := i
+			for j < len(s) && s[j] == '=' {
+				j++
+			}
+			seg = s[:j]
+		}
+		s = s[len(seg):]
+		b, err := base64.StdEncoding.DecodeString(seg)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+/* ---------- Connect unary ---------- */
+
+func (_self *GRPCController) handleConnectUnary(c echo.Context, isJSON bool) error {
+	req := c.Request()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return writeConnectError(c, status.Error(codes.InvalidArgument, err.Error()))
+	}
+	if isJSON {
+		if body, err = _self.connectJSONToWire(req.URL.Path, body); err != nil {
+			return writeConnectError(c, status.Errorf(codes.InvalidArgument, "decode request: %v", err))
+		}
+	}
+
+	featureName, accountId, scenario := webCallMetadata(req.Header)
+	if featureName == "" {
+		return writeConnectError(c, status.Error(codes.InvalidArgument, "x-feature-name metadata is required"))
+	}
+
+	ctx, cancel := webCallContext(req)
+	defer cancel()
+	stream := &connectStream{in: body}
+	callErr := _self.serveWebCall(ctx, req, stream, featureName, accountId, scenario)
+
+	h := c.Response().Header()
+	for k, vals := range stream.header {
+		for _, v := range vals {
+			h.Add(k, encodeMetadataValue(k, v))
+		}
+	}
+	for k, vals := range stream.trailer {
+		for _, v := range vals {
+			h.Add("Trailer-"+k, encodeMetadataValue(k, v))
+		}
+	}
+	if callErr != nil {
+		return writeConnectError(c, callErr)
+	}
+	if stream.out == nil {
+		return writeConnectError(c, status.Error(codes.Unimplemented, "method is not unary"))
+	}
+
+	if isJSON {
+		b, err := protojson.Marshal(stream.out)
+		if err != nil {
+			return writeConnectError(c, status.Errorf(codes.Internal, "marshal response: %v", err))
+		}
+		return c.Blob(http.StatusOK, connectJSONContentType, b)
+	}
+	b, err := proto.Marshal(stream.out)
+	if err != nil {
+		return writeConnectError(c, status.Errorf(codes.Internal, "marshal response: %v", err))
+	}
+	return c.Blob(http.StatusOK, connectProtoType, b)
+}
+
+// connectJSONToWire re-encodes a Connect JSON request as protobuf wire bytes,
+// using the uploaded descriptor when there is one and Struct otherwise.
+func (_self *GRPCController) connectJSONToWire(fullMethod string, body []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	var msg proto.Message = &structpb.Struct{}
+	if method := _self.descriptors.FindMethod(fullMethod); method != nil {
+		msg = dynamicpb.NewMessage(method.Input())
+	}
+	if err := protojson.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
+
+// connectStream implements usecase.GRPCStream for one unary Connect call.
+type connectStream struct {
+	in      []byte
+	read    bool
+	out     proto.Message
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *connectStream) Recv() ([]byte, error) {
+	if s.read {
+		return nil, io.EOF
+	}
+	s.read = true
+	return s.in, nil
+}
+
+func (s *connectStream) Send(m proto.Message) error {
+	if s.out != nil {
+		return status.Error(codes.Unimplemented, "streaming responses require the Connect streaming protocol")
+	}
+	s.out = m
+	return nil
+}
+
+func (s *connectStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *connectStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+type connectErrorDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type connectError struct {
+	Code    string               `json:"code"`
+	Message string               `json:"message,omitempty"`
+	Details []connectErrorDetail `json:"details,omitempty"`
+}
+
+func writeConnectError(c echo.Context, err error) error {
+	st := status.Convert(err)
+	body := connectError{Code: connectCodeName(st.Code()), Message: st.Message()}
+	for _, d := range st.Proto().GetDetails() {
+		body.Details = append(body.Details, connectErrorDetail{
+			Type:  strings.TrimPrefix(d.GetTypeUrl(), "type.googleapis.com/"),
+			Value: base64.RawStdEncoding.EncodeToString(d.GetValue()),
+		})
+	}
+	b, _ := json.Marshal(body)
+	return c.Blob(connectHTTPStatus(st.Code()), connectJSONContentType, b)
+}
+
+// connectCodeName converts a gRPC code to its Connect name, e.g.
+// InvalidArgument → invalid_argument.
+func connectCodeName(code codes.Code) string {
+	if code == codes.Canceled {
+		return "canceled"
+	}
+	var sb strings.Builder
+	for i, r := range code.String() {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// connectHTTPStatus follows the Connect protocol code → HTTP status table.
+func connectHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+/* ---------- shared ---------- */
+
+func webCallMetadata(h http.Header) (featureName string, accountId *string, scenario string) {
+	featureName = h.Get("x-feature-name")
+	if v := h.Get("x-account-id"); v != "" {
+		accountId = &v
+	}
+	return featureName, accountId, h.Get("x-scenario")
+}
+
+// webCallContext applies the client deadline sent as grpc-timeout (gRPC-Web)
+// or Connect-Timeout-Ms (Connect).
+func webCallContext(req *http.Request) (context.Context, context.CancelFunc) {
+	ctx := req.Context()
+	if ms, err := strconv.ParseInt(req.Header.Get("Connect-Timeout-Ms"), 10, 64); err == nil && ms > 0 {
+		return context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+	}
+	if d, ok := parseGRPCTimeout(req.Header.Get("grpc-timeout")); ok {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// parseGRPCTimeout parses the grpc-timeout header, e.g. "250m" or "5S".
+func parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second,
+		'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond,
+	}
+	unit, ok := units[v[len(v)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// encodeMetadataValue base64-encodes binary (-bin) metadata for HTTP/1.1.
+func encodeMetadataValue(key, v string) string {
+	if strings.HasSuffix(key, "-bin") {
+		return base64.RawStdEncoding.EncodeToString([]byte(v))
+	}
+	return v
+}
@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/usecase"
+	usecaseMocks "github.com/namnv2496/mocktool/mocks/usecase"
+)
+
+func setupGRPCWebController(t *testing.T) (*GRPCController, *usecaseMocks.MockIGRPCForwardUC) {
+	ctrl := gomock.NewController(t)
+	forward := usecaseMocks.NewMockIGRPCForwardUC(ctrl)
+	return &GRPCController{grpcForward: forward}, forward
+}
+
+func grpcWebFrame(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	require.NoError(t, err)
+	frame := make([]byte, 5+len(b))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(b)))
+	copy(frame[5:], b)
+	return frame
+}
+
+func TestGRPCWeb_ServerStreamingWithTrailers(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), "/demo.Svc/Watch", gomock.Any(), "feat", gomock.Nil(), "").
+		DoAndReturn(func(_ context.Context, _ string, stream usecase.GRPCStream, _ string, _ *string, _ string) (codes.Code, error) {
+			in, err := stream.Recv()
+			require.NoError(t, err)
+			var req structpb.Struct
+			require.NoError(t, proto.Unmarshal(in, &req))
+			assert.Equal(t, "1", req.Fields["id"].GetStringValue())
+
+			require.NoError(t, stream.SetHeader(metadata.Pairs("x-request-id", "r-1")))
+			stream.SetTrailer(metadata.Pairs("x-ratelimit-remaining", "0"))
+			for _, name := range []string{"a", "b"} {
+				msg, _ := structpb.NewStruct(map[string]any{"name": name})
+				require.NoError(t, stream.Send(msg))
+			}
+			return codes.OK, nil
+		})
+
+	reqMsg, _ := structpb.NewStruct(map[string]any{"id": "1"})
+	body := base64.StdEncoding.EncodeToString(grpcWebFrame(t, reqMsg))
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Watch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "application/grpc-web-text+proto")
+	req.Header.Set("x-feature-name", "feat")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "r-1", rec.Header().Get("x-request-id"))
+	raw, err := decodeGRPCWebText(rec.Body.Bytes())
+	require.NoError(t, err)
+
+	var names []string
+	var trailer string
+	for len(raw) > 0 {
+		size := binary.BigEndian.Uint32(raw[1:5])
+		payload := raw[5 : 5+size]
+		if raw[0]&frameFlagTrailer != 0 {
+			trailer = string(payload)
+		} else {
+			var s structpb.Struct
+			require.NoError(t, proto.Unmarshal(payload, &s))
+			names = append(names, s.Fields["name"].GetStringValue())
+		}
+		raw = raw[5+size:]
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Contains(t, trailer, "grpc-status: 0\r\n")
+	assert.Contains(t, trailer, "x-ratelimit-remaining: 0\r\n")
+}
+
+func TestGRPCWeb_ErrorStatus(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(codes.NotFound, status.Error(codes.NotFound, "mock not found"))
+
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Get", bytes.NewReader(nil))
+	req.Header.Set(echo.HeaderContentType, "application/grpc-web+proto")
+	req.Header.Set("x-feature-name", "feat")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	raw := rec.Body.Bytes()
+	require.GreaterOrEqual(t, len(raw), 5)
+	assert.Equal(t, byte(frameFlagTrailer), raw[0])
+	assert.Contains(t, string(raw[5:]), "grpc-status: 5\r\n")
+	assert.Contains(t, string(raw[5:]), "grpc-message: mock%20not%20found\r\n")
+}
+
+func TestConnect_UnaryJSON(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), "/demo.Svc/Get", gomock.Any(), "feat", gomock.Any(), "pinned").
+		DoAndReturn(func(_ context.Context, _ string, stream usecase.GRPCStream, _ string, accountId *string, _ string) (codes.Code, error) {
+			assert.Equal(t, "acc-1", *accountId)
+			in, err := stream.Recv()
+			require.NoError(t, err)
+			var req structpb.Struct
+			require.NoError(t, proto.Unmarshal(in, &req))
+			assert.Equal(t, float64(7), req.Fields["id"].GetNumberValue())
+
+			stream.SetTrailer(metadata.Pairs("x-cost", "1"))
+			msg, _ := structpb.NewStruct(map[string]any{"name": "Alice"})
+			return codes.OK, stream.Send(msg)
+		})
+
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Get", strings.NewReader(`{"id": 7}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	req.Header.Set("x-feature-name", "feat")
+	req.Header.Set("x-account-id", "acc-1")
+	req.Header.Set("x-scenario", "pinned")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name": "Alice"}`, rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("Trailer-X-Cost"))
+}
+
+func TestConnect_Error(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(codes.ResourceExhausted, status.Error(codes.ResourceExhausted, "slow down"))
+
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Get", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	req.Header.Set("x-feature-name", "feat")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "resource_exhausted", body["code"])
+	assert.Equal(t, "slow down", body["message"])
+}
+
+func TestConnect_ProxyFallback(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+	proxy := usecaseMocks.NewMockIGRPCProxyUC(gomock.NewController(t))
+	controller.proxy = proxy
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), "/demo.Svc/Get", gomock.Any(), "feat", gomock.Nil(), "").
+		DoAndReturn(func(_ context.Context, _ string, stream usecase.GRPCStream, _ string, _ *string, _ string) (codes.Code, error) {
+			_, err := stream.Recv()
+			require.NoError(t, err)
+			return codes.NotFound, status.Error(codes.NotFound, "mock not found")
+		})
+	proxy.EXPECT().Find("demo.Svc").Return(&domain.GRPCProxy{ServiceName: "demo.Svc"}).AnyTimes()
+	proxy.EXPECT().
+		Forward(gomock.Any(), "/demo.Svc/Get", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, reqBytes []byte, md metadata.MD, stream usecase.GRPCStream) (codes.Code, error) {
+			var req structpb.Struct
+			require.NoError(t, proto.Unmarshal(reqBytes, &req))
+			assert.Equal(t, float64(7), req.Fields["id"].GetNumberValue())
+			assert.Equal(t, []string{"Bearer t"}, md.Get("authorization"))
+			assert.Empty(t, md.Get("content-length"))
+
+			msg, _ := structpb.NewStruct(map[string]any{"name": "upstream"})
+			return codes.OK, stream.Send(msg)
+		})
+
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Get", strings.NewReader(`{"id": 7}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	req.Header.Set("x-feature-name", "feat")
+	req.Header.Set("Authorization", "Bearer t")
+	req.Header.Set("Content-Length", "9")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name": "upstream"}`, rec.Body.String())
+}
+
+func TestConnect_ProxyFallbackWithoutActiveScenario(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+	proxy := usecaseMocks.NewMockIGRPCProxyUC(gomock.NewController(t))
+	controller.proxy = proxy
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), "/demo.Svc/Get", gomock.Any(), "feat", gomock.Nil(), "").
+		Return(codes.FailedPrecondition, status.Error(codes.FailedPrecondition, "no active scenario for feature feat"))
+	proxy.EXPECT().Find("demo.Svc").Return(&domain.GRPCProxy{ServiceName: "demo.Svc"}).AnyTimes()
+	proxy.EXPECT().
+		Forward(gomock.Any(), "/demo.Svc/Get", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, reqBytes []byte, _ metadata.MD, stream usecase.GRPCStream) (codes.Code, error) {
+			var req structpb.Struct
+			require.NoError(t, proto.Unmarshal(reqBytes, &req))
+			assert.Equal(t, float64(7), req.Fields["id"].GetNumberValue(), "the unread request is forwarded")
+
+			msg, _ := structpb.NewStruct(map[string]any{"name": "upstream"})
+			return codes.OK, stream.Send(msg)
+		})
+
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Get", strings.NewReader(`{"id": 7}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	req.Header.Set("x-feature-name", "feat")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name": "upstream"}`, rec.Body.String())
+}
+
+func TestGRPCWeb_ProxyFallbackStream(t *testing.T) {
+	controller, forward := setupGRPCWebController(t)
+	proxy := usecaseMocks.NewMockIGRPCProxyUC(gomock.NewController(t))
+	controller.proxy = proxy
+
+	forward.EXPECT().
+		HandleStream(gomock.Any(), "/demo.Svc/Upload", gomock.Any(), "feat", gomock.Nil(), "").
+		DoAndReturn(func(_ context.Context, _ string, stream usecase.GRPCStream, _ string, _ *string, _ string) (codes.Code, error) {
+			for {
+				if _, err := stream.Recv(); err != nil {
+					return codes.NotFound, status.Error(codes.NotFound, "stream mock not found")
+				}
+			}
+		})
+	proxy.EXPECT().Find("demo.Svc").Return(&domain.GRPCProxy{ServiceName: "demo.Svc"}).AnyTimes()
+	proxy.EXPECT().
+		ForwardStream(gomock.Any(), "/demo.Svc/Upload", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ metadata.MD, stream usecase.GRPCStream) (codes.Code, error) {
+			// The messages read by the mocks are replayed.
+			var ids []string
+			for {
+				b, err := stream.Recv()
+				if err != nil {
+					break
+				}
+				var req structpb.Struct
+				require.NoError(t, proto.Unmarshal(b, &req))
+				ids = append(ids, req.Fields["id"].GetStringValue())
+			}
+			assert.Equal(t, []string{"1", "2"}, ids)
+
+			msg, _ := structpb.NewStruct(map[string]any{"name": "upstream"})
+			return codes.OK, stream.Send(msg)
+		})
+
+	first, _ := structpb.NewStruct(map[string]any{"id": "1"})
+	second, _ := structpb.NewStruct(map[string]any{"id": "2"})
+	body := append(grpcWebFrame(t, first), grpcWebFrame(t, second)...)
+	req := httptest.NewRequest(http.MethodPost, "/demo.Svc/Upload", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "application/grpc-web+proto")
+	req.Header.Set("x-feature-name", "feat")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.serveGRPCWeb(echo.New().NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	raw := rec.Body.Bytes()
+	require.Greater(t, len(raw), 5)
+	var resp structpb.Struct
+	require.NoError(t, proto.Unmarshal(raw[5:5+binary.BigEndian.Uint32(raw[1:5])], &resp))
+	assert.Equal(t, "upstream", resp.Fields["name"].GetStringValue())
+}
+
+func TestParseGRPCTimeout(t *testing.T) {
+	d, ok := parseGRPCTimeout("250m")
+	assert.True(t, ok)
+	assert.Equal(t, "250ms", d.String())
+
+	_, ok = parseGRPCTimeout("5x")
+	assert.False(t, ok)
+}
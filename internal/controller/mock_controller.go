@@ -22,6 +22,7 @@ import (
 	"github.com/namnv2496/mocktool/internal/domain"
 	"github.com/namnv2496/mocktool/internal/entity"
 	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/internal/tools"
 	"github.com/namnv2496/mocktool/internal/usecase"
 	"github.com/namnv2496/mocktool/pkg/observability"
 	"github.com/namnv2496/mocktool/pkg/security"
@@ -36,17 +37,34 @@ type IMockController interface {
 	StartHttpServer() error
 }
 
+// apiPrefix is the route group of the admin API.
+const apiPrefix = "/api/v1/mocktool"
+
 type MockController struct {
 	config              *configs.Config
 	FeatureRepo         repository.IFeatureRepository
 	ScenarioRepo        repository.IScenarioRepository
 	AccountScenarioRepo repository.IAccountScenarioRepository
+	AccountGroupRepo    repository.IAccountGroupRepository
 	MockAPIRepo         repository.IMockAPIRepository
 	GRPCMockAPIRepo     repository.IGRPCMockAPIRepository
+	GRPCDescriptors     *usecase.GRPCDescriptorRegistry
+	GRPCServiceIndex    *usecase.GRPCServiceIndex
+	GRPCProxy           usecase.IGRPCProxyUC
+	Revisions           usecase.IRevisionUC
+	AuditRepo           repository.IAuditRepository
+	Clones              usecase.ICloneUC
+	SessionProfileRepo  repository.ISessionProfileRepository
+	SessionRepo         repository.ISessionRepository
+	Sessions            usecase.ISessionUC
+	Renames             usecase.IRenameUC
+	Trash               usecase.ITrashUC
+	Bundles             usecase.IBundleUC
 	loadTestController  ILoadTestController
 	cacheRepo           repository.ICache
 	chatHandler         *chat.Handler
 	stats               *usecase.StatsStore
+	serverTLS           *security.ServerTLS
 }
 
 func NewMockController(
@@ -54,12 +72,26 @@ func NewMockController(
 	featureRepo repository.IFeatureRepository,
 	scenarioRepo repository.IScenarioRepository,
 	accountScenarioRepo repository.IAccountScenarioRepository,
+	accountGroupRepo repository.IAccountGroupRepository,
 	mockAPIRepo repository.IMockAPIRepository,
 	grpcMockAPIRepo repository.IGRPCMockAPIRepository,
+	grpcDescriptors *usecase.GRPCDescriptorRegistry,
+	grpcServiceIndex *usecase.GRPCServiceIndex,
+	grpcProxy usecase.IGRPCProxyUC,
+	revisions usecase.IRevisionUC,
+	auditRepo repository.IAuditRepository,
+	clones usecase.ICloneUC,
+	sessionProfileRepo repository.ISessionProfileRepository,
+	sessionRepo repository.ISessionRepository,
+	sessions usecase.ISessionUC,
+	renames usecase.IRenameUC,
+	trash usecase.ITrashUC,
+	bundles usecase.IBundleUC,
 	loadTestController ILoadTestController,
 	cacheRepo repository.ICache,
 	chatHandler *chat.Handler,
 	stats *usecase.StatsStore,
+	serverTLS *security.ServerTLS,
 ) IMockController {
 
 	return &MockController{
@@ -67,12 +99,26 @@ func NewMockController(
 		FeatureRepo:         featureRepo,
 		ScenarioRepo:        scenarioRepo,
 		AccountScenarioRepo: accountScenarioRepo,
+		AccountGroupRepo:    accountGroupRepo,
 		MockAPIRepo:         mockAPIRepo,
 		GRPCMockAPIRepo:     grpcMockAPIRepo,
+		GRPCDescriptors:     grpcDescriptors,
+		GRPCServiceIndex:    grpcServiceIndex,
+		GRPCProxy:           grpcProxy,
+		Revisions:           revisions,
+		AuditRepo:           auditRepo,
+		Clones:              clones,
+		SessionProfileRepo:  sessionProfileRepo,
+		SessionRepo:         sessionRepo,
+		Sessions:            sessions,
+		Renames:             renames,
+		Trash:               trash,
+		Bundles:             bundles,
 		loadTestController:  loadTestController,
 		cacheRepo:           cacheRepo,
 		chatHandler:         chatHandler,
 		stats:               stats,
+		serverTLS:           serverTLS,
 	}
 }
 
@@ -114,20 +160,43 @@ func (_self *MockController) StartHttpServer() error {
 	c.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	// Routes
-	v1 := c.Group("/api/v1/mocktool")
+	v1 := c.Group(apiPrefix)
+	// Group middleware only applies to the routes added after it.
+	v1.Use(_self.auditMiddleware(apiPrefix))
+
 	v1.GET("/features", _self.GetFeatures)                  // list all features
 	v1.GET("/features/search", _self.SearchFeaturesByName)  // list all features has name likely
 	v1.POST("/features", _self.CreateNewFeature)            // create new feature
 	v1.PUT("/features/:feature_id", _self.UpdateFeature)    // update or inactive
 	v1.DELETE("/features/:feature_id", _self.DeleteFeature) // update or inactive
-
-	v1.GET("/scenarios", _self.ListScenariosByFeature)                  // list all scenarios by feature
-	v1.GET("/scenarios/search", _self.SearchScenariosByFeatureAndName)  // list all scenarios by feature has name likely
-	v1.GET("/scenarios/active", _self.ListActiveScenariosByFeature)     // get active scenario for feature+account
-	v1.POST("/scenarios", _self.CreateNewScenariosByFeature)            // create new scenario
-	v1.PUT("/scenarios/:scenario_id", _self.UpdateScenarioByFeature)    // update scenario
-	v1.POST("/scenarios/:scenario_id/activate", _self.ActivateScenario) // activate scenario for account
-	v1.DELETE("/scenarios/:scenario_id", _self.DeleteScenario)          // Delete scenario
+	v1.POST("/features/:feature_id/clone", _self.CloneFeature)
+	v1.GET("/features/:feature_id/export", _self.ExportFeature) // YAML/JSON bundle of the feature
+	v1.POST("/import", _self.ImportBundle)                      // create or update from a bundle, ?dry_run=true to preview
+
+	v1.GET("/scenarios", _self.ListScenariosByFeature)                                // list all scenarios by feature
+	v1.GET("/scenarios/search", _self.SearchScenariosByFeatureAndName)                // list all scenarios by feature has name likely
+	v1.GET("/scenarios/active", _self.ListActiveScenariosByFeature)                   // get active scenario for feature+account
+	v1.POST("/scenarios", _self.CreateNewScenariosByFeature)                          // create new scenario
+	v1.PUT("/scenarios/:scenario_id", _self.UpdateScenarioByFeature)                  // update scenario
+	v1.POST("/scenarios/:scenario_id/activate", _self.ActivateScenario)               // activate scenario for account
+	v1.DELETE("/scenarios/:scenario_id", _self.DeleteScenario)                        // Delete scenario
+	v1.POST("/scenarios/:scenario_id/clone", _self.CloneScenario)                     // copy with its mocks, in or across features
+	v1.GET("/scenarios/:scenario_id/effective-mockapis", _self.ListEffectiveMockAPIs) // own and inherited mocks
+
+	v1.GET("/account-groups", _self.ListAccountGroups)           // list account groups
+	v1.PUT("/account-groups", _self.SaveAccountGroup)            // create or replace a group
+	v1.DELETE("/account-groups/:name", _self.DeleteAccountGroup) // delete a group
+
+	v1.GET("/session-profiles", _self.ListSessionProfiles)              // list session profiles
+	v1.PUT("/session-profiles", _self.SaveSessionProfile)               // create or replace a profile
+	v1.DELETE("/session-profiles/:name", _self.DeleteSessionProfile)    // delete a profile
+	v1.POST("/session-profiles/:name/apply", _self.ApplySessionProfile) // activate all its scenarios for one account
+	v1.GET("/sessions", _self.ListSessions)                             // applied profiles
+	v1.DELETE("/sessions/:session_id", _self.RevertSession)             // restore the account's previous scenarios
+
+	v1.GET("/trash", _self.ListTrash)                           // deleted features and scenarios
+	v1.POST("/trash/:trash_id/restore", _self.RestoreFromTrash) // undo a delete
+	v1.DELETE("/trash/:trash_id", _self.PurgeTrash)             // delete for good
 
 	v1.GET("/mockapis", _self.ListMockAPIsByScenario)                       // list all APIs by scenario
 	v1.GET("/mockapis/search", _self.SearchMockAPIsByScenarioAndNameOrPath) // search APIs by scenario and name/path
@@ -144,6 +213,22 @@ func (_self *MockController) StartHttpServer() error {
 	v1.PUT("/grpc/apis/:api_id", _self.UpdateGRPCMockAPI)
 	v1.DELETE("/grpc/apis/:api_id", _self.DeleteGRPCMockAPI)
 	v1.PATCH("/grpc/apis/:api_id/toggle", _self.ToggleGRPCMockAPI)
+	v1.POST("/grpc/apis/:api_id/reset-counter", _self.ResetGRPCSequenceCounter) // reset sequence counter
+	v1.POST("/grpc/descriptors", _self.UploadGRPCDescriptor)                    // upload .proto files or a FileDescriptorSet
+	v1.GET("/grpc/descriptors", _self.ListGRPCDescriptors)                      // list services with typed descriptors
+	v1.DELETE("/grpc/descriptors/:service_name", _self.DeleteGRPCDescriptor)    // fall back to Struct encoding
+	v1.GET("/grpc/proxies", _self.ListGRPCProxies)                              // list upstream proxies
+	v1.PUT("/grpc/proxies", _self.SaveGRPCProxy)                                // create or replace the proxy of a service
+	v1.DELETE("/grpc/proxies/:service_name", _self.DeleteGRPCProxy)             // stop proxying a service
+
+	// Revision history of mock APIs, gRPC mock APIs and scenarios
+	_self.registerRevisionRoutes(v1)
+
+	// Audit log of admin and tool actions
+	v1.GET("/audit", _self.ListAuditLog)
+
+	// TLS
+	v1.GET("/tls/ca.crt", _self.DownloadCACert) // local CA to trust when TLS uses a generated certificate
 
 	// Analytics
 	v1.GET("/stats", _self.GetStats)
@@ -156,7 +241,7 @@ func (_self *MockController) StartHttpServer() error {
 	fmt.Println("Start main server")
 	_self.loadTestController.RegisterRoutes(v1)
 	fmt.Println("Start main server")
-	if err := c.Start(_self.config.AppConfig.HTTPPort); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if err := startEcho(c, _self.config.AppConfig.HTTPPort, _self.serverTLS); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slog.Error("failed to start server", "error", err)
 		return err
 	}
@@ -268,6 +353,8 @@ func (_self *MockController) CreateNewFeature(c echo.Context) error {
 
 /* ---------- PUT /features/:feature_id ---------- */
 
+// DeleteFeature moves the feature with its scenarios and mocks to the trash,
+// or deletes them for good with ?hard=true.
 func (_self *MockController) DeleteFeature(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -278,21 +365,14 @@ func (_self *MockController) DeleteFeature(c echo.Context) error {
 	}
 
 	feature, err := _self.FeatureRepo.FindById(ctx, objectID)
-	if err != nil {
-		return c.NoContent(http.StatusOK)
+	if err != nil || feature.Name == "" {
+		return echo.NewHTTPError(http.StatusNotFound, "feature not found")
 	}
-	_self.FeatureRepo.DeleteById(ctx, objectID)
-	_self.ScenarioRepo.DeleteByFeatureName(ctx, feature.Name)
-	_self.MockAPIRepo.DeleteByFeatureName(ctx, feature.Name)
-
-	// invalid cache by scenario
-	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, feature.Name))
-	// recheck
-	_, err = _self.FeatureRepo.FindById(ctx, objectID)
+	entry, err := _self.Trash.DeleteFeature(ctx, feature.Name, revisionAuthor(c))
 	if err != nil {
-		return c.NoContent(http.StatusOK)
+		return trashHTTPError(err)
 	}
-	return c.NoContent(http.StatusOK)
+	return _self.deleted(c, entry)
 }
 
 func (_self *MockController) UpdateFeature(c echo.Context) error {
@@ -310,10 +390,20 @@ func (_self *MockController) UpdateFeature(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	update := bson.M{}
-	if req.Name != "" {
-		update["name"] = req.Name
+	existing, err := _self.FeatureRepo.FindById(ctx, objectID)
+	if err != nil || existing.Name == "" {
+		return echo.NewHTTPError(http.StatusNotFound, "feature not found")
+	}
+	name := existing.Name
+	// The name is referenced by every scenario and mock of the feature.
+	if req.Name != "" && req.Name != existing.Name {
+		if _, err := _self.Renames.RenameFeature(ctx, existing.Name, req.Name); err != nil {
+			return renameHTTPError(err)
+		}
+		name = req.Name
 	}
+
+	update := bson.M{}
 	if req.Description != "" {
 		update["description"] = req.Description
 	}
@@ -329,7 +419,7 @@ func (_self *MockController) UpdateFeature(c echo.Context) error {
 	}
 
 	// invalid cache by scenario
-	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, req.Name))
+	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, name))
 	return c.NoContent(http.StatusOK)
 }
 
@@ -385,6 +475,7 @@ func (_self *MockController) ListScenariosByFeature(c echo.Context) error {
 					FeatureName: globalScenario.FeatureName,
 					Name:        globalScenario.Name,
 					Description: globalScenario.Description,
+					ParentID:    globalScenario.ParentID,
 					CreatedAt:   globalScenario.CreatedAt,
 					UpdatedAt:   globalScenario.UpdatedAt,
 				})
@@ -445,6 +536,7 @@ func (_self *MockController) CreateNewScenariosByFeature(c echo.Context) error {
 		FeatureName string `json:"feature_name" validate:"required,no_spaces"`
 		Name        string `json:"name" validate:"required,no_spaces"`
 		Description string `json:"description"`
+		ParentID    string `json:"parent_id"` // inherit the mocks this scenario does not override
 	}
 
 	if err := c.Bind(&req); err != nil {
@@ -467,11 +559,22 @@ func (_self *MockController) CreateNewScenariosByFeature(c echo.Context) error {
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
 	}
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid parent_id")
+		}
+		if _, err := usecase.ValidateScenarioParent(ctx, _self.ScenarioRepo, scenarioReq, parentID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		scenarioReq.ParentID = &parentID
+	}
 
 	// Just create the scenario - activation is handled separately via AccountScenario
 	if err := _self.ScenarioRepo.Create(ctx, scenarioReq); err != nil {
 		return echo.NewHTTPError(http.StatusConflict, err.Error())
 	}
+	_self.recordRevision(c, domain.RevisionKindScenario, scenarioReq.ID, domain.RevisionActionCreate)
 
 	return c.JSON(http.StatusCreated, scenarioReq)
 }
@@ -487,19 +590,51 @@ func (_self *MockController) UpdateScenarioByFeature(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid scenario_id")
 	}
 
-	var req domain.Scenario
+	var req struct {
+		domain.Scenario
+		// ParentID sets the parent, "" removes it and null keeps it.
+		ParentID *string `json:"parent_id"`
+	}
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	existing, err := _self.ScenarioRepo.GetByObjectID(ctx, objectID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "scenario not found")
+	}
+
 	// Just update the scenario details - activation is handled separately via AccountScenario
 	update := req.ToMap()
+	delete(update, "name")
+	name := existing.Name
+	// The name is referenced by the mocks of the scenario.
+	if req.Name != "" && req.Name != existing.Name {
+		if _, err := _self.Renames.RenameScenario(ctx, existing.FeatureName, existing.Name, req.Name); err != nil {
+			return renameHTTPError(err)
+		}
+		name = req.Name
+	}
+	if req.ParentID != nil {
+		update["parent_id"] = nil
+		if *req.ParentID != "" {
+			parentID, err := primitive.ObjectIDFromHex(*req.ParentID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid parent_id")
+			}
+			if _, err := usecase.ValidateScenarioParent(ctx, _self.ScenarioRepo, existing, parentID); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			update["parent_id"] = parentID
+		}
+	}
 	if err := _self.ScenarioRepo.UpdateByObjectID(ctx, objectID, update); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	_self.recordRevision(c, domain.RevisionKindScenario, objectID, domain.RevisionActionUpdate)
 
 	// invalid cache by scenario
-	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, req.FeatureName, req.Name))
+	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, existing.FeatureName, name))
 	return c.NoContent(http.StatusOK)
 }
 
@@ -531,27 +666,53 @@ func (_self *MockController) ActivateScenario(c echo.Context) error {
 		accountId = &accountIdParam
 	}
 
-	// If activating globally (accountId is nil), remove ALL account-specific mappings for this feature
-	if accountId == nil {
-		// Delete all account-specific mappings
-		if err := _self.AccountScenarioRepo.DeactivateAllAccountSpecificMappings(ctx, scenario.FeatureName); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to deactivate account-specific scenarios: "+err.Error())
+	// Create new AccountScenario mapping
+	now := time.Now().UTC()
+	accountScenario := &domain.AccountScenario{
+		FeatureName:    scenario.FeatureName,
+		ScenarioID:     scenarioID,
+		AccountId:      accountId,
+		Target:         reqBody.Target,
+		GroupName:      strings.TrimSpace(reqBody.GroupName),
+		AccountPattern: reqBody.AccountPattern,
+		AccountPrefix:  reqBody.AccountPrefix,
+		Percentage:     reqBody.Percentage,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := accountScenario.ValidateTarget(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	var duration time.Duration
+	if reqBody.Duration != "" {
+		if duration, err = time.ParseDuration(reqBody.Duration); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid duration: "+err.Error())
 		}
 	}
-
-	// Deactivate existing active scenario for this feature+account
-	if err := _self.AccountScenarioRepo.DeactivateByFeatureAndAccount(ctx, scenario.FeatureName, accountId); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to deactivate existing scenario: "+err.Error())
+	if err := accountScenario.SetWindow(now, reqBody.StartsAt, reqBody.ExpiresAt, duration); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	// Create new AccountScenario mapping
-	now := time.Now().UTC()
-	accountScenario := &domain.AccountScenario{
-		FeatureName: scenario.FeatureName,
-		ScenarioID:  scenarioID,
-		AccountId:   accountId,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+	// A time-boxed activation is layered on top of the current one, which
+	// takes over again once it expires. A permanent one replaces everything.
+	if !accountScenario.IsTimeBoxed() && accountScenario.Target != "" {
+		// A cohort activation only replaces the same cohort.
+		if err := _self.AccountScenarioRepo.DeactivateByTarget(ctx, accountScenario); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to deactivate existing scenario: "+err.Error())
+		}
+	} else if !accountScenario.IsTimeBoxed() {
+		// If activating globally (accountId is nil), remove ALL account-specific mappings for this feature
+		if accountId == nil {
+			// Delete all account-specific mappings
+			if err := _self.AccountScenarioRepo.DeactivateAllAccountSpecificMappings(ctx, scenario.FeatureName); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to deactivate account-specific scenarios: "+err.Error())
+			}
+		}
+
+		// Deactivate existing active scenario for this feature+account
+		if err := _self.AccountScenarioRepo.DeactivateByFeatureAndAccount(ctx, scenario.FeatureName, accountId); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to deactivate existing scenario: "+err.Error())
+		}
 	}
 
 	if err := _self.AccountScenarioRepo.Create(ctx, accountScenario); err != nil {
@@ -575,7 +736,17 @@ func (_self *MockController) ActivateScenario(c echo.Context) error {
 			_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplateAccount, scenario.FeatureName, prevScenario.Name, *accountId))
 		}
 	}
-	return c.JSON(http.StatusOK, map[string]string{"message": "scenario activated successfully"})
+	resp := map[string]any{"message": "scenario activated successfully"}
+	if accountScenario.Target != "" {
+		resp["target"] = accountScenario.Target
+	}
+	if accountScenario.StartsAt != nil {
+		resp["starts_at"] = accountScenario.StartsAt
+	}
+	if accountScenario.ExpiresAt != nil {
+		resp["expires_at"] = accountScenario.ExpiresAt
+	}
+	return c.JSON(http.StatusOK, resp)
 }
 
 /* ---------- DELETE /scenarios/:scenario_id/deactivate ---------- */
@@ -594,17 +765,13 @@ func (_self *MockController) DeleteScenario(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "scenario not found")
 	}
-	_self.ScenarioRepo.DeleteByObjectID(ctx, scenarioID)
-	_self.MockAPIRepo.DeleteByScenarioName(ctx, scenario.Name)
-
-	// invalid cache by scenario
-	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, scenario.FeatureName, scenario.Name))
-	// Delete the scenario for this account
-	if err := _self.AccountScenarioRepo.DeleteByScenarioId(ctx, scenarioID); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to deactivate scenario: "+err.Error())
+	// Its mocks and activations go to the trash with it
+	entry, err := _self.Trash.DeleteScenario(ctx, scenario.FeatureName, scenario.Name, revisionAuthor(c))
+	if err != nil {
+		return trashHTTPError(err)
 	}
-
-	return c.JSON(http.StatusOK, map[string]string{"message": "scenario deactivated successfully"})
+	_self.recordDeletedRevision(c, domain.RevisionKindScenario, scenarioID, scenario)
+	return _self.deleted(c, entry)
 }
 
 /* ---------- GET /mockapis?scenario_name= ---------- */
@@ -986,6 +1153,7 @@ func (_self *MockController) CreateMockAPIByScenario(c echo.Context) error {
 	if err := _self.MockAPIRepo.Create(ctx, &req); err != nil {
 		return echo.NewHTTPError(http.StatusConflict, err.Error())
 	}
+	_self.recordRevision(c, domain.RevisionKindMockAPI, req.ID, domain.RevisionActionCreate)
 
 	// Convert response to JSON-friendly format for frontend
 	var inputJSON any
@@ -1041,6 +1209,9 @@ func (_self *MockController) DeleteMockAPIByScenario(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusOK, nil)
 	}
+	if mockAPI != nil && mockAPI.Name != "" {
+		_self.recordRevision(c, domain.RevisionKindMockAPI, objectID, domain.RevisionActionDelete)
+	}
 	_self.MockAPIRepo.DeletByObjectID(ctx, objectID)
 	// invalid cache by scenario
 	if mockAPI != nil && mockAPI.Name != "" {
@@ -1249,6 +1420,7 @@ func (_self *MockController) UpdateMockAPIByScenario(c echo.Context) error {
 	if err := _self.MockAPIRepo.UpdateByObjectID(ctx, objectID, update); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	_self.recordRevision(c, domain.RevisionKindMockAPI, objectID, domain.RevisionActionUpdate)
 
 	// invalid cache by scenario
 	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, reqBody.FeatureName, reqBody.ScenarioName))
@@ -1499,7 +1671,9 @@ func (_self *MockController) HandleChat(c echo.Context) error {
 	if req.Message == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message is required"})
 	}
-	reply, err := _self.chatHandler.Chat(c.Request().Context(), req.History, req.Message)
+	// The chat's tool calls are audited with the web user as actor.
+	ctx := tools.WithCaller(c.Request().Context(), tools.Caller{Actor: revisionAuthor(c), Source: domain.AuditSourceChat})
+	reply, err := _self.chatHandler.Chat(ctx, req.History, req.Message)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -1579,6 +1753,9 @@ func (_self *MockController) CreateGRPCMockAPI(c echo.Context) error {
 	if err := _self.GRPCMockAPIRepo.Create(ctx, m); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	_self.recordRevision(c, domain.RevisionKindGRPCMockAPI, m.ID, domain.RevisionActionCreate)
+	_self.invalidateGRPCMockCache(ctx, m)
+	_self.GRPCServiceIndex.Put(m)
 	return c.JSON(http.StatusCreated, map[string]string{"id": m.ID.Hex()})
 }
 
@@ -1601,19 +1778,30 @@ func (_self *MockController) ImportGRPCMockAPIs(c echo.Context) error {
 			skipped++
 			continue
 		}
+		_self.recordRevision(c, domain.RevisionKindGRPCMockAPI, m.ID, domain.RevisionActionCreate)
+		_self.invalidateGRPCMockCache(ctx, m)
+		_self.GRPCServiceIndex.Put(m)
 		created++
 	}
 	return c.JSON(http.StatusOK, map[string]int{"created": created, "skipped": skipped})
 }
 
 func (_self *MockController) buildGRPCMockDomain(req entity.GRPCMockAPIRequest) (*domain.GRPCMockAPI, error) {
-	var outputData any
-	if err := json.Unmarshal(req.Output, &outputData); err != nil {
-		return nil, err
-	}
-	outputBSON, err := bson.Marshal(outputData)
-	if err != nil {
-		return nil, err
+	var (
+		outputBSON []byte
+		err        error
+	)
+	if len(req.Output) > 0 && string(req.Output) != "null" {
+		var outputData any
+		if err := json.Unmarshal(req.Output, &outputData); err != nil {
+			return nil, err
+		}
+		outputBSON, err = bson.Marshal(outputData)
+		if err != nil {
+			return nil, err
+		}
+	} else if (req.StreamType == domain.GRPCStreamUnary && len(req.Sequence) == 0) || req.StreamType == domain.GRPCStreamClient {
+		return nil, errors.New("output is required")
 	}
 
 	hashInput := ""
@@ -1637,7 +1825,7 @@ func (_self *MockController) buildGRPCMockDomain(req entity.GRPCMockAPIRequest)
 		hashInput = utils.HashInputConsistent(inputBsonData)
 	}
 
-	return &domain.GRPCMockAPI{
+	m := &domain.GRPCMockAPI{
 		FeatureName:  req.FeatureName,
 		ScenarioName: req.ScenarioName,
 		ServiceName:  req.ServiceName,
@@ -1647,7 +1835,24 @@ func (_self *MockController) buildGRPCMockDomain(req entity.GRPCMockAPIRequest)
 		Output:       outputBSON,
 		StatusCode:   req.StatusCode,
 		Latency:      req.Latency,
-	}, nil
+		Hang:         req.Hang != nil && *req.Hang,
+	}
+	if err := buildGRPCStreamFields(req, m); err != nil {
+		return nil, err
+	}
+	if err := _self.buildGRPCResponseMeta(req, m); err != nil {
+		return nil, err
+	}
+	if m.Sequence, err = buildGRPCSequence(req); err != nil {
+		return nil, err
+	}
+	if m.Matchers, err = buildGRPCMatchers(req); err != nil {
+		return nil, err
+	}
+	if _, err := _self.GRPCDescriptors.HashTypedInputs(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
 func (_self *MockController) ListGRPCMockAPIs(c echo.Context) error {
@@ -1690,7 +1895,19 @@ func (_self *MockController) ListGRPCMockAPIs(c echo.Context) error {
 			Output:       outputJSON,
 			StatusCode:   a.StatusCode,
 			Latency:      a.Latency,
+			Hang:         a.Hang,
 			IsActive:     a.IsActive,
+			StreamType:   a.StreamType,
+			Responses:    streamMessagesToJSON(a.Responses),
+			Aggregate:    a.Aggregate,
+			BidiRules:    bidiRulesToJSON(a.BidiRules),
+
+			Headers:       a.Headers,
+			Trailers:      a.Trailers,
+			StatusMessage: a.StatusMessage,
+			Details:       statusDetailsToJSON(a.Details),
+			Sequence:      grpcSequenceToJSON(a.Sequence),
+			Matchers:      grpcMatchersToJSON(a.Matchers),
 		})
 	}
 	return c.JSON(http.StatusOK, result)
@@ -1705,17 +1922,26 @@ func (_self *MockController) UpdateGRPCMockAPI(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid api_id")
 	}
 
+	existing, err := _self.GRPCMockAPIRepo.FindByID(ctx, id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "gRPC mock not found")
+	}
+
 	var req entity.GRPCMockAPIRequest
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	update := bson.M{}
+	// merged is the mock as it will be stored, to rehash typed inputs.
+	merged := *existing
 	if req.ServiceName != "" {
 		update["service_name"] = req.ServiceName
+		merged.ServiceName = req.ServiceName
 	}
 	if req.MethodName != "" {
 		update["method_name"] = req.MethodName
+		merged.MethodName = req.MethodName
 	}
 	if req.StatusCode != 0 {
 		update["status_code"] = req.StatusCode
@@ -1723,6 +1949,9 @@ func (_self *MockController) UpdateGRPCMockAPI(c echo.Context) error {
 	if req.Latency != 0 {
 		update["latency"] = req.Latency
 	}
+	if req.Hang != nil {
+		update["hang"] = *req.Hang
+	}
 	update["status_code"] = req.StatusCode
 
 	if len(req.Input) > 0 && string(req.Input) != "null" {
@@ -1741,8 +1970,10 @@ func (_self *MockController) UpdateGRPCMockAPI(c echo.Context) error {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "failed to convert input to BSON: "+err.Error())
 		}
+		hashInput := utils.GenerateHashFromInput(inputBsonData)
 		update["input"] = inputData
-		update["hash_input"] = utils.GenerateHashFromInput(inputBsonData)
+		update["hash_input"] = hashInput
+		merged.Input, merged.HashInput = inputBsonData, hashInput
 	}
 
 	if len(req.Output) > 0 && string(req.Output) != "null" && string(req.Output) != "" {
@@ -1771,9 +2002,54 @@ func (_self *MockController) UpdateGRPCMockAPI(c echo.Context) error {
 		update["output"] = outputJSON
 	}
 
+	if req.StreamType != "" || req.Responses != nil || req.BidiRules != nil {
+		stream := &domain.GRPCMockAPI{}
+		if err := buildGRPCStreamFields(req, stream); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		update["stream_type"] = stream.StreamType
+		update["aggregate"] = stream.Aggregate
+		update["responses"] = stream.Responses
+		update["bidi_rules"] = stream.BidiRules
+		merged.StreamType, merged.Aggregate, merged.BidiRules = stream.StreamType, stream.Aggregate, stream.BidiRules
+	}
+	if changed, err := _self.GRPCDescriptors.HashTypedInputs(&merged); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if changed {
+		update["hash_input"] = merged.HashInput
+		update["bidi_rules"] = merged.BidiRules
+	}
+
+	metaUpdate, err := _self.grpcResponseMetaUpdate(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for k, v := range metaUpdate {
+		update[k] = v
+	}
+	if req.Sequence != nil {
+		sequence, err := buildGRPCSequence(req)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		update["sequence"] = sequence
+	}
+	if req.Matchers != nil {
+		matchers, err := buildGRPCMatchers(req)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		update["matchers"] = matchers
+	}
+
 	if err := _self.GRPCMockAPIRepo.UpdateByID(ctx, id, update); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	_self.recordRevision(c, domain.RevisionKindGRPCMockAPI, id, domain.RevisionActionUpdate)
+	_self.invalidateGRPCMockCache(ctx, existing)
+	if updated, err := _self.GRPCMockAPIRepo.FindByID(ctx, id); err == nil {
+		_self.GRPCServiceIndex.Put(updated)
+	}
 	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
 }
 
@@ -1786,9 +2062,17 @@ func (_self *MockController) DeleteGRPCMockAPI(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid api_id")
 	}
 
+	existing, err := _self.GRPCMockAPIRepo.FindByID(ctx, id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "gRPC mock not found")
+	}
+
+	_self.recordRevision(c, domain.RevisionKindGRPCMockAPI, id, domain.RevisionActionDelete)
 	if err := _self.GRPCMockAPIRepo.DeleteByID(ctx, id); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	_self.invalidateGRPCMockCache(ctx, existing)
+	_self.GRPCServiceIndex.Remove(id)
 	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
@@ -1812,5 +2096,15 @@ func (_self *MockController) ToggleGRPCMockAPI(c echo.Context) error {
 	}); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	return c.JSON(http.StatusOK, map[string]bool{"is_active": !existing.IsActive})
+	_self.recordRevision(c, domain.RevisionKindGRPCMockAPI, id, domain.RevisionActionUpdate)
+	_self.invalidateGRPCMockCache(ctx, existing)
+	existing.IsActive = !existing.IsActive
+	_self.GRPCServiceIndex.Put(existing)
+	return c.JSON(http.StatusOK, map[string]bool{"is_active": existing.IsActive})
+}
+
+// invalidateGRPCMockCache drops the cached gRPC lookups of the scenario the
+// mock belongs to, including negative entries.
+func (_self *MockController) invalidateGRPCMockCache(ctx context.Context, m *domain.GRPCMockAPI) {
+	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyGRPCScenarioTemplate, m.FeatureName, m.ScenarioName))
 }
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+)
+
+/* ---------- Account groups ---------- */
+
+func (_self *MockController) ListAccountGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	groups, err := _self.AccountGroupRepo.ListAll(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if groups == nil {
+		groups = []domain.AccountGroup{}
+	}
+	return c.JSON(http.StatusOK, groups)
+}
+
+// SaveAccountGroup creates or replaces a group. Activations of the group
+// follow its new members right away.
+func (_self *MockController) SaveAccountGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req entity.AccountGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	accountIds := make([]string, 0, len(req.AccountIds))
+	seen := make(map[string]bool, len(req.AccountIds))
+	for _, id := range req.AccountIds {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		accountIds = append(accountIds, id)
+	}
+
+	group := &domain.AccountGroup{
+		Name:        req.Name,
+		Description: req.Description,
+		AccountIds:  accountIds,
+	}
+	if err := _self.AccountGroupRepo.Upsert(ctx, group); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, group)
+}
+
+func (_self *MockController) DeleteAccountGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+	if err := _self.AccountGroupRepo.DeleteByName(ctx, name); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
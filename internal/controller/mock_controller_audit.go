@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+// auditSkippedRoutes change nothing in mocktool: the chat records its tool
+// calls itself and test-real only calls the real API.
+var auditSkippedRoutes = map[string]bool{
+	"/chat":                       true,
+	"/chat/clear":                 true,
+	"/mockapis/:api_id/test-real": true,
+	"/*":                          true, // unknown routes
+	"":                            true,
+}
+
+// auditResponseRecorder keeps a copy of the response body.
+type auditResponseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (_self *auditResponseRecorder) Write(b []byte) (int, error) {
+	_self.body.Write(b)
+	return _self.ResponseWriter.Write(b)
+}
+
+// auditMiddleware records every mutating request of the group under prefix
+// with the target document before and after it. Reads are not recorded.
+func (_self *MockController) auditMiddleware(prefix string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := strings.TrimPrefix(c.Path(), prefix)
+			if _self.AuditRepo == nil || auditSkippedRoutes[route] ||
+				req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions {
+				return next(c)
+			}
+
+			entry := &domain.AuditEntry{
+				Actor:     revisionAuthor(c),
+				Source:    domain.AuditSourceHTTP,
+				Operation: req.Method + " " + route,
+				Target:    auditTarget(c),
+				Before:    _self.auditDocument(c),
+			}
+			// Multipart uploads (descriptors) are not kept.
+			if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) && req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body")
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				entry.Request = jsonObject(body)
+			}
+			recorder := &auditResponseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			err := next(c)
+
+			c.Response().Writer = recorder.ResponseWriter
+			entry.Status = c.Response().Status
+			if err != nil {
+				entry.Error = err.Error()
+				entry.Status = http.StatusInternalServerError
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					entry.Status = he.Code
+				}
+			} else if entry.Status >= http.StatusBadRequest {
+				entry.Error = strings.TrimSpace(recorder.body.String())
+			}
+			if entry.Error == "" {
+				entry.After = _self.auditDocument(c)
+				if entry.After == nil && entry.Before == nil {
+					// Creates: the response is the new document.
+					entry.After = jsonObject(recorder.body.Bytes())
+				}
+			}
+			entry.FeatureName = auditFeature(c, entry)
+
+			if err := _self.AuditRepo.Create(c.Request().Context(), entry); err != nil {
+				slog.Warn("failed to record audit entry", "operation", entry.Operation, "error", err)
+			}
+			return err
+		}
+	}
+}
+
+// auditTarget lists the route parameters, e.g. "scenario_id=65f...".
+func auditTarget(c echo.Context) string {
+	var parts []string
+	for i, name := range c.ParamNames() {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, c.ParamValues()[i]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// auditDocument loads the document named by the route parameters, nil when
+// there is none (yet).
+func (_self *MockController) auditDocument(c echo.Context) bson.M {
+	ctx := c.Request().Context()
+	var (
+		doc any
+		err error
+	)
+	switch {
+	case c.Param("api_id") != "":
+		id, idErr := primitive.ObjectIDFromHex(c.Param("api_id"))
+		if idErr != nil {
+			return nil
+		}
+		if strings.Contains(c.Path(), "/grpc/") {
+			doc, err = _self.GRPCMockAPIRepo.FindByID(ctx, id)
+		} else {
+			doc, err = _self.MockAPIRepo.FindByObjectID(ctx, id)
+		}
+	case c.Param("scenario_id") != "":
+		id, idErr := primitive.ObjectIDFromHex(c.Param("scenario_id"))
+		if idErr != nil {
+			return nil
+		}
+		doc, err = _self.ScenarioRepo.GetByObjectID(ctx, id)
+	case c.Param("feature_id") != "":
+		id, idErr := primitive.ObjectIDFromHex(c.Param("feature_id"))
+		if idErr != nil {
+			return nil
+		}
+		doc, err = _self.FeatureRepo.FindById(ctx, id)
+	case c.Param("session_id") != "" && _self.SessionRepo != nil:
+		id, idErr := primitive.ObjectIDFromHex(c.Param("session_id"))
+		if idErr != nil {
+			return nil
+		}
+		doc, err = _self.SessionRepo.FindByID(ctx, id)
+	case c.Param("name") != "" && strings.Contains(c.Path(), "/account-groups/"):
+		doc, err = _self.AccountGroupRepo.FindByName(ctx, c.Param("name"))
+	case c.Param("name") != "" && strings.Contains(c.Path(), "/session-profiles/") && _self.SessionProfileRepo != nil:
+		doc, err = _self.SessionProfileRepo.FindByName(ctx, c.Param("name"))
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	// Some repositories return an empty document when nothing matches.
+	if id, ok := m["_id"].(primitive.ObjectID); ok && id.IsZero() {
+		return nil
+	}
+	return m
+}
+
+// auditFeature names the feature the change belongs to.
+func auditFeature(c echo.Context, entry *domain.AuditEntry) string {
+	for _, m := range []bson.M{entry.After, entry.Before, entry.Request} {
+		if feature, ok := m["feature_name"].(string); ok && feature != "" {
+			return feature
+		}
+	}
+	if c.Param("feature_id") != "" || strings.HasSuffix(c.Path(), "/features") {
+		for _, m := range []bson.M{entry.After, entry.Before, entry.Request} {
+			if name, ok := m["name"].(string); ok && name != "" {
+				return name
+			}
+		}
+	}
+	return c.QueryParam("feature_name")
+}
+
+// jsonObject decodes a JSON object, nil for anything else.
+func jsonObject(raw []byte) bson.M {
+	var m bson.M
+	if err := bson.UnmarshalExtJSON(raw, false, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+/* ---------- GET /audit ---------- */
+
+func (_self *MockController) ListAuditLog(c echo.Context) error {
+	filter := domain.AuditFilter{
+		FeatureName: c.QueryParam("feature_name"),
+		Actor:       c.QueryParam("actor"),
+		Source:      c.QueryParam("source"),
+	}
+	for param, out := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.QueryParam(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, param+" must be an RFC3339 time")
+			}
+			*out = t
+		}
+	}
+
+	params := parsePaginationParams(c)
+	entries, total, err := _self.AuditRepo.List(c.Request().Context(), filter, params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, domain.NewPaginatedResponse(entries, total, params))
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+/* ---------- GET /features/:feature_id/export ---------- */
+
+func (_self *MockController) ExportFeature(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := primitive.ObjectIDFromHex(c.Param("feature_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid feature_id")
+	}
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "yml" {
+		format = usecase.BundleFormatYAML
+	}
+	feature, _ := _self.FeatureRepo.FindById(ctx, id)
+	if feature == nil || feature.Name == "" {
+		return echo.NewHTTPError(http.StatusNotFound, "feature not found")
+	}
+
+	var scenarios []string
+	for _, name := range strings.Split(c.QueryParam("scenarios"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			scenarios = append(scenarios, name)
+		}
+	}
+	bundle, err := _self.Bundles.Export(ctx, feature.Name, scenarios)
+	if err != nil {
+		return bundleHTTPError(err)
+	}
+	data, err := usecase.EncodeBundle(bundle, format)
+	if err != nil {
+		return bundleHTTPError(err)
+	}
+
+	contentType, ext := echo.MIMEApplicationJSON, usecase.BundleFormatJSON
+	if format == usecase.BundleFormatYAML {
+		contentType, ext = "application/yaml", usecase.BundleFormatYAML
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", feature.Name+"."+ext))
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+/* ---------- POST /import ---------- */
+
+// ImportBundle reads a YAML or JSON bundle from the request body.
+func (_self *MockController) ImportBundle(c echo.Context) error {
+	ctx := c.Request().Context()
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+	data, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	bundle, err := usecase.DecodeBundle(data)
+	if err != nil {
+		return bundleHTTPError(err)
+	}
+
+	result, err := _self.Bundles.Import(ctx, bundle, dryRun, revisionAuthor(c))
+	if err != nil {
+		return bundleHTTPError(err)
+	}
+	grpcChanged := slices.ContainsFunc(result.Changes, func(change domain.BundleChange) bool {
+		return change.Kind == domain.BundleKindGRPCMockAPI
+	})
+	if !result.DryRun && grpcChanged && _self.GRPCServiceIndex != nil {
+		_ = _self.GRPCServiceIndex.Refresh(ctx)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func bundleHTTPError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBundle):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrBundleNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrBundleConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
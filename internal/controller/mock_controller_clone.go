@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+/* ---------- POST /features/:feature_id/clone ---------- */
+
+func (_self *MockController) CloneFeature(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := primitive.ObjectIDFromHex(c.Param("feature_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid feature_id")
+	}
+	var req struct {
+		Name       string `json:"name" validate:"required,no_spaces"`
+		NamePrefix string `json:"name_prefix" validate:"no_spaces"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	feature, _ := _self.FeatureRepo.FindById(ctx, id)
+	if feature == nil || feature.Name == "" {
+		return echo.NewHTTPError(http.StatusNotFound, "feature not found")
+	}
+
+	result, err := _self.Clones.CloneFeature(ctx, feature.Name, req.Name, usecase.CloneOptions{NamePrefix: req.NamePrefix})
+	if err != nil {
+		return cloneHTTPError(err)
+	}
+	_self.refreshGRPCIndex(c, result)
+	return c.JSON(http.StatusCreated, result)
+}
+
+/* ---------- POST /scenarios/:scenario_id/clone ---------- */
+
+func (_self *MockController) CloneScenario(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := primitive.ObjectIDFromHex(c.Param("scenario_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid scenario_id")
+	}
+	var req struct {
+		FeatureName string `json:"feature_name" validate:"no_spaces"` // target feature, the scenario's own by default
+		Name        string `json:"name" validate:"no_spaces"`
+		NamePrefix  string `json:"name_prefix" validate:"no_spaces"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	scenario, err := _self.ScenarioRepo.GetByObjectID(ctx, id)
+	if err != nil || scenario == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "scenario not found")
+	}
+
+	result, err := _self.Clones.CloneScenario(
+		ctx, scenario.FeatureName, scenario.Name, req.FeatureName, req.Name,
+		usecase.CloneOptions{NamePrefix: req.NamePrefix},
+	)
+	if err != nil {
+		return cloneHTTPError(err)
+	}
+	_self.refreshGRPCIndex(c, result)
+	return c.JSON(http.StatusCreated, result)
+}
+
+// refreshGRPCIndex makes the copied gRPC mocks servable right away instead of
+// at the next periodic refresh.
+func (_self *MockController) refreshGRPCIndex(c echo.Context, result *usecase.CloneResult) {
+	if result.GRPCMockAPIs > 0 && _self.GRPCServiceIndex != nil {
+		_ = _self.GRPCServiceIndex.Refresh(c.Request().Context())
+	}
+}
+
+func cloneHTTPError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidClone):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrCloneSourceNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrCloneConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
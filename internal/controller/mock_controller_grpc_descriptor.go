@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+/* ---------- gRPC descriptors ---------- */
+
+// UploadGRPCDescriptor accepts .proto sources or a FileDescriptorSet, either
+// as JSON (entity.GRPCDescriptorUploadRequest) or as multipart files. Files
+// ending in .proto are compiled; any other file is read as a binary
+// FileDescriptorSet.
+func (_self *MockController) UploadGRPCDescriptor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req entity.GRPCDescriptorUploadRequest
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		req.Files = map[string]string{}
+		for _, headers := range form.File {
+			for _, fh := range headers {
+				f, err := fh.Open()
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+				}
+				content, err := io.ReadAll(f)
+				f.Close()
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+				}
+				if strings.EqualFold(path.Ext(fh.Filename), ".proto") {
+					req.Files[fh.Filename] = string(content)
+				} else {
+					req.DescriptorSet = content
+				}
+			}
+		}
+	} else if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var (
+		set    *descriptorpb.FileDescriptorSet
+		source string
+	)
+	switch {
+	case len(req.DescriptorSet) > 0:
+		set = &descriptorpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(req.DescriptorSet, set); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid descriptor_set: "+err.Error())
+		}
+		source = domain.GRPCDescriptorSourceDescriptorSet
+	case len(req.Files) > 0:
+		compiled, err := usecase.CompileProtoFiles(ctx, req.Files)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		set = compiled
+		source = domain.GRPCDescriptorSourceProto
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "files or descriptor_set is required")
+	}
+
+	services, err := _self.GRPCDescriptors.Register(ctx, set, source)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]any{"services": services})
+}
+
+func (_self *MockController) ListGRPCDescriptors(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docs, err := _self.GRPCDescriptors.List(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	result := make([]entity.GRPCDescriptorResponse, 0, len(docs))
+	for _, d := range docs {
+		var methods []string
+		if svc := _self.GRPCDescriptors.FindService(d.ServiceName); svc != nil {
+			for i := 0; i < svc.Methods().Len(); i++ {
+				methods = append(methods, string(svc.Methods().Get(i).Name()))
+			}
+		}
+		result = append(result, entity.GRPCDescriptorResponse{
+			ServiceName: d.ServiceName,
+			Source:      d.Source,
+			Files:       d.Files,
+			Methods:     methods,
+			UpdatedAt:   d.UpdatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (_self *MockController) DeleteGRPCDescriptor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	serviceName := c.Param("service_name")
+	if serviceName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "service_name is required")
+	}
+	if err := _self.GRPCDescriptors.Delete(ctx, serviceName); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+/* ---------- gRPC field matchers ---------- */
+
+func buildGRPCMatchers(req entity.GRPCMockAPIRequest) ([]domain.GRPCFieldMatcher, error) {
+	if len(req.Matchers) == 0 {
+		return nil, nil
+	}
+	out := make([]domain.GRPCFieldMatcher, 0, len(req.Matchers))
+	for i, m := range req.Matchers {
+		value, err := matcherValue(m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("matchers[%d].value: %w", i, err)
+		}
+		out = append(out, domain.GRPCFieldMatcher{
+			Path:  m.Path,
+			Op:    m.Op,
+			Value: value,
+			Min:   m.Min,
+			Max:   m.Max,
+		})
+	}
+	if err := usecase.ValidateGRPCMatchers(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// matcherValue stores JSON strings unquoted and any other JSON value as
+// written, which is the form the request field is compared in.
+func matcherValue(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func grpcMatchersToJSON(matchers []domain.GRPCFieldMatcher) []entity.GRPCFieldMatcherRequest {
+	if len(matchers) == 0 {
+		return nil
+	}
+	out := make([]entity.GRPCFieldMatcherRequest, 0, len(matchers))
+	for _, m := range matchers {
+		var value json.RawMessage
+		if m.Value != "" {
+			value, _ = json.Marshal(m.Value)
+		}
+		out = append(out, entity.GRPCFieldMatcherRequest{
+			Path:  m.Path,
+			Op:    m.Op,
+			Value: value,
+			Min:   m.Min,
+			Max:   m.Max,
+		})
+	}
+	return out
+}
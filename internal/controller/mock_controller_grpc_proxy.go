@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+)
+
+/* ---------- gRPC proxies ---------- */
+
+func (_self *MockController) ListGRPCProxies(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	proxies, err := _self.GRPCProxy.List(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if proxies == nil {
+		proxies = []domain.GRPCProxy{}
+	}
+	return c.JSON(http.StatusOK, proxies)
+}
+
+// SaveGRPCProxy creates or replaces the proxy of a service.
+func (_self *MockController) SaveGRPCProxy(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req entity.GRPCProxyRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	req.ServiceName = strings.TrimSpace(req.ServiceName)
+	req.Upstream = strings.TrimSpace(req.Upstream)
+	if req.ServiceName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "service_name is required")
+	}
+	if req.Upstream == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "upstream is required")
+	}
+	if req.Record && (req.RecordFeature == "" || req.RecordScenario == "") {
+		return echo.NewHTTPError(http.StatusBadRequest, "record_feature and record_scenario are required to record")
+	}
+
+	proxy := &domain.GRPCProxy{
+		ServiceName:    req.ServiceName,
+		Upstream:       req.Upstream,
+		TLS:            req.TLS,
+		Record:         req.Record,
+		RecordFeature:  req.RecordFeature,
+		RecordScenario: req.RecordScenario,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+	}
+	if err := _self.GRPCProxy.Save(ctx, proxy); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, proxy)
+}
+
+func (_self *MockController) DeleteGRPCProxy(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	serviceName := c.Param("service_name")
+	if serviceName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "service_name is required")
+	}
+	if err := _self.GRPCProxy.Delete(ctx, serviceName); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+)
+
+/* ---------- gRPC sequence responses ---------- */
+
+func buildGRPCSequence(req entity.GRPCMockAPIRequest) ([]domain.GRPCSequenceResponse, error) {
+	if len(req.Sequence) == 0 {
+		return nil, nil
+	}
+	if req.StreamType != domain.GRPCStreamUnary {
+		return nil, fmt.Errorf("sequence is only supported for unary mocks")
+	}
+	out := make([]domain.GRPCSequenceResponse, 0, len(req.Sequence))
+	for i, step := range req.Sequence {
+		if step.From < 1 || (step.To != 0 && step.To <= step.From) {
+			return nil, fmt.Errorf("sequence[%d]: from must be >= 1 and to must be 0 or greater than from", i)
+		}
+		output, err := jsonToBSON(step.Output)
+		if err != nil {
+			return nil, fmt.Errorf("sequence[%d].output: %w", i, err)
+		}
+		out = append(out, domain.GRPCSequenceResponse{
+			From:          step.From,
+			To:            step.To,
+			StatusCode:    step.StatusCode,
+			StatusMessage: step.StatusMessage,
+			Output:        output,
+			Latency:       step.Latency,
+		})
+	}
+	return out, nil
+}
+
+func grpcSequenceToJSON(steps []domain.GRPCSequenceResponse) []entity.GRPCSequenceResponseRequest {
+	if len(steps) == 0 {
+		return nil
+	}
+	out := make([]entity.GRPCSequenceResponseRequest, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, entity.GRPCSequenceResponseRequest{
+			From:          step.From,
+			To:            step.To,
+			StatusCode:    step.StatusCode,
+			StatusMessage: step.StatusMessage,
+			Output:        bsonToJSON(step.Output),
+			Latency:       step.Latency,
+		})
+	}
+	return out
+}
+
+func (_self *MockController) ResetGRPCSequenceCounter(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	idStr := c.Param("api_id")
+	id, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid api_id")
+	}
+
+	mock, err := _self.GRPCMockAPIRepo.FindByID(ctx, id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "gRPC mock not found")
+	}
+
+	// Delete the counters of every account and request hash of this method
+	pattern := fmt.Sprintf("mocktool:grpcseq:%s:%s:*:%s:%s:*",
+		mock.FeatureName,
+		mock.ScenarioName,
+		mock.ServiceName,
+		mock.MethodName,
+	)
+	_self.cacheRepo.InvalidAllKey(ctx, pattern)
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "counter reset successfully"})
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+/* ---------- gRPC response metadata and status details ---------- */
+
+// buildGRPCResponseMeta copies headers, trailers, status message and status
+// details of req into m. Details are resolved once here so a typo in a type
+// name is reported when the mock is saved rather than when it is called.
+func (_self *MockController) buildGRPCResponseMeta(req entity.GRPCMockAPIRequest, m *domain.GRPCMockAPI) error {
+	headers, err := normalizeGRPCMetadata(req.Headers)
+	if err != nil {
+		return fmt.Errorf("headers: %w", err)
+	}
+	trailers, err := normalizeGRPCMetadata(req.Trailers)
+	if err != nil {
+		return fmt.Errorf("trailers: %w", err)
+	}
+
+	details := make([]domain.GRPCStatusDetail, 0, len(req.Details))
+	for i, d := range req.Details {
+		if d.Type == "" {
+			return fmt.Errorf("details[%d].type is required", i)
+		}
+		value, err := jsonToBSON(d.Value)
+		if err != nil {
+			return fmt.Errorf("details[%d].value: %w", i, err)
+		}
+		details = append(details, domain.GRPCStatusDetail{Type: d.Type, Value: value})
+	}
+	if len(details) > 0 {
+		if _, err := usecase.BuildGRPCStatus(_self.GRPCDescriptors, codes.Code(req.StatusCode), req.StatusMessage, details); err != nil {
+			return err
+		}
+	}
+
+	m.Headers = headers
+	m.Trailers = trailers
+	m.StatusMessage = req.StatusMessage
+	if len(details) > 0 {
+		m.Details = details
+	}
+	return nil
+}
+
+// normalizeGRPCMetadata lower-cases keys and rejects the ones gRPC reserves.
+// Values of binary keys (ending in -bin) must be base64.
+func normalizeGRPCMetadata(in map[string]string) (map[string]string, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		key := strings.ToLower(strings.TrimSpace(k))
+		if key == "" || strings.HasPrefix(key, "grpc-") || strings.HasPrefix(key, ":") || key == "content-type" {
+			return nil, fmt.Errorf("reserved or empty key %q", k)
+		}
+		if strings.HasSuffix(key, "-bin") {
+			if _, err := base64.StdEncoding.DecodeString(v); err != nil {
+				return nil, fmt.Errorf("value of %q must be base64", k)
+			}
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+func statusDetailsToJSON(details []domain.GRPCStatusDetail) []entity.GRPCStatusDetailRequest {
+	if len(details) == 0 {
+		return nil
+	}
+	out := make([]entity.GRPCStatusDetailRequest, 0, len(details))
+	for _, d := range details {
+		out = append(out, entity.GRPCStatusDetailRequest{Type: d.Type, Value: bsonToJSON(d.Value)})
+	}
+	return out
+}
+
+// grpcResponseMetaUpdate returns the update fields for the metadata and
+// status details present in an update request.
+func (_self *MockController) grpcResponseMetaUpdate(req entity.GRPCMockAPIRequest) (bson.M, error) {
+	meta := &domain.GRPCMockAPI{}
+	if err := _self.buildGRPCResponseMeta(req, meta); err != nil {
+		return nil, err
+	}
+	update := bson.M{}
+	if req.Headers != nil {
+		update["headers"] = meta.Headers
+	}
+	if req.Trailers != nil {
+		update["trailers"] = meta.Trailers
+	}
+	if req.StatusMessage != "" {
+		update["status_message"] = meta.StatusMessage
+	}
+	if req.Details != nil {
+		update["details"] = meta.Details
+	}
+	return update, nil
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+/* ---------- gRPC stream mocks ---------- */
+
+// buildGRPCStreamFields copies the streaming part of req into m.
+func buildGRPCStreamFields(req entity.GRPCMockAPIRequest, m *domain.GRPCMockAPI) error {
+	switch req.StreamType {
+	case domain.GRPCStreamUnary:
+		return nil
+	case domain.GRPCStreamServer:
+		msgs, err := streamMessagesToBSON(req.Responses)
+		if err != nil {
+			return err
+		}
+		m.Responses = msgs
+	case domain.GRPCStreamClient:
+		switch req.Aggregate {
+		case "", domain.GRPCAggregateLast, domain.GRPCAggregateFirst, domain.GRPCAggregateMerge,
+			domain.GRPCAggregateAll, domain.GRPCAggregateCount:
+		default:
+			return fmt.Errorf("unknown aggregate %q", req.Aggregate)
+		}
+		m.Aggregate = req.Aggregate
+	case domain.GRPCStreamBidi:
+		if len(req.BidiRules) == 0 {
+			return fmt.Errorf("bidi_rules is required for bidi mocks")
+		}
+		for i, r := range req.BidiRules {
+			rule := domain.GRPCBidiRule{}
+			if input, err := jsonToBSON(r.Input); err != nil {
+				return fmt.Errorf("bidi_rules[%d].input: %w", i, err)
+			} else if input != nil {
+				rule.Input = input
+				rule.HashInput = utils.GenerateHashFromInput(input)
+			}
+			msgs, err := streamMessagesToBSON(r.Responses)
+			if err != nil {
+				return fmt.Errorf("bidi_rules[%d]: %w", i, err)
+			}
+			rule.Responses = msgs
+			m.BidiRules = append(m.BidiRules, rule)
+		}
+	default:
+		return fmt.Errorf("unknown stream_type %q", req.StreamType)
+	}
+	m.StreamType = req.StreamType
+	return nil
+}
+
+func streamMessagesToBSON(msgs []entity.GRPCStreamMessageRequest) ([]domain.GRPCStreamMessage, error) {
+	out := make([]domain.GRPCStreamMessage, 0, len(msgs))
+	for i, msg := range msgs {
+		output, err := jsonToBSON(msg.Output)
+		if err != nil {
+			return nil, fmt.Errorf("responses[%d].output: %w", i, err)
+		}
+		if msg.Delay < 0 {
+			return nil, fmt.Errorf("responses[%d].delay must not be negative", i)
+		}
+		out = append(out, domain.GRPCStreamMessage{Output: output, Delay: msg.Delay})
+	}
+	return out, nil
+}
+
+func streamMessagesToJSON(msgs []domain.GRPCStreamMessage) []entity.GRPCStreamMessageRequest {
+	if len(msgs) == 0 {
+		return nil
+	}
+	out := make([]entity.GRPCStreamMessageRequest, 0, len(msgs))
+	for _, msg := range msgs {
+		out = append(out, entity.GRPCStreamMessageRequest{Output: bsonToJSON(msg.Output), Delay: msg.Delay})
+	}
+	return out
+}
+
+func bidiRulesToJSON(rules []domain.GRPCBidiRule) []entity.GRPCBidiRuleRequest {
+	if len(rules) == 0 {
+		return nil
+	}
+	out := make([]entity.GRPCBidiRuleRequest, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, entity.GRPCBidiRuleRequest{
+			Input:     bsonToJSON(r.Input),
+			Responses: streamMessagesToJSON(r.Responses),
+		})
+	}
+	return out
+}
+
+// jsonToBSON converts a JSON object (or a JSON string holding one) to BSON.
+// Empty and null values return nil.
+func jsonToBSON(raw json.RawMessage) (bson.Raw, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	if str, ok := data.(string); ok {
+		if err := json.Unmarshal([]byte(str), &data); err != nil {
+			return nil, err
+		}
+	}
+	return bson.Marshal(data)
+}
+
+func bsonToJSON(raw bson.Raw) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	out, _ := json.Marshal(m)
+	return out
+}
@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+// renameHTTPError maps the errors of a feature or scenario rename, made by
+// UpdateFeature and UpdateScenarioByFeature when the name changes.
+func renameHTTPError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidRename):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrRenameNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrRenameConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
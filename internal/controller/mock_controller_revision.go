@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+// revisionAuthorHeader names who makes a change; the client IP is recorded
+// when it is missing.
+const revisionAuthorHeader = "X-User"
+
+// revisionResources are the documents with a revision log, by route prefix
+// and id parameter.
+var revisionResources = []struct {
+	prefix string
+	param  string
+	kind   string
+}{
+	{"/mockapis/:api_id", "api_id", domain.RevisionKindMockAPI},
+	{"/grpc/apis/:api_id", "api_id", domain.RevisionKindGRPCMockAPI},
+	{"/scenarios/:scenario_id", "scenario_id", domain.RevisionKindScenario},
+}
+
+func (_self *MockController) registerRevisionRoutes(v1 *echo.Group) {
+	for _, r := range revisionResources {
+		v1.GET(r.prefix+"/revisions", _self.listRevisions(r.kind, r.param))
+		v1.GET(r.prefix+"/revisions/diff", _self.diffRevisions(r.kind, r.param))
+		v1.POST(r.prefix+"/revisions/:version/restore", _self.restoreRevision(r.kind, r.param))
+	}
+}
+
+func revisionAuthor(c echo.Context) string {
+	if user := strings.TrimSpace(c.Request().Header.Get(revisionAuthorHeader)); user != "" {
+		return user
+	}
+	return c.RealIP()
+}
+
+// recordRevision snapshots a document after it changed, or before it is
+// deleted. Failures are only logged: the change itself already succeeded.
+func (_self *MockController) recordRevision(c echo.Context, kind string, id primitive.ObjectID, action string) {
+	if _self.Revisions == nil {
+		return
+	}
+	if err := _self.Revisions.Record(c.Request().Context(), kind, id, action, revisionAuthor(c)); err != nil {
+		slog.Warn("failed to record revision", "kind", kind, "id", id.Hex(), "action", action, "error", err)
+	}
+}
+
+// recordDeletedRevision snapshots doc once its delete succeeded.
+func (_self *MockController) recordDeletedRevision(c echo.Context, kind string, id primitive.ObjectID, doc any) {
+	if _self.Revisions == nil {
+		return
+	}
+	if err := _self.Revisions.RecordDeleted(c.Request().Context(), kind, id, doc, revisionAuthor(c)); err != nil {
+		slog.Warn("failed to record revision", "kind", kind, "id", id.Hex(), "action", domain.RevisionActionDelete, "error", err)
+	}
+}
+
+/* ---------- GET /<resource>/:id/revisions ---------- */
+
+func (_self *MockController) listRevisions(kind, param string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := primitive.ObjectIDFromHex(c.Param(param))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid "+param)
+		}
+		params := parsePaginationParams(c)
+		revisions, total, err := _self.Revisions.List(c.Request().Context(), kind, id, params)
+		if err != nil {
+			return revisionHTTPError(err)
+		}
+		return c.JSON(http.StatusOK, domain.NewPaginatedResponse(revisions, total, params))
+	}
+}
+
+/* ---------- GET /<resource>/:id/revisions/diff?from=&to= ---------- */
+
+func (_self *MockController) diffRevisions(kind, param string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := primitive.ObjectIDFromHex(c.Param(param))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid "+param)
+		}
+		var from, to int
+		if v := c.QueryParam("from"); v != "" {
+			if from, err = strconv.Atoi(v); err != nil || from < 1 {
+				return echo.NewHTTPError(http.StatusBadRequest, "from must be a revision number")
+			}
+		}
+		if v := c.QueryParam("to"); v != "" {
+			if to, err = strconv.Atoi(v); err != nil || to < 1 {
+				return echo.NewHTTPError(http.StatusBadRequest, "to must be a revision number")
+			}
+		}
+		diff, err := _self.Revisions.Diff(c.Request().Context(), kind, id, from, to)
+		if err != nil {
+			return revisionHTTPError(err)
+		}
+		return c.JSON(http.StatusOK, diff)
+	}
+}
+
+/* ---------- POST /<resource>/:id/revisions/:version/restore ---------- */
+
+func (_self *MockController) restoreRevision(kind, param string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := primitive.ObjectIDFromHex(c.Param(param))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid "+param)
+		}
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid version")
+		}
+		rev, err := _self.Revisions.Restore(ctx, kind, id, version, revisionAuthor(c))
+		if err != nil {
+			return revisionHTTPError(err)
+		}
+		if kind == domain.RevisionKindGRPCMockAPI && _self.GRPCServiceIndex != nil {
+			if restored, err := _self.GRPCMockAPIRepo.FindByID(ctx, id); err == nil {
+				_self.GRPCServiceIndex.Put(restored)
+			}
+		}
+		return c.JSON(http.StatusOK, rev)
+	}
+}
+
+func revisionHTTPError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrUnknownRevisionKind), errors.Is(err, usecase.ErrRevisionNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrRevisionConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
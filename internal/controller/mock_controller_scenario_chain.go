@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+// Origins of the mocks listed by ListEffectiveMockAPIs.
+const (
+	mockOriginOwn        = "own"        // defined in the scenario only
+	mockOriginOverridden = "overridden" // defined in the scenario, hiding a parent mock
+	mockOriginInherited  = "inherited"  // served from a parent scenario
+)
+
+/* ---------- GET /scenarios/:scenario_id/effective-mockapis ---------- */
+
+// ListEffectiveMockAPIs lists the active HTTP and gRPC mocks a scenario
+// serves, its own and the inherited ones. A mock hides the mocks of its
+// parents with the same method, path (or service) and request hash.
+func (_self *MockController) ListEffectiveMockAPIs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	scenarioID, err := primitive.ObjectIDFromHex(c.Param("scenario_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid scenario_id")
+	}
+	scenario, err := _self.ScenarioRepo.GetByObjectID(ctx, scenarioID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "scenario not found")
+	}
+	chain, err := usecase.ScenarioChain(ctx, _self.ScenarioRepo, scenario)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	names := make([]string, 0, len(chain))
+	for _, s := range chain {
+		names = append(names, s.Name)
+	}
+
+	apis, err := _self.MockAPIRepo.ListActiveAPIsByScenario(ctx, names)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	httpLayers := make([][]map[string]any, len(chain))
+	for _, api := range apis {
+		level := chainLevel(names, api.ScenarioName)
+		if api.FeatureName != scenario.FeatureName || level < 0 {
+			continue
+		}
+		httpLayers[level] = append(httpLayers[level], map[string]any{
+			"key":           api.Method + " " + api.Path + " " + api.HashInput,
+			"id":            api.ID.Hex(),
+			"name":          api.Name,
+			"scenario_name": api.ScenarioName,
+			"method":        api.Method,
+			"path":          api.Path,
+			"status_code":   api.StatusCode,
+		})
+	}
+
+	grpcLayers := make([][]map[string]any, len(chain))
+	if _self.GRPCMockAPIRepo != nil {
+		for level, name := range names {
+			mocks, err := _self.GRPCMockAPIRepo.ListByFeatureAndScenario(ctx, scenario.FeatureName, name)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+			for _, mock := range mocks {
+				if !mock.IsActive {
+					continue
+				}
+				grpcLayers[level] = append(grpcLayers[level], map[string]any{
+					"key":           mock.ServiceName + "/" + mock.MethodName + " " + mock.HashInput,
+					"id":            mock.ID.Hex(),
+					"scenario_name": mock.ScenarioName,
+					"service_name":  mock.ServiceName,
+					"method_name":   mock.MethodName,
+					"status_code":   mock.StatusCode,
+				})
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"scenario":       scenario,
+		"chain":          names,
+		"mock_apis":      mergeMockLayers(httpLayers),
+		"grpc_mock_apis": mergeMockLayers(grpcLayers),
+	})
+}
+
+// chainLevel returns the position of name in the chain, -1 when absent.
+func chainLevel(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeMockLayers keeps the nearest mock of every key, layers[0] being the
+// scenario itself, and sets its origin.
+func mergeMockLayers(layers [][]map[string]any) []map[string]any {
+	seen := make(map[string]bool)
+	shadowed := make(map[string]string) // key -> nearest parent scenario defining it
+	for level := len(layers) - 1; level > 0; level-- {
+		for _, mock := range layers[level] {
+			shadowed[mock["key"].(string)] = mock["scenario_name"].(string)
+		}
+	}
+
+	result := []map[string]any{}
+	for level, layer := range layers {
+		for _, mock := range layer {
+			key := mock["key"].(string)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			delete(mock, "key")
+			switch {
+			case level > 0:
+				mock["origin"] = mockOriginInherited
+			case shadowed[key] != "":
+				mock["origin"] = mockOriginOverridden
+				mock["overrides"] = shadowed[key]
+			default:
+				mock["origin"] = mockOriginOwn
+			}
+			result = append(result, mock)
+		}
+	}
+	return result
+}
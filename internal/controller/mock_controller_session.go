@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/entity"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+/* ---------- Session profiles ---------- */
+
+func (_self *MockController) ListSessionProfiles(c echo.Context) error {
+	profiles, err := _self.SessionProfileRepo.ListAll(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if profiles == nil {
+		profiles = []domain.SessionProfile{}
+	}
+	return c.JSON(http.StatusOK, profiles)
+}
+
+// SaveSessionProfile creates or replaces a profile. Sessions already applied
+// keep the scenarios they were applied with.
+func (_self *MockController) SaveSessionProfile(c echo.Context) error {
+	var req entity.SessionProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	profile := &domain.SessionProfile{
+		Name:        req.Name,
+		Description: req.Description,
+		Entries:     make([]domain.SessionProfileEntry, 0, len(req.Entries)),
+	}
+	for _, e := range req.Entries {
+		profile.Entries = append(profile.Entries, domain.SessionProfileEntry{
+			FeatureName:  strings.TrimSpace(e.FeatureName),
+			ScenarioName: strings.TrimSpace(e.ScenarioName),
+		})
+	}
+	if err := _self.Sessions.SaveProfile(c.Request().Context(), profile); err != nil {
+		return sessionHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (_self *MockController) DeleteSessionProfile(c echo.Context) error {
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+	if err := _self.SessionProfileRepo.DeleteByName(c.Request().Context(), name); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+/* ---------- POST /session-profiles/:name/apply ---------- */
+
+func (_self *MockController) ApplySessionProfile(c echo.Context) error {
+	var req entity.ApplySessionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	var duration time.Duration
+	if req.Duration != "" {
+		var err error
+		if duration, err = time.ParseDuration(req.Duration); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid duration: "+err.Error())
+		}
+	}
+	session, err := _self.Sessions.Apply(c.Request().Context(), c.Param("name"), req.AccountId, duration)
+	if err != nil {
+		return sessionHTTPError(err)
+	}
+	return c.JSON(http.StatusCreated, session)
+}
+
+/* ---------- Sessions ---------- */
+
+func (_self *MockController) ListSessions(c echo.Context) error {
+	sessions, err := _self.SessionRepo.ListAll(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if sessions == nil {
+		sessions = []domain.Session{}
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// RevertSession ends a session: the account gets its previous scenarios back.
+func (_self *MockController) RevertSession(c echo.Context) error {
+	id, err := primitive.ObjectIDFromHex(c.Param("session_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid session_id")
+	}
+	session, err := _self.Sessions.Revert(c.Request().Context(), id)
+	if err != nil {
+		return sessionHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, session)
+}
+
+func sessionHTTPError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSessionProfile):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrSessionProfileNotFound), errors.Is(err, usecase.ErrSessionNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrSessionConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/namnv2496/mocktool/internal/configs"
@@ -13,8 +15,11 @@ import (
 	"github.com/namnv2496/mocktool/internal/usecase"
 	controllerMocks "github.com/namnv2496/mocktool/mocks/controller"
 	repositoryMocks "github.com/namnv2496/mocktool/mocks/repository"
+	usecaseMocks "github.com/namnv2496/mocktool/mocks/usecase"
 	customValidator "github.com/namnv2496/mocktool/pkg/validator"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/mock/gomock"
 )
@@ -43,12 +48,26 @@ func setupTestController(t *testing.T) (*MockController, *gomock.Controller, *re
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo not needed in unit tests
+		nil, // grpcDescriptors not needed in unit tests
+		nil, // grpcServiceIndex not needed in unit tests
+		nil, // grpcProxy not needed in unit tests
+		nil, // revisions not needed in unit tests
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
-		nil,                    // chatHandler not needed in unit tests
+		nil,                     // chatHandler not needed in unit tests
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	).(*MockController)
 
 	return controller, ctrl, featureRepo, scenarioRepo, accountScenarioRepo, mockAPIRepo
@@ -400,6 +419,505 @@ func TestMockController_ActivateScenario(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestMockController_ActivateScenarioTimeBoxed(t *testing.T) {
+	controller, ctrl, _, scenarioRepo, accountScenarioRepo, _ := setupTestController(t)
+	defer ctrl.Finish()
+
+	scenarioID := primitive.NewObjectID()
+	scenarioRepo.EXPECT().
+		GetByObjectID(gomock.Any(), scenarioID).
+		Return(&domain.Scenario{ID: scenarioID, FeatureName: "test-feature", Name: "error_500"}, nil).
+		Times(2)
+
+	// The current activation is kept: it takes over again after 30 minutes.
+	var created *domain.AccountScenario
+	accountScenarioRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, as *domain.AccountScenario) error {
+			created = as
+			return nil
+		})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/mocktool/scenarios/"+scenarioID.Hex()+"/activate?account_id=qa-1",
+		strings.NewReader(`{"duration": "30m"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("scenario_id")
+	c.SetParamValues(scenarioID.Hex())
+
+	before := time.Now()
+	require.NoError(t, controller.ActivateScenario(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, created)
+	assert.Equal(t, "qa-1", *created.AccountId)
+	assert.Nil(t, created.StartsAt)
+	require.NotNil(t, created.ExpiresAt)
+	assert.WithinDuration(t, before.Add(30*time.Minute), *created.ExpiresAt, 5*time.Second)
+	assert.Contains(t, rec.Body.String(), "expires_at")
+
+	// An expiry in the past is rejected.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/mocktool/scenarios/"+scenarioID.Hex()+"/activate",
+		strings.NewReader(`{"expires_at": "2020-01-01T00:00:00Z"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c = e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("scenario_id")
+	c.SetParamValues(scenarioID.Hex())
+	err := controller.ActivateScenario(c)
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestMockController_ActivateScenarioCohort(t *testing.T) {
+	controller, ctrl, _, scenarioRepo, accountScenarioRepo, _ := setupTestController(t)
+	defer ctrl.Finish()
+
+	scenarioID := primitive.NewObjectID()
+	scenarioRepo.EXPECT().
+		GetByObjectID(gomock.Any(), scenarioID).
+		Return(&domain.Scenario{ID: scenarioID, FeatureName: "test-feature", Name: "new_checkout"}, nil).
+		Times(2)
+
+	// Only the previous rollout is replaced; account and global mappings stay.
+	accountScenarioRepo.EXPECT().
+		DeactivateByTarget(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, as *domain.AccountScenario) error {
+			assert.Equal(t, domain.ActivationTargetPercentage, as.Target)
+			return nil
+		})
+	var created *domain.AccountScenario
+	accountScenarioRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, as *domain.AccountScenario) error {
+			created = as
+			return nil
+		})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/mocktool/scenarios/"+scenarioID.Hex()+"/activate",
+		strings.NewReader(`{"target": "percentage", "percentage": 25}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("scenario_id")
+	c.SetParamValues(scenarioID.Hex())
+
+	require.NoError(t, controller.ActivateScenario(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, created)
+	assert.Nil(t, created.AccountId)
+	assert.Equal(t, 25, created.Percentage)
+
+	// A cohort cannot be combined with account_id.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/mocktool/scenarios/"+scenarioID.Hex()+"/activate?account_id=qa-1",
+		strings.NewReader(`{"target": "group", "group_name": "qa"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c = e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("scenario_id")
+	c.SetParamValues(scenarioID.Hex())
+	err := controller.ActivateScenario(c)
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestMockController_SaveAccountGroup(t *testing.T) {
+	controller, ctrl, _, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	groupRepo := repositoryMocks.NewMockIAccountGroupRepository(ctrl)
+	controller.AccountGroupRepo = groupRepo
+
+	groupRepo.EXPECT().
+		Upsert(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, g *domain.AccountGroup) error {
+			assert.Equal(t, "qa", g.Name)
+			assert.Equal(t, []string{"qa-1", "qa-2"}, g.AccountIds)
+			return nil
+		})
+
+	e := echo.New()
+	e.Validator = customValidator.NewValidator()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/mocktool/account-groups",
+		strings.NewReader(`{"name": "qa", "account_ids": ["qa-1", " qa-2 ", "qa-1", ""]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, controller.SaveAccountGroup(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/mocktool/account-groups", strings.NewReader(`{"account_ids": ["qa-1"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assert.Error(t, controller.SaveAccountGroup(e.NewContext(req, httptest.NewRecorder())))
+}
+
+func TestMockController_ListEffectiveMockAPIs(t *testing.T) {
+	controller, ctrl, _, scenarioRepo, _, mockAPIRepo := setupTestController(t)
+	defer ctrl.Finish()
+
+	base := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "base"}
+	child := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "child", ParentID: &base.ID}
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), child.ID).Return(child, nil)
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), base.ID).Return(base, nil)
+	mockAPIRepo.EXPECT().
+		ListActiveAPIsByScenario(gomock.Any(), []string{"child", "base"}).
+		Return([]domain.MockAPI{
+			{ID: primitive.NewObjectID(), FeatureName: "f", ScenarioName: "child", Name: "login-fail", Path: "/login", Method: "POST"},
+			{ID: primitive.NewObjectID(), FeatureName: "f", ScenarioName: "child", Name: "logout", Path: "/logout", Method: "POST"},
+			{ID: primitive.NewObjectID(), FeatureName: "f", ScenarioName: "base", Name: "login", Path: "/login", Method: "POST"},
+			{ID: primitive.NewObjectID(), FeatureName: "f", ScenarioName: "base", Name: "profile", Path: "/profile", Method: "GET"},
+			{ID: primitive.NewObjectID(), FeatureName: "g", ScenarioName: "base", Name: "elsewhere", Path: "/x", Method: "GET"},
+		}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/mocktool/scenarios/"+child.ID.Hex()+"/effective-mockapis", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("scenario_id")
+	c.SetParamValues(child.ID.Hex())
+
+	require.NoError(t, controller.ListEffectiveMockAPIs(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Chain    []string         `json:"chain"`
+		MockAPIs []map[string]any `json:"mock_apis"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"child", "base"}, resp.Chain)
+
+	origins := map[string]string{}
+	for _, api := range resp.MockAPIs {
+		origins[api["name"].(string)] = api["origin"].(string)
+	}
+	assert.Equal(t, map[string]string{
+		"login-fail": "overridden",
+		"logout":     "own",
+		"profile":    "inherited",
+	}, origins)
+}
+
+func TestMockController_RestoreRevision(t *testing.T) {
+	controller, ctrl, _, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	revisions := usecaseMocks.NewMockIRevisionUC(ctrl)
+	controller.Revisions = revisions
+	scenarioID := primitive.NewObjectID()
+
+	restore := controller.restoreRevision(domain.RevisionKindScenario, "scenario_id")
+	call := func(version string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(revisionAuthorHeader, "alice")
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("scenario_id", "version")
+		c.SetParamValues(scenarioID.Hex(), version)
+		return rec, restore(c)
+	}
+
+	revisions.EXPECT().
+		Restore(gomock.Any(), domain.RevisionKindScenario, scenarioID, 2, "alice").
+		Return(&domain.Revision{Version: 5, RestoredFrom: 2}, nil)
+	rec, err := call("2")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	revisions.EXPECT().
+		Restore(gomock.Any(), domain.RevisionKindScenario, scenarioID, 3, "alice").
+		Return(nil, usecase.ErrRevisionConflict)
+	_, err = call("3")
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusConflict, httpErr.Code)
+
+	_, err = call("latest")
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestMockController_CloneScenario(t *testing.T) {
+	controller, ctrl, _, scenarioRepo, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	clones := usecaseMocks.NewMockICloneUC(ctrl)
+	controller.Clones = clones
+	scenarioID := primitive.NewObjectID()
+
+	call := func(body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e := echo.New()
+		e.Validator = customValidator.NewValidator()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("scenario_id")
+		c.SetParamValues(scenarioID.Hex())
+		return rec, controller.CloneScenario(c)
+	}
+
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), scenarioID).
+		Return(&domain.Scenario{ID: scenarioID, FeatureName: "f", Name: "s"}, nil).Times(2)
+	clones.EXPECT().CloneScenario(gomock.Any(), "f", "s", "g", "s-copy", usecase.CloneOptions{}).
+		Return(&usecase.CloneResult{Feature: "g", Scenarios: []string{"s-copy"}}, nil)
+	rec, err := call(`{"feature_name":"g","name":"s-copy"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	clones.EXPECT().CloneScenario(gomock.Any(), "f", "s", "g", "s", usecase.CloneOptions{}).
+		Return(nil, usecase.ErrCloneConflict)
+	_, err = call(`{"feature_name":"g","name":"s"}`)
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusConflict, httpErr.Code)
+}
+
+func TestMockController_ApplyAndRevertSession(t *testing.T) {
+	controller, ctrl, _, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	sessions := usecaseMocks.NewMockISessionUC(ctrl)
+	controller.Sessions = sessions
+	e := echo.New()
+	e.Validator = customValidator.NewValidator()
+	session := &domain.Session{ID: primitive.NewObjectID(), ProfileName: "e2e", AccountId: "acc-1"}
+
+	sessions.EXPECT().Apply(gomock.Any(), "e2e", "acc-1", 30*time.Minute).Return(session, nil)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"account_id":"acc-1","duration":"30m"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("name")
+	c.SetParamValues("e2e")
+	require.NoError(t, controller.ApplySessionProfile(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	sessions.EXPECT().Revert(gomock.Any(), session.ID).Return(nil, usecase.ErrSessionNotFound)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec)
+	c.SetParamNames("session_id")
+	c.SetParamValues(session.ID.Hex())
+	err := controller.RevertSession(c)
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+}
+
+func TestMockController_UpdateFeatureRenames(t *testing.T) {
+	controller, ctrl, featureRepo, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	renames := usecaseMocks.NewMockIRenameUC(ctrl)
+	controller.Renames = renames
+	cacheRepo := repositoryMocks.NewMockICache(ctrl)
+	controller.cacheRepo = cacheRepo
+	featureID := primitive.NewObjectID()
+
+	featureRepo.EXPECT().FindById(gomock.Any(), featureID).Return(&domain.Feature{ID: featureID, Name: "insertAd"}, nil)
+	renames.EXPECT().RenameFeature(gomock.Any(), "insertAd", "postAd").Return(nil, usecase.ErrRenameConflict)
+
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"name":"postAd"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("feature_id")
+	c.SetParamValues(featureID.Hex())
+	err := controller.UpdateFeature(c)
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusConflict, httpErr.Code)
+
+	featureRepo.EXPECT().FindById(gomock.Any(), featureID).Return(&domain.Feature{ID: featureID, Name: "insertAd"}, nil)
+	renames.EXPECT().RenameFeature(gomock.Any(), "insertAd", "postAd").Return(&usecase.RenameResult{Feature: "postAd"}, nil)
+	featureRepo.EXPECT().UpdateByObjectID(gomock.Any(), featureID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ primitive.ObjectID, update bson.M) error {
+			assert.NotContains(t, update, "name", "the rename already set it")
+			return nil
+		},
+	)
+	cacheRepo.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:postAd:*").Return(nil)
+
+	req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"name":"postAd"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c = echo.New().NewContext(req, rec)
+	c.SetParamNames("feature_id")
+	c.SetParamValues(featureID.Hex())
+	require.NoError(t, controller.UpdateFeature(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestMockController_DeleteFeatureMovesToTrash(t *testing.T) {
+	controller, ctrl, featureRepo, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	trash := usecaseMocks.NewMockITrashUC(ctrl)
+	controller.Trash = trash
+	featureID := primitive.NewObjectID()
+	entry := &domain.TrashEntry{ID: primitive.NewObjectID(), Kind: domain.TrashKindFeature, FeatureName: "insertAd"}
+
+	featureRepo.EXPECT().FindById(gomock.Any(), featureID).Return(&domain.Feature{ID: featureID, Name: "insertAd"}, nil)
+	trash.EXPECT().DeleteFeature(gomock.Any(), "insertAd", gomock.Any()).Return(entry, nil)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec)
+	c.SetParamNames("feature_id")
+	c.SetParamValues(featureID.Hex())
+	require.NoError(t, controller.DeleteFeature(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), entry.ID.Hex())
+
+	// ?hard=true purges the entry right away
+	featureRepo.EXPECT().FindById(gomock.Any(), featureID).Return(&domain.Feature{ID: featureID, Name: "insertAd"}, nil)
+	trash.EXPECT().DeleteFeature(gomock.Any(), "insertAd", gomock.Any()).Return(entry, nil)
+	trash.EXPECT().Purge(gomock.Any(), entry.ID).Return(nil)
+
+	rec = httptest.NewRecorder()
+	c = echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/?hard=true", nil), rec)
+	c.SetParamNames("feature_id")
+	c.SetParamValues(featureID.Hex())
+	require.NoError(t, controller.DeleteFeature(c))
+	assert.Contains(t, rec.Body.String(), `"deleted"`)
+}
+
+func TestMockController_DeleteScenarioRecordsRevisionAfterTrash(t *testing.T) {
+	controller, ctrl, _, scenarioRepo, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	trash := usecaseMocks.NewMockITrashUC(ctrl)
+	revisions := usecaseMocks.NewMockIRevisionUC(ctrl)
+	controller.Trash = trash
+	controller.Revisions = revisions
+	scenario := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "insertAd", Name: "s1"}
+	call := func() error {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), httptest.NewRecorder())
+		c.SetParamNames("scenario_id")
+		c.SetParamValues(scenario.ID.Hex())
+		return controller.DeleteScenario(c)
+	}
+
+	// A failed delete records nothing.
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), scenario.ID).Return(scenario, nil)
+	trash.EXPECT().DeleteScenario(gomock.Any(), "insertAd", "s1", gomock.Any()).Return(nil, usecase.ErrTrashConflict)
+	require.Error(t, call())
+
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), scenario.ID).Return(scenario, nil)
+	trash.EXPECT().DeleteScenario(gomock.Any(), "insertAd", "s1", gomock.Any()).
+		Return(&domain.TrashEntry{ID: primitive.NewObjectID(), Kind: domain.TrashKindScenario}, nil)
+	revisions.EXPECT().RecordDeleted(gomock.Any(), domain.RevisionKindScenario, scenario.ID, scenario, gomock.Any()).Return(nil)
+	require.NoError(t, call())
+}
+
+func TestMockController_RestoreFromTrashConflict(t *testing.T) {
+	controller, ctrl, _, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	trash := usecaseMocks.NewMockITrashUC(ctrl)
+	controller.Trash = trash
+	id := primitive.NewObjectID()
+
+	trash.EXPECT().Restore(gomock.Any(), id).Return(nil, usecase.ErrTrashConflict)
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	c.SetParamNames("trash_id")
+	c.SetParamValues(id.Hex())
+	err := controller.RestoreFromTrash(c)
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusConflict, httpErr.Code)
+}
+
+func TestMockController_ExportFeatureYAML(t *testing.T) {
+	controller, ctrl, featureRepo, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	bundles := usecaseMocks.NewMockIBundleUC(ctrl)
+	controller.Bundles = bundles
+	featureID := primitive.NewObjectID()
+
+	featureRepo.EXPECT().FindById(gomock.Any(), featureID).Return(&domain.Feature{ID: featureID, Name: "checkout"}, nil)
+	bundles.EXPECT().Export(gomock.Any(), "checkout", []string{"happy", "declined"}).
+		Return(&domain.Bundle{Version: domain.BundleVersion, Features: []domain.BundleFeature{{Name: "checkout"}}}, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/?format=yaml&scenarios=happy,%20declined", nil)
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("feature_id")
+	c.SetParamValues(featureID.Hex())
+	require.NoError(t, controller.ExportFeature(c))
+	assert.Equal(t, "application/yaml", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), `filename="checkout.yaml"`)
+	assert.Equal(t, "version: 1\nfeatures:\n  - name: checkout\n", rec.Body.String())
+}
+
+func TestMockController_ImportBundleDryRun(t *testing.T) {
+	controller, ctrl, _, _, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	bundles := usecaseMocks.NewMockIBundleUC(ctrl)
+	controller.Bundles = bundles
+
+	change := domain.BundleChange{Kind: domain.BundleKindFeature, Action: domain.BundleActionCreate, Feature: "checkout"}
+	bundles.EXPECT().Import(gomock.Any(), gomock.Any(), true, gomock.Any()).
+		Return(&usecase.ImportResult{DryRun: true, Created: 1, Changes: []domain.BundleChange{change}}, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/?dry_run=true", strings.NewReader("version: 1\nfeatures:\n  - name: checkout\n"))
+	require.NoError(t, controller.ImportBundle(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"dry_run":true`)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"version": 1, "feature": "checkout"}`))
+	err := controller.ImportBundle(echo.New().NewContext(req, httptest.NewRecorder()))
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestMockController_AuditMiddleware(t *testing.T) {
+	controller, ctrl, _, scenarioRepo, _, _ := setupTestController(t)
+	defer ctrl.Finish()
+	auditRepo := repositoryMocks.NewMockIAuditRepository(ctrl)
+	controller.AuditRepo = auditRepo
+	scenarioID := primitive.NewObjectID()
+
+	e := echo.New()
+	v1 := e.Group(apiPrefix)
+	v1.Use(controller.auditMiddleware(apiPrefix))
+	v1.GET("/scenarios/:scenario_id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	v1.PUT("/scenarios/:scenario_id", func(c echo.Context) error {
+		var body map[string]any
+		require.NoError(t, c.Bind(&body))
+		assert.Equal(t, "after", body["description"], "the handler still reads the body")
+		return c.JSON(http.StatusOK, body)
+	})
+
+	gomock.InOrder(
+		scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), scenarioID).
+			Return(&domain.Scenario{ID: scenarioID, FeatureName: "f", Name: "s", Description: "before"}, nil),
+		scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), scenarioID).
+			Return(&domain.Scenario{ID: scenarioID, FeatureName: "f", Name: "s", Description: "after"}, nil),
+	)
+	auditRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, entry *domain.AuditEntry) error {
+			assert.Equal(t, "alice", entry.Actor)
+			assert.Equal(t, domain.AuditSourceHTTP, entry.Source)
+			assert.Equal(t, "PUT /scenarios/:scenario_id", entry.Operation)
+			assert.Equal(t, "scenario_id="+scenarioID.Hex(), entry.Target)
+			assert.Equal(t, "f", entry.FeatureName)
+			assert.Equal(t, "before", entry.Before["description"])
+			assert.Equal(t, "after", entry.After["description"])
+			assert.Equal(t, "after", entry.Request["description"])
+			assert.Equal(t, http.StatusOK, entry.Status)
+			return nil
+		},
+	)
+
+	req := httptest.NewRequest(http.MethodPut, apiPrefix+"/scenarios/"+scenarioID.Hex(), strings.NewReader(`{"description":"after"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(revisionAuthorHeader, "alice")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "after")
+
+	// Reads are not recorded.
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, apiPrefix+"/scenarios/"+scenarioID.Hex(), nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
 func TestMockController_ListActiveScenarioByFeature(t *testing.T) {
 	controller, ctrl, _, scenarioRepo, accountScenarioRepo, _ := setupTestController(t)
 	defer ctrl.Finish()
@@ -500,12 +1018,26 @@ func TestNewMockController(t *testing.T) {
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo not needed in unit tests
+		nil, // grpcDescriptors not needed in unit tests
+		nil, // grpcServiceIndex not needed in unit tests
+		nil, // grpcProxy not needed in unit tests
+		nil, // revisions not needed in unit tests
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
-		nil,                    // chatHandler not needed in unit tests
+		nil,                     // chatHandler not needed in unit tests
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	)
 
 	assert.NotNil(t, controller)
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+// deleted answers a delete moved to the trash, purging it first when the
+// request asks for ?hard=true.
+func (_self *MockController) deleted(c echo.Context, entry *domain.TrashEntry) error {
+	_self.refreshTrashedGRPCIndex(c, entry)
+	if hard, _ := strconv.ParseBool(c.QueryParam("hard")); hard {
+		if err := _self.Trash.Purge(c.Request().Context(), entry.ID); err != nil {
+			return trashHTTPError(err)
+		}
+		return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+/* ---------- GET /trash ---------- */
+
+func (_self *MockController) ListTrash(c echo.Context) error {
+	params := parsePaginationParams(c)
+	entries, total, err := _self.Trash.List(c.Request().Context(), c.QueryParam("feature_name"), params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, domain.NewPaginatedResponse(entries, total, params))
+}
+
+/* ---------- POST /trash/:trash_id/restore ---------- */
+
+func (_self *MockController) RestoreFromTrash(c echo.Context) error {
+	id, err := primitive.ObjectIDFromHex(c.Param("trash_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid trash_id")
+	}
+	entry, err := _self.Trash.Restore(c.Request().Context(), id)
+	if err != nil {
+		return trashHTTPError(err)
+	}
+	_self.refreshTrashedGRPCIndex(c, entry)
+	return c.JSON(http.StatusOK, entry)
+}
+
+/* ---------- DELETE /trash/:trash_id ---------- */
+
+func (_self *MockController) PurgeTrash(c echo.Context) error {
+	id, err := primitive.ObjectIDFromHex(c.Param("trash_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid trash_id")
+	}
+	if err := _self.Trash.Purge(c.Request().Context(), id); err != nil {
+		return trashHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "purged"})
+}
+
+// refreshTrashedGRPCIndex reloads the reflection index when gRPC mocks were
+// moved in or out of the trash.
+func (_self *MockController) refreshTrashedGRPCIndex(c echo.Context, entry *domain.TrashEntry) {
+	if entry.Counts["grpc_mock_apis"] > 0 && _self.GRPCServiceIndex != nil {
+		_ = _self.GRPCServiceIndex.Refresh(c.Request().Context())
+	}
+}
+
+func trashHTTPError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrDeleteNotFound), errors.Is(err, usecase.ErrTrashNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrTrashConflict), errors.Is(err, usecase.ErrDeleteConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
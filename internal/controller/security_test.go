@@ -34,12 +34,26 @@ func TestSecurity_HeaderSanitization(t *testing.T) {
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo
+		nil, // grpcDescriptors
+		nil, // grpcServiceIndex
+		nil, // grpcProxy
+		nil, // revisions
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
 		nil,                     // chatHandler
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	).(*MockController)
 
 	tests := []struct {
@@ -205,12 +219,26 @@ func TestSecurity_ValidationPreventsInjection(t *testing.T) {
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo
+		nil, // grpcDescriptors
+		nil, // grpcServiceIndex
+		nil, // grpcProxy
+		nil, // revisions
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
 		nil,                     // chatHandler
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	).(*MockController)
 
 	tests := []struct {
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/namnv2496/mocktool/pkg/security"
+)
+
+// startEcho serves e on addr, over TLS when serverTLS is set. ALPN offers h2
+// so HTTP/2 clients are served without an upgrade.
+func startEcho(e *echo.Echo, addr string, serverTLS *security.ServerTLS) error {
+	if serverTLS == nil {
+		return e.Start(addr)
+	}
+	return e.StartServer(&http.Server{Addr: addr, TLSConfig: serverTLS.Config()})
+}
+
+// DownloadCACert returns the local CA that issued the server certificate so
+// clients can trust it.
+func (_self *MockController) DownloadCACert(c echo.Context) error {
+	caPEM := _self.serverTLS.CACertPEM()
+	if caPEM == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "TLS is disabled or uses a provided certificate")
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="mocktool-ca.crt"`)
+	return c.Blob(http.StatusOK, "application/x-pem-file", caPEM)
+}
@@ -34,12 +34,26 @@ func TestValidation_CreateNewFeature(t *testing.T) {
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo
+		nil, // grpcDescriptors
+		nil, // grpcServiceIndex
+		nil, // grpcProxy
+		nil, // revisions
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
 		nil,                     // chatHandler
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	).(*MockController)
 
 	tests := []struct {
@@ -131,12 +145,26 @@ func TestValidation_CreateNewScenario(t *testing.T) {
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo
+		nil, // grpcDescriptors
+		nil, // grpcServiceIndex
+		nil, // grpcProxy
+		nil, // revisions
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
 		nil,                     // chatHandler
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	).(*MockController)
 
 	tests := []struct {
@@ -228,12 +256,26 @@ func TestValidation_CreateMockAPI(t *testing.T) {
 		featureRepo,
 		scenarioRepo,
 		accountScenarioRepo,
+		nil, // accountGroupRepo
 		mockAPIRepo,
 		nil, // grpcMockAPIRepo
+		nil, // grpcDescriptors
+		nil, // grpcServiceIndex
+		nil, // grpcProxy
+		nil, // revisions
+		nil, // audit
+		nil, // clones
+		nil, // sessionProfileRepo
+		nil, // sessionRepo
+		nil, // sessions
+		nil, // renames
+		nil, // trash
+		nil, // bundles
 		loadTestController,
 		cacheRepo,
 		nil,                     // chatHandler
 		usecase.NewStatsStore(), // stats
+		nil,                     // serverTLS
 	).(*MockController)
 
 	tests := []struct {
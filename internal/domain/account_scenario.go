@@ -1,20 +1,162 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Activation targets besides a single account (AccountId) and everyone
+// (global). When several activations match an account the most specific one
+// wins: exact account > group > pattern > percentage > global.
+const (
+	ActivationTargetGroup      = "group"      // the accounts of the AccountGroup named GroupName
+	ActivationTargetPattern    = "pattern"    // accounts matching AccountPattern (regex) or AccountPrefix
+	ActivationTargetPercentage = "percentage" // a stable Percentage of the accounts
+)
+
 // AccountScenario maps which scenario is active for a specific account (or globally)
+// or for a cohort of accounts selected by Target.
+// An activation with StartsAt/ExpiresAt is only effective inside that window
+// and takes precedence over the permanent one while it is.
 type AccountScenario struct {
 	ID          primitive.ObjectID `bson:"_id" json:"id"`
 	FeatureName string             `bson:"feature_name" json:"feature_name"`
 	ScenarioID  primitive.ObjectID `bson:"scenario_id" json:"scenario_id"`
 	AccountId   *string            `bson:"account_id,omitempty" json:"account_id,omitempty"` // null/empty for global
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	// Target selects a cohort instead of AccountId; empty for an exact
+	// account or global activation.
+	Target         string     `bson:"target,omitempty" json:"target,omitempty"`
+	GroupName      string     `bson:"group_name,omitempty" json:"group_name,omitempty"`
+	AccountPattern string     `bson:"account_pattern,omitempty" json:"account_pattern,omitempty"`
+	AccountPrefix  string     `bson:"account_prefix,omitempty" json:"account_prefix,omitempty"`
+	Percentage     int        `bson:"percentage,omitempty" json:"percentage,omitempty"` // 1-100
+	StartsAt       *time.Time `bson:"starts_at,omitempty" json:"starts_at,omitempty"`   // null: effective right away
+	ExpiresAt      *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // null: until deactivated
+	CreatedAt      time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// ValidateTarget checks the cohort fields against Target.
+func (_self AccountScenario) ValidateTarget() error {
+	if _self.Target != "" && _self.AccountId != nil {
+		return errors.New("account_id cannot be combined with a target")
+	}
+	switch _self.Target {
+	case "":
+		return nil
+	case ActivationTargetGroup:
+		if strings.TrimSpace(_self.GroupName) == "" {
+			return errors.New("group_name is required for a group activation")
+		}
+	case ActivationTargetPattern:
+		if _self.AccountPattern == "" && _self.AccountPrefix == "" {
+			return errors.New("account_pattern or account_prefix is required for a pattern activation")
+		}
+		if _self.AccountPattern != "" && _self.AccountPrefix != "" {
+			return errors.New("set either account_pattern or account_prefix, not both")
+		}
+		if _self.AccountPattern != "" {
+			if _, err := compileAccountPattern(_self.AccountPattern); err != nil {
+				return fmt.Errorf("invalid account_pattern: %w", err)
+			}
+		}
+	case ActivationTargetPercentage:
+		if _self.Percentage < 1 || _self.Percentage > 100 {
+			return errors.New("percentage must be between 1 and 100")
+		}
+	default:
+		return fmt.Errorf("unknown target %q, expected group, pattern or percentage", _self.Target)
+	}
+	return nil
+}
+
+// MatchesAccount reports whether a pattern or percentage activation applies
+// to accountId. Group membership is resolved by the repository.
+func (_self AccountScenario) MatchesAccount(accountId string) bool {
+	switch _self.Target {
+	case ActivationTargetPattern:
+		if _self.AccountPrefix != "" {
+			return strings.HasPrefix(accountId, _self.AccountPrefix)
+		}
+		re, err := compileAccountPattern(_self.AccountPattern)
+		return err == nil && re.MatchString(accountId)
+	case ActivationTargetPercentage:
+		return AccountBucket(_self.FeatureName, accountId) < _self.Percentage
+	}
+	return false
+}
+
+// AccountBucket places accountId in one of 100 buckets. The bucket only
+// depends on the feature and the account, so an account stays in a rollout
+// while its percentage grows.
+func AccountBucket(featureName, accountId string) int {
+	h := fnv.New32a()
+	h.Write([]byte(featureName + ":" + accountId))
+	return int(h.Sum32() % 100)
+}
+
+// accountPatterns caches compiled AccountPattern values, which are matched
+// on every mock lookup.
+var accountPatterns sync.Map
+
+func compileAccountPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := accountPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	accountPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// SetWindow limits the activation to [startsAt, expiresAt). duration counts
+// from startsAt (or now) and cannot be combined with expiresAt.
+func (_self *AccountScenario) SetWindow(now time.Time, startsAt, expiresAt *time.Time, duration time.Duration) error {
+	if duration < 0 {
+		return errors.New("duration must be positive")
+	}
+	if duration > 0 && expiresAt != nil {
+		return errors.New("set either duration or expires_at, not both")
+	}
+	if startsAt != nil {
+		start := startsAt.UTC()
+		_self.StartsAt = &start
+	}
+	if duration > 0 {
+		from := now
+		if _self.StartsAt != nil {
+			from = *_self.StartsAt
+		}
+		expiry := from.Add(duration).UTC()
+		_self.ExpiresAt = &expiry
+	} else if expiresAt != nil {
+		expiry := expiresAt.UTC()
+		_self.ExpiresAt = &expiry
+	}
+	if _self.ExpiresAt != nil {
+		if !_self.ExpiresAt.After(now) {
+			return errors.New("expires_at must be in the future")
+		}
+		if _self.StartsAt != nil && !_self.ExpiresAt.After(*_self.StartsAt) {
+			return errors.New("expires_at must be after starts_at")
+		}
+	}
+	return nil
+}
+
+// IsTimeBoxed reports whether the activation has a start or an expiry.
+func (_self AccountScenario) IsTimeBoxed() bool {
+	return _self.StartsAt != nil || _self.ExpiresAt != nil
 }
 
 func (_self AccountScenario) ToMap() bson.M {
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Channels an audited change comes from.
+const (
+	AuditSourceHTTP  = "http"  // admin API and web UI
+	AuditSourceMCP   = "mcp"   // MCP clients
+	AuditSourceSlack = "slack" // Slack bot
+	AuditSourceChat  = "chat"  // AI chat of the web UI
+)
+
+// AuditEntry records one mutating operation. Operation is the HTTP method and
+// route, or the tool name; Before and After are the target document around
+// the change when it is known.
+type AuditEntry struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Actor       string             `bson:"actor" json:"actor"`
+	Source      string             `bson:"source" json:"source"`
+	Operation   string             `bson:"operation" json:"operation"`
+	Target      string             `bson:"target,omitempty" json:"target,omitempty"`
+	FeatureName string             `bson:"feature_name,omitempty" json:"feature_name,omitempty"`
+	Request     bson.M             `bson:"request,omitempty" json:"request,omitempty"`
+	Before      bson.M             `bson:"before,omitempty" json:"before,omitempty"`
+	After       bson.M             `bson:"after,omitempty" json:"after,omitempty"`
+	Status      int                `bson:"status,omitempty" json:"status,omitempty"` // HTTP status
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AuditFilter selects audit entries; zero fields match everything.
+type AuditFilter struct {
+	FeatureName string
+	Actor       string
+	Source      string
+	From        time.Time
+	To          time.Time
+}
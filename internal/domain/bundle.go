@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BundleVersion is the version of the bundle format written by exports.
+const BundleVersion = 1
+
+// Kinds of documents in a bundle.
+const (
+	BundleKindFeature     = "feature"
+	BundleKindScenario    = "scenario"
+	BundleKindMockAPI     = "mock_api"
+	BundleKindGRPCMockAPI = "grpc_mock_api"
+	BundleKindActivation  = "activation"
+)
+
+// Actions of an import.
+const (
+	BundleActionCreate = "create"
+	BundleActionUpdate = "update"
+)
+
+// Bundle is a portable copy of features with their scenarios, mocks and
+// activations. Documents refer to each other by name, never by ID, so a
+// bundle can be imported on another machine. Payloads (inputs, outputs,
+// headers) are plain JSON values.
+type Bundle struct {
+	Version    int             `json:"version"`
+	ExportedAt *time.Time      `json:"exported_at,omitempty"`
+	Features   []BundleFeature `json:"features"`
+}
+
+// BundleFeature is keyed by Name. IsActive defaults to true.
+type BundleFeature struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	IsActive    *bool            `json:"is_active,omitempty"`
+	Scenarios   []BundleScenario `json:"scenarios,omitempty"`
+}
+
+// BundleScenario is keyed by Name within its feature. Parent names a scenario
+// of the same feature, in the bundle or already imported.
+type BundleScenario struct {
+	Name         string              `json:"name"`
+	Description  string              `json:"description,omitempty"`
+	Parent       string              `json:"parent,omitempty"`
+	MockAPIs     []BundleMockAPI     `json:"mock_apis,omitempty"`
+	GRPCMockAPIs []BundleGRPCMockAPI `json:"grpc_mock_apis,omitempty"`
+	// Activations lists the permanent activations of the scenario.
+	Activations []BundleActivation `json:"activations,omitempty"`
+}
+
+// BundleMockAPI is keyed by Name within its scenario. IsActive defaults to
+// true.
+type BundleMockAPI struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	IsActive    *bool                    `json:"is_active,omitempty"`
+	BaseURL     string                   `json:"base_url,omitempty"`
+	Path        string                   `json:"path"`
+	Method      string                   `json:"method"`
+	Input       json.RawMessage          `json:"input,omitempty"`
+	Headers     json.RawMessage          `json:"headers,omitempty"`
+	Output      json.RawMessage          `json:"output,omitempty"`
+	StatusCode  int                      `json:"status_code,omitempty"`
+	Latency     int64                    `json:"latency,omitempty"`
+	Responses   []BundleSequenceResponse `json:"responses,omitempty"`
+}
+
+type BundleSequenceResponse struct {
+	From       int             `json:"from"`
+	To         int             `json:"to"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Output     json.RawMessage `json:"output,omitempty"`
+	Headers    json.RawMessage `json:"headers,omitempty"`
+	Latency    int64           `json:"latency,omitempty"`
+}
+
+// BundleGRPCMockAPI is keyed by service, method, input and matchers within
+// its scenario, like the lookup of a call. IsActive defaults to true.
+type BundleGRPCMockAPI struct {
+	ServiceName   string                       `json:"service_name"`
+	MethodName    string                       `json:"method_name"`
+	IsActive      *bool                        `json:"is_active,omitempty"`
+	Input         json.RawMessage              `json:"input,omitempty"`
+	Matchers      []GRPCFieldMatcher           `json:"matchers,omitempty"`
+	Output        json.RawMessage              `json:"output,omitempty"`
+	StatusCode    int32                        `json:"status_code,omitempty"`
+	StatusMessage string                       `json:"status_message,omitempty"`
+	Details       []BundleGRPCStatusDetail     `json:"details,omitempty"`
+	Latency       int64                        `json:"latency,omitempty"`
+	Hang          bool                         `json:"hang,omitempty"`
+	StreamType    string                       `json:"stream_type,omitempty"`
+	Responses     []BundleGRPCStreamMessage    `json:"responses,omitempty"`
+	Aggregate     string                       `json:"aggregate,omitempty"`
+	BidiRules     []BundleGRPCBidiRule         `json:"bidi_rules,omitempty"`
+	Headers       map[string]string            `json:"headers,omitempty"`
+	Trailers      map[string]string            `json:"trailers,omitempty"`
+	Sequence      []BundleGRPCSequenceResponse `json:"sequence,omitempty"`
+}
+
+type BundleGRPCStatusDetail struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type BundleGRPCStreamMessage struct {
+	Output json.RawMessage `json:"output,omitempty"`
+	Delay  int64           `json:"delay,omitempty"`
+}
+
+type BundleGRPCBidiRule struct {
+	Input     json.RawMessage           `json:"input,omitempty"`
+	Responses []BundleGRPCStreamMessage `json:"responses"`
+}
+
+type BundleGRPCSequenceResponse struct {
+	From          int             `json:"from"`
+	To            int             `json:"to"`
+	StatusCode    int32           `json:"status_code,omitempty"`
+	StatusMessage string          `json:"status_message,omitempty"`
+	Output        json.RawMessage `json:"output,omitempty"`
+	Latency       int64           `json:"latency,omitempty"`
+}
+
+// BundleActivation puts its scenario on an account, a cohort or everyone (no
+// field set). It is keyed by its target, like ActivateScenario replaces
+// activations: one per account, group, pattern or prefix, and one percentage
+// rollout per feature.
+type BundleActivation struct {
+	AccountId      string `json:"account_id,omitempty"`
+	Target         string `json:"target,omitempty"`
+	GroupName      string `json:"group_name,omitempty"`
+	AccountPattern string `json:"account_pattern,omitempty"`
+	AccountPrefix  string `json:"account_prefix,omitempty"`
+	Percentage     int    `json:"percentage,omitempty"`
+}
+
+// BundleChange is one document an import creates or updates.
+type BundleChange struct {
+	Kind     string `json:"kind"`
+	Action   string `json:"action"`
+	Feature  string `json:"feature"`
+	Scenario string `json:"scenario,omitempty"`
+	// Name identifies the document within its scenario: the mock name, the
+	// gRPC service/method or the activation target.
+	Name string `json:"name,omitempty"`
+	// Fields lists the fields an update changes.
+	Fields []string `json:"fields,omitempty"`
+}
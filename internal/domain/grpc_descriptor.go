@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	GRPCDescriptorSourceProto         = "proto"
+	GRPCDescriptorSourceDescriptorSet = "descriptor_set"
+	GRPCDescriptorSourceReflection    = "reflection" // fetched from a proxy upstream
+)
+
+// GRPCDescriptor stores the serialized FileDescriptorSet that describes one
+// gRPC service. DescriptorSet contains the file declaring the service plus all
+// of its transitive imports, so it can be loaded without any other document.
+type GRPCDescriptor struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	ServiceName   string             `bson:"service_name" json:"service_name"`
+	Source        string             `bson:"source" json:"source"` // proto, descriptor_set or reflection
+	Files         []string           `bson:"files" json:"files"`
+	DescriptorSet []byte             `bson:"descriptor_set" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
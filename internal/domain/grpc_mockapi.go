@@ -7,18 +7,101 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Stream types of a gRPC mock. An empty value means unary.
+const (
+	GRPCStreamUnary  = ""
+	GRPCStreamServer = "server"
+	GRPCStreamClient = "client"
+	GRPCStreamBidi   = "bidi"
+)
+
+// Aggregation rules for client-streaming mocks. The aggregated value is
+// hashed and matched against the mock input like a unary request.
+const (
+	GRPCAggregateLast  = "last"  // last message (default)
+	GRPCAggregateFirst = "first" // first message
+	GRPCAggregateMerge = "merge" // shallow merge of all messages, later fields win
+	GRPCAggregateAll   = "all"   // {"messages": [...]} in receive order
+	GRPCAggregateCount = "count" // {"count": n}
+)
+
+// GRPCStreamMessage is one scripted response message, sent after Delay ms.
+type GRPCStreamMessage struct {
+	Output bson.Raw `bson:"output,omitempty" json:"output"`
+	Delay  int64    `bson:"delay" json:"delay"`
+}
+
+// GRPCBidiRule replies with Responses to every incoming bidi message whose
+// hash equals HashInput. An empty HashInput matches any message.
+type GRPCBidiRule struct {
+	Input     bson.Raw            `bson:"input,omitempty" json:"input"`
+	HashInput string              `bson:"hash_input" json:"hash_input"`
+	Responses []GRPCStreamMessage `bson:"responses" json:"responses"`
+}
+
+// GRPCStatusDetail is one detail message of the google.rpc.Status returned by
+// a failing mock. Type is the message full name (e.g. google.rpc.ErrorInfo)
+// and Value its protobuf JSON form.
+type GRPCStatusDetail struct {
+	Type  string   `bson:"type" json:"type"`
+	Value bson.Raw `bson:"value,omitempty" json:"value"`
+}
+
+// GRPCSequenceResponse overrides the mock response for calls From..To-1 of a
+// sequence (To == 0 means open ended), like SequenceResponse for HTTP mocks.
+// Latency is in milliseconds.
+type GRPCSequenceResponse struct {
+	From          int      `bson:"from" json:"from"`
+	To            int      `bson:"to" json:"to"`
+	StatusCode    int32    `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	StatusMessage string   `bson:"status_message,omitempty" json:"status_message,omitempty"`
+	Output        bson.Raw `bson:"output,omitempty" json:"output"`
+	Latency       int64    `bson:"latency" json:"latency"`
+}
+
+// Operators of a GRPCFieldMatcher.
+const (
+	GRPCMatchEquals = "equals" // string form of the field equals Value
+	GRPCMatchRegex  = "regex"  // string form of the field matches the regexp Value
+	GRPCMatchExists = "exists" // field is present
+	GRPCMatchAbsent = "absent" // field is missing
+	GRPCMatchRange  = "range"  // numeric field within [Min, Max], either bound optional
+)
+
+// GRPCFieldMatcher is a predicate on one request field. Path uses the
+// protobuf JSON field names separated by dots; list elements are addressed by
+// index (e.g. items.0.sku).
+type GRPCFieldMatcher struct {
+	Path  string   `bson:"path" json:"path"`
+	Op    string   `bson:"op" json:"op"`
+	Value string   `bson:"value,omitempty" json:"value,omitempty"`
+	Min   *float64 `bson:"min,omitempty" json:"min,omitempty"`
+	Max   *float64 `bson:"max,omitempty" json:"max,omitempty"`
+}
+
 type GRPCMockAPI struct {
-	ID           primitive.ObjectID `bson:"_id" json:"id"`
-	FeatureName  string             `bson:"feature_name" json:"feature_name" validate:"required,no_spaces"`
-	ScenarioName string             `bson:"scenario_name" json:"scenario_name" validate:"required,no_spaces"`
-	ServiceName  string             `bson:"service_name" json:"service_name" validate:"required"`
-	MethodName   string             `bson:"method_name" json:"method_name" validate:"required"`
-	HashInput    string             `bson:"hash_input" json:"hash_input"`
-	Input        bson.Raw           `bson:"input,omitempty" json:"input"`
-	Output       bson.Raw           `bson:"output,omitempty" json:"output"`
-	StatusCode   int32              `bson:"status_code,omitempty" json:"status_code,omitempty"`
-	Latency      int64              `bson:"latency" json:"latency"`
-	IsActive     bool               `bson:"is_active" json:"is_active"`
-	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+	ID            primitive.ObjectID     `bson:"_id" json:"id"`
+	FeatureName   string                 `bson:"feature_name" json:"feature_name" validate:"required,no_spaces"`
+	ScenarioName  string                 `bson:"scenario_name" json:"scenario_name" validate:"required,no_spaces"`
+	ServiceName   string                 `bson:"service_name" json:"service_name" validate:"required"`
+	MethodName    string                 `bson:"method_name" json:"method_name" validate:"required"`
+	HashInput     string                 `bson:"hash_input" json:"hash_input"`
+	Input         bson.Raw               `bson:"input,omitempty" json:"input"`
+	Output        bson.Raw               `bson:"output,omitempty" json:"output"`
+	StatusCode    int32                  `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Latency       int64                  `bson:"latency" json:"latency"`
+	Hang          bool                   `bson:"hang,omitempty" json:"hang,omitempty"` // never answer; the call ends at the client deadline
+	StreamType    string                 `bson:"stream_type,omitempty" json:"stream_type,omitempty"`
+	Responses     []GRPCStreamMessage    `bson:"responses,omitempty" json:"responses,omitempty"` // server streaming
+	Aggregate     string                 `bson:"aggregate,omitempty" json:"aggregate,omitempty"` // client streaming
+	BidiRules     []GRPCBidiRule         `bson:"bidi_rules,omitempty" json:"bidi_rules,omitempty"`
+	Headers       map[string]string      `bson:"headers,omitempty" json:"headers,omitempty"`
+	Trailers      map[string]string      `bson:"trailers,omitempty" json:"trailers,omitempty"`
+	StatusMessage string                 `bson:"status_message,omitempty" json:"status_message,omitempty"`
+	Details       []GRPCStatusDetail     `bson:"details,omitempty" json:"details,omitempty"`
+	Sequence      []GRPCSequenceResponse `bson:"sequence,omitempty" json:"sequence,omitempty"` // unary only
+	Matchers      []GRPCFieldMatcher     `bson:"matchers,omitempty" json:"matchers,omitempty"`
+	IsActive      bool                   `bson:"is_active" json:"is_active"`
+	CreatedAt     time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time              `bson:"updated_at" json:"updated_at"`
 }
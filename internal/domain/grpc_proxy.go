@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GRPCProxy forwards calls of one service that no mock matches to a real
+// upstream. With Record set, every forwarded unary call is saved as a
+// GRPCMockAPI in RecordFeature/RecordScenario.
+type GRPCProxy struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	ServiceName    string             `bson:"service_name" json:"service_name"`
+	Upstream       string             `bson:"upstream" json:"upstream"` // host:port
+	TLS            bool               `bson:"tls" json:"tls"`
+	Record         bool               `bson:"record" json:"record"`
+	RecordFeature  string             `bson:"record_feature,omitempty" json:"record_feature,omitempty"`
+	RecordScenario string             `bson:"record_scenario,omitempty" json:"record_scenario,omitempty"`
+	IsActive       bool               `bson:"is_active" json:"is_active"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of documents a Revision can snapshot.
+const (
+	RevisionKindMockAPI     = "mock_api"
+	RevisionKindGRPCMockAPI = "grpc_mock_api"
+	RevisionKindScenario    = "scenario"
+)
+
+// Actions recorded by a Revision.
+const (
+	RevisionActionCreate  = "create"
+	RevisionActionUpdate  = "update"
+	RevisionActionDelete  = "delete" // Snapshot is the document as it was deleted
+	RevisionActionRestore = "restore"
+)
+
+// FieldChange is one top-level field that differs between two snapshots.
+// Before or After is nil when the field was added or removed.
+type FieldChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before any    `bson:"before,omitempty" json:"before"`
+	After  any    `bson:"after,omitempty" json:"after"`
+}
+
+// Revision is an immutable snapshot of a mock API, gRPC mock API or scenario
+// taken after each change. Versions start at 1 for every document.
+type Revision struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	Kind         string             `bson:"kind" json:"kind"`
+	ResourceID   primitive.ObjectID `bson:"resource_id" json:"resource_id"`
+	Version      int                `bson:"version" json:"version"`
+	Action       string             `bson:"action" json:"action"`
+	Author       string             `bson:"author" json:"author"`
+	RestoredFrom int                `bson:"restored_from,omitempty" json:"restored_from,omitempty"`
+	Changes      []FieldChange      `bson:"changes" json:"changes"` // against the previous version
+	Snapshot     bson.M             `bson:"snapshot" json:"snapshot"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
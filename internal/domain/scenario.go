@@ -7,13 +7,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Scenario groups the mocks served for a feature. A scenario with a parent
+// only holds the mocks it overrides: lookups that miss fall through to the
+// parent, then to its parent, and so on.
 type Scenario struct {
-	ID          primitive.ObjectID `bson:"_id" json:"id"`
-	FeatureName string             `bson:"feature_name" json:"feature_name"`
-	Name        string             `bson:"name" json:"name"`
-	Description string             `bson:"description" json:"description"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID  `bson:"_id" json:"id"`
+	FeatureName string              `bson:"feature_name" json:"feature_name"`
+	Name        string              `bson:"name" json:"name"`
+	Description string              `bson:"description" json:"description"`
+	ParentID    *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"` // scenario of the same feature
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
 }
 
 func (_self Scenario) ToMap() bson.M {
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SessionProfileEntry puts one feature on one scenario.
+type SessionProfileEntry struct {
+	FeatureName  string `bson:"feature_name" json:"feature_name"`
+	ScenarioName string `bson:"scenario_name" json:"scenario_name"`
+}
+
+// SessionProfile is a named set of feature→scenario pairs applied together
+// to one account, typically for an end-to-end test.
+type SessionProfile struct {
+	ID          primitive.ObjectID    `bson:"_id" json:"id"`
+	Name        string                `bson:"name" json:"name"`
+	Description string                `bson:"description,omitempty" json:"description,omitempty"`
+	Entries     []SessionProfileEntry `bson:"entries" json:"entries"`
+	CreatedAt   time.Time             `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time             `bson:"updated_at" json:"updated_at"`
+}
+
+// Session is a profile applied to an account. Its activations are layered on
+// top of the account's own, which take over again when the session is
+// reverted.
+type Session struct {
+	ID          primitive.ObjectID   `bson:"_id" json:"id"`
+	ProfileName string               `bson:"profile_name" json:"profile_name"`
+	AccountId   string               `bson:"account_id" json:"account_id"`
+	Ephemeral   bool                 `bson:"ephemeral,omitempty" json:"ephemeral,omitempty"` // AccountId was generated
+	Features    []string             `bson:"features" json:"features"`
+	Activations []primitive.ObjectID `bson:"activations" json:"activations"` // AccountScenario IDs
+	ExpiresAt   *time.Time           `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	CreatedAt   time.Time            `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of deletes kept in the trash.
+const (
+	TrashKindFeature  = "feature"
+	TrashKindScenario = "scenario"
+)
+
+// TrashEntry is a deleted feature or scenario, kept until ExpiresAt so the
+// delete can be undone. The documents deleted with it are kept as
+// TrashDocuments.
+type TrashEntry struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	Kind         string             `bson:"kind" json:"kind"`
+	FeatureName  string             `bson:"feature_name" json:"feature_name"`
+	ScenarioName string             `bson:"scenario_name,omitempty" json:"scenario_name,omitempty"`
+	DocumentID   primitive.ObjectID `bson:"document_id" json:"document_id"` // the feature or scenario
+	DeletedBy    string             `bson:"deleted_by" json:"deleted_by"`
+	DeletedAt    time.Time          `bson:"deleted_at" json:"deleted_at"`
+	ExpiresAt    time.Time          `bson:"expires_at" json:"expires_at"`
+	// ParentID is the parent of a trashed scenario, which must exist for the
+	// scenario to be restored.
+	ParentID *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	// Counts holds how many documents were deleted per collection.
+	Counts map[string]int `bson:"counts" json:"counts"`
+	// DroppedActivations counts the activations a restore left out because
+	// their account or cohort got another scenario since the delete.
+	DroppedActivations int `bson:"-" json:"dropped_activations,omitempty"`
+}
+
+// TrashDocument is one document deleted with the TrashEntry TrashID. Each one
+// is stored on its own, so a large delete is not bound by the size limit of a
+// single document.
+type TrashDocument struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	TrashID    primitive.ObjectID `bson:"trash_id"`
+	Collection string             `bson:"collection"`
+	Document   bson.Raw           `bson:"document"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+}
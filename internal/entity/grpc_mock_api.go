@@ -1,6 +1,9 @@
 package entity
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type GRPCMockAPIRequest struct {
 	FeatureName  string          `json:"feature_name" validate:"required,no_spaces"`
@@ -8,9 +11,65 @@ type GRPCMockAPIRequest struct {
 	ServiceName  string          `json:"service_name" validate:"required"`
 	MethodName   string          `json:"method_name" validate:"required"`
 	Input        json.RawMessage `json:"input"`
-	Output       json.RawMessage `json:"output" validate:"required"`
+	Output       json.RawMessage `json:"output"` // required for unary and client-streaming mocks
 	StatusCode   int32           `json:"status_code,omitempty"`
 	Latency      int64           `json:"latency"`
+	Hang         *bool           `json:"hang,omitempty"` // wait for the client deadline instead of answering
+
+	StreamType string                     `json:"stream_type,omitempty" validate:"omitempty,oneof=server client bidi"`
+	Responses  []GRPCStreamMessageRequest `json:"responses,omitempty"`                                                       // server streaming
+	Aggregate  string                     `json:"aggregate,omitempty" validate:"omitempty,oneof=last first merge all count"` // client streaming
+	BidiRules  []GRPCBidiRuleRequest      `json:"bidi_rules,omitempty"`
+
+	Headers       map[string]string         `json:"headers,omitempty"`
+	Trailers      map[string]string         `json:"trailers,omitempty"`
+	StatusMessage string                    `json:"status_message,omitempty"`
+	Details       []GRPCStatusDetailRequest `json:"details,omitempty"`
+
+	Sequence []GRPCSequenceResponseRequest `json:"sequence,omitempty"`
+	Matchers []GRPCFieldMatcherRequest     `json:"matchers,omitempty"`
+}
+
+// GRPCFieldMatcherRequest is a predicate on one request field, e.g.
+// {"path": "user_id", "op": "equals", "value": 1} or
+// {"path": "amount", "op": "range", "min": 100}.
+type GRPCFieldMatcherRequest struct {
+	Path  string          `json:"path"`
+	Op    string          `json:"op"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Min   *float64        `json:"min,omitempty"`
+	Max   *float64        `json:"max,omitempty"`
+}
+
+// GRPCSequenceResponseRequest is one step of a unary response sequence; it
+// applies to calls From..To-1 (To == 0 means open ended). Latency is in ms.
+type GRPCSequenceResponseRequest struct {
+	From          int             `json:"from"`
+	To            int             `json:"to"`
+	StatusCode    int32           `json:"status_code,omitempty"`
+	StatusMessage string          `json:"status_message,omitempty"`
+	Output        json.RawMessage `json:"output"`
+	Latency       int64           `json:"latency"`
+}
+
+// GRPCStatusDetailRequest is one google.rpc.Status detail, e.g.
+// {"type": "google.rpc.RetryInfo", "value": {"retry_delay": "5s"}}.
+type GRPCStatusDetailRequest struct {
+	Type  string          `json:"type" validate:"required"`
+	Value json.RawMessage `json:"value"`
+}
+
+// GRPCStreamMessageRequest is one scripted stream message; Delay is in ms.
+type GRPCStreamMessageRequest struct {
+	Output json.RawMessage `json:"output"`
+	Delay  int64           `json:"delay"`
+}
+
+// GRPCBidiRuleRequest replies with Responses to each bidi message equal to
+// Input. Without input the rule matches any message.
+type GRPCBidiRuleRequest struct {
+	Input     json.RawMessage            `json:"input"`
+	Responses []GRPCStreamMessageRequest `json:"responses"`
 }
 
 type GRPCMockAPIResponse struct {
@@ -23,5 +82,47 @@ type GRPCMockAPIResponse struct {
 	Output       json.RawMessage `json:"output"`
 	StatusCode   int32           `json:"status_code,omitempty"`
 	Latency      int64           `json:"latency"`
+	Hang         bool            `json:"hang,omitempty"`
 	IsActive     bool            `json:"is_active"`
+
+	StreamType string                     `json:"stream_type,omitempty"`
+	Responses  []GRPCStreamMessageRequest `json:"responses,omitempty"`
+	Aggregate  string                     `json:"aggregate,omitempty"`
+	BidiRules  []GRPCBidiRuleRequest      `json:"bidi_rules,omitempty"`
+
+	Headers       map[string]string         `json:"headers,omitempty"`
+	Trailers      map[string]string         `json:"trailers,omitempty"`
+	StatusMessage string                    `json:"status_message,omitempty"`
+	Details       []GRPCStatusDetailRequest `json:"details,omitempty"`
+
+	Sequence []GRPCSequenceResponseRequest `json:"sequence,omitempty"`
+	Matchers []GRPCFieldMatcherRequest     `json:"matchers,omitempty"`
+}
+
+// GRPCDescriptorUploadRequest carries either .proto sources (file name →
+// content) or a binary FileDescriptorSet (base64 in JSON), e.g. the output of
+// `protoc --include_imports --descriptor_set_out`.
+type GRPCDescriptorUploadRequest struct {
+	Files         map[string]string `json:"files"`
+	DescriptorSet []byte            `json:"descriptor_set"`
+}
+
+type GRPCDescriptorResponse struct {
+	ServiceName string    `json:"service_name"`
+	Source      string    `json:"source"`
+	Files       []string  `json:"files"`
+	Methods     []string  `json:"methods"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GRPCProxyRequest configures the upstream of one service. IsActive defaults
+// to true.
+type GRPCProxyRequest struct {
+	ServiceName    string `json:"service_name"`
+	Upstream       string `json:"upstream"`
+	TLS            bool   `json:"tls"`
+	Record         bool   `json:"record"`
+	RecordFeature  string `json:"record_feature"`
+	RecordScenario string `json:"record_scenario"`
+	IsActive       *bool  `json:"is_active"`
 }
@@ -1,6 +1,9 @@
 package entity
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type SequenceResponseRequest struct {
 	From       int             `json:"from"`
@@ -30,4 +33,42 @@ type MockAPIRequest struct {
 
 type ActiceScenarioRequest struct {
 	PrevScenarioId string `json:"prev_scenario_id"`
+	// Optional window: the activation starts at StartsAt (default now) and
+	// ends at ExpiresAt or after Duration (e.g. "30m").
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Duration  string     `json:"duration"`
+	// Optional cohort instead of the account_id query parameter: "group"
+	// (GroupName), "pattern" (AccountPattern regex or AccountPrefix) or
+	// "percentage" (Percentage of the accounts, 1-100).
+	Target         string `json:"target"`
+	GroupName      string `json:"group_name"`
+	AccountPattern string `json:"account_pattern"`
+	AccountPrefix  string `json:"account_prefix"`
+	Percentage     int    `json:"percentage"`
+}
+
+// AccountGroupRequest creates or replaces the account group Name.
+type AccountGroupRequest struct {
+	Name        string   `json:"name" validate:"required,no_spaces"`
+	Description string   `json:"description"`
+	AccountIds  []string `json:"account_ids"`
+}
+
+// SessionProfileRequest creates or replaces the session profile Name, which
+// puts each feature of Entries on its scenario.
+type SessionProfileRequest struct {
+	Name        string `json:"name" validate:"required,no_spaces"`
+	Description string `json:"description"`
+	Entries     []struct {
+		FeatureName  string `json:"feature_name"`
+		ScenarioName string `json:"scenario_name"`
+	} `json:"entries"`
+}
+
+// ApplySessionRequest applies a profile to AccountId, or to a generated
+// account when it is empty, for Duration (e.g. "1h") or until reverted.
+type ApplySessionRequest struct {
+	AccountId string `json:"account_id"`
+	Duration  string `json:"duration"`
 }
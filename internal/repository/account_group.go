@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IAccountGroupRepository interface {
+	Upsert(ctx context.Context, g *domain.AccountGroup) error
+	FindByName(ctx context.Context, name string) (*domain.AccountGroup, error)
+	ListAll(ctx context.Context) ([]domain.AccountGroup, error)
+	ListNamesByAccount(ctx context.Context, accountId string) ([]string, error)
+	DeleteByName(ctx context.Context, name string) error
+}
+
+type AccountGroupRepository struct {
+	repo IBaseRepository
+}
+
+func NewAccountGroupRepository(db *mongo.Database) IAccountGroupRepository {
+	return &AccountGroupRepository{
+		repo: NewBaseRepository(db.Collection("account_groups")),
+	}
+}
+
+// Upsert replaces the group named g.Name, or inserts it when it does not
+// exist yet.
+func (_self *AccountGroupRepository) Upsert(ctx context.Context, g *domain.AccountGroup) error {
+	existing, err := _self.FindByName(ctx, g.Name)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if g.AccountIds == nil {
+		g.AccountIds = []string{}
+	}
+	if existing != nil {
+		g.ID = existing.ID
+		g.CreatedAt = existing.CreatedAt
+		g.UpdatedAt = time.Now().UTC()
+		return _self.repo.UpdateByObjectID(ctx, g.ID, bson.M{
+			"description": g.Description,
+			"account_ids": g.AccountIds,
+		})
+	}
+	g.ID = primitive.NewObjectID()
+	g.CreatedAt = time.Now().UTC()
+	g.UpdatedAt = g.CreatedAt
+	return _self.repo.Insert(ctx, g)
+}
+
+func (_self *AccountGroupRepository) FindByName(ctx context.Context, name string) (*domain.AccountGroup, error) {
+	var result domain.AccountGroup
+	err := _self.repo.FindOne(ctx, bson.M{"name": name}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (_self *AccountGroupRepository) ListAll(ctx context.Context) ([]domain.AccountGroup, error) {
+	var result []domain.AccountGroup
+	err := _self.repo.FindMany(ctx, bson.M{}, &result)
+	return result, err
+}
+
+// ListNamesByAccount returns the names of the groups accountId belongs to.
+func (_self *AccountGroupRepository) ListNamesByAccount(ctx context.Context, accountId string) ([]string, error) {
+	var groups []domain.AccountGroup
+	if err := _self.repo.FindMany(ctx, bson.M{"account_ids": accountId}, &groups); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return names, nil
+}
+
+func (_self *AccountGroupRepository) DeleteByName(ctx context.Context, name string) error {
+	_, err := _self.repo.DeleteMany(ctx, bson.M{"name": name})
+	return err
+}
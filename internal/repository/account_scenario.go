@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,16 +18,22 @@ type IAccountScenarioRepository interface {
 	GetActiveScenarioByName(ctx context.Context, featureName string, scenario *string) (*domain.AccountScenario, error)
 	DeactivateByFeatureAndAccount(ctx context.Context, featureName string, accountId *string) error
 	DeactivateAllAccountSpecificMappings(ctx context.Context, featureName string) error
+	DeactivateByTarget(ctx context.Context, as *domain.AccountScenario) error
 	DeleteByScenarioId(ctx context.Context, scenarioId primitive.ObjectID) error
+	ListExpired(ctx context.Context, now time.Time) ([]domain.AccountScenario, error)
+	ListByFeature(ctx context.Context, featureName string) ([]domain.AccountScenario, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
 }
 
 type AccountScenarioRepository struct {
-	repo IBaseRepository
+	repo   IBaseRepository
+	groups IAccountGroupRepository
 }
 
 func NewAccountScenarioRepository(db *mongo.Database) *AccountScenarioRepository {
 	return &AccountScenarioRepository{
-		repo: NewBaseRepository(db.Collection("account_scenarios")),
+		repo:   NewBaseRepository(db.Collection("account_scenarios")),
+		groups: NewAccountGroupRepository(db),
 	}
 }
 
@@ -56,37 +63,122 @@ func (r *AccountScenarioRepository) GetByObjectID(
 }
 
 // GetActiveScenario returns the active scenario for a feature and accountId
-// If accountId is provided, it first looks for account-specific mapping, then falls back to global
+// If accountId is provided, the most specific mapping wins: the account's
+// own mapping, then one of its groups, then a matching pattern, then a
+// percentage rollout the account falls in. Otherwise it falls back to global.
+// Only activations effective now are considered; the newest one wins, so a
+// time-boxed activation overrides the permanent one until it expires.
 func (r *AccountScenarioRepository) GetActiveScenario(
 	ctx context.Context,
 	featureName string,
 	accountId *string,
 ) (*domain.AccountScenario, error) {
-	var result domain.AccountScenario
+	now := time.Now().UTC()
 
 	// If accountId is provided, try to find account-specific mapping
 	if accountId != nil {
-		err := r.repo.FindOne(ctx, bson.M{
+		result, err := r.findEffective(ctx, bson.M{
 			"feature_name": featureName,
 			"account_id":   *accountId,
-		}, &result)
+		}, now)
 
 		// If found, return it
 		if err == nil {
-			return &result, nil
+			return result, nil
+		}
+
+		if result, err := r.findCohort(ctx, featureName, *accountId, now); err != nil || result != nil {
+			return result, err
 		}
 	}
 
 	// Fallback to global mapping (account_id is null)
-	err := r.repo.FindOne(ctx, bson.M{
+	return r.findEffective(ctx, bson.M{
 		"feature_name": featureName,
 		"account_id":   nil,
-	}, &result)
+		"target":       nil,
+	}, now)
+}
 
+// findCohort returns the group, pattern or percentage mapping applying to
+// accountId, in that order, or nil when there is none.
+func (r *AccountScenarioRepository) findCohort(
+	ctx context.Context,
+	featureName string,
+	accountId string,
+	now time.Time,
+) (*domain.AccountScenario, error) {
+	groups, err := r.groups.ListNamesByAccount(ctx, accountId)
 	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if len(groups) > 0 {
+		result, err := r.findEffective(ctx, bson.M{
+			"feature_name": featureName,
+			"target":       domain.ActivationTargetGroup,
+			"group_name":   bson.M{"$in": groups},
+		}, now)
+		if err == nil {
+			return result, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	}
+
+	for _, target := range []string{domain.ActivationTargetPattern, domain.ActivationTargetPercentage} {
+		var mappings []domain.AccountScenario
+		err := r.repo.FindManyWithPagination(ctx, effectiveFilter(bson.M{
+			"feature_name": featureName,
+			"target":       target,
+		}, now), 0, 0, &mappings)
+		if err != nil {
+			return nil, err
+		}
+		// Newest first, like findEffective.
+		for i := range mappings {
+			if mappings[i].MatchesAccount(accountId) {
+				return &mappings[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findEffective returns the newest mapping matching filter whose window
+// contains now, or mongo.ErrNoDocuments.
+func (r *AccountScenarioRepository) findEffective(
+	ctx context.Context,
+	filter bson.M,
+	now time.Time,
+) (*domain.AccountScenario, error) {
+	var results []domain.AccountScenario
+	if err := r.repo.FindManyWithPagination(ctx, effectiveFilter(filter, now), 0, 1, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &results[0], nil
+}
+
+// effectiveFilter restricts filter to the mappings whose window contains now.
+func effectiveFilter(filter bson.M, now time.Time) bson.M {
+	filter["$and"] = bson.A{
+		bson.M{"$or": bson.A{bson.M{"starts_at": nil}, bson.M{"starts_at": bson.M{"$lte": now}}}},
+		bson.M{"$or": bson.A{bson.M{"expires_at": nil}, bson.M{"expires_at": bson.M{"$gt": now}}}},
+	}
+	return filter
+}
+
+// ListExpired returns the time-boxed mappings that expired at or before now.
+func (r *AccountScenarioRepository) ListExpired(
+	ctx context.Context,
+	now time.Time,
+) ([]domain.AccountScenario, error) {
+	var results []domain.AccountScenario
+	err := r.repo.FindMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}}, &results)
+	return results, err
 }
 
 func (r *AccountScenarioRepository) GetActiveScenarioByName(ctx context.Context, featureName string, scenario *string) (*domain.AccountScenario, error) {
@@ -117,13 +209,14 @@ func (r *AccountScenarioRepository) DeactivateByFeatureAndAccount(
 		filter["account_id"] = *accountId
 	} else {
 		filter["account_id"] = nil
+		filter["target"] = nil
 	}
 
 	_, err := r.repo.DeleteMany(ctx, filter)
 	return err
 }
 
-// DeactivateAllAccountSpecificMappings deletes all account-specific mappings for a feature
+// DeactivateAllAccountSpecificMappings deletes all account-specific and cohort mappings for a feature
 // (keeps only the global mapping with account_id = nil)
 func (r *AccountScenarioRepository) DeactivateAllAccountSpecificMappings(
 	ctx context.Context,
@@ -131,13 +224,58 @@ func (r *AccountScenarioRepository) DeactivateAllAccountSpecificMappings(
 ) error {
 	filter := bson.M{
 		"feature_name": featureName,
-		"account_id":   bson.M{"$ne": nil}, // Delete all where account_id is NOT nil
+		"$or": bson.A{
+			bson.M{"account_id": bson.M{"$ne": nil}}, // Delete all where account_id is NOT nil
+			bson.M{"target": bson.M{"$ne": nil}},
+		},
 	}
 
 	_, err := r.repo.DeleteMany(ctx, filter)
 	return err
 }
 
+// DeactivateByTarget deletes the mappings of the same cohort as as: the same
+// group, the same pattern or prefix, or any percentage rollout of the feature.
+func (r *AccountScenarioRepository) DeactivateByTarget(
+	ctx context.Context,
+	as *domain.AccountScenario,
+) error {
+	_, err := r.repo.DeleteMany(ctx, activationSlotFilter(as))
+	return err
+}
+
+// activationSlotFilter matches the mappings an activation like as replaces:
+// the same account, the global mapping, or the same cohort.
+func activationSlotFilter(as *domain.AccountScenario) bson.M {
+	filter := bson.M{"feature_name": as.FeatureName}
+	if as.Target == "" {
+		filter["target"] = nil
+		filter["account_id"] = nil
+		if as.AccountId != nil {
+			filter["account_id"] = *as.AccountId
+		}
+		return filter
+	}
+	filter["target"] = as.Target
+	switch as.Target {
+	case domain.ActivationTargetGroup:
+		filter["group_name"] = as.GroupName
+	case domain.ActivationTargetPattern:
+		filter["account_pattern"] = optionalString(as.AccountPattern)
+		filter["account_prefix"] = optionalString(as.AccountPrefix)
+	}
+	return filter
+}
+
+// optionalString matches a field stored with omitempty: an empty value is
+// absent from the document.
+func optionalString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
 func (r *AccountScenarioRepository) Delete(
 	ctx context.Context,
 	id primitive.ObjectID,
@@ -344,6 +344,64 @@ func TestAccountScenarioRepository_GlobalScenarioFallback(t *testing.T) {
 }
 
 // Helper function to create string pointer
+func TestAccountScenarioRepository_GetActiveScenarioCohorts(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	repo := NewAccountScenarioRepository(helper.DB)
+	groups := NewAccountGroupRepository(helper.DB)
+	ctx := helper.GetContext()
+
+	require.NoError(t, groups.Upsert(ctx, &domain.AccountGroup{Name: "qa", AccountIds: []string{"vip", "beta-9"}}))
+
+	exactID := primitive.NewObjectID()
+	groupID := primitive.NewObjectID()
+	patternID := primitive.NewObjectID()
+	percentageID := primitive.NewObjectID()
+	globalID := primitive.NewObjectID()
+	mappings := []*domain.AccountScenario{
+		{FeatureName: "feature-1", ScenarioID: globalID},
+		{FeatureName: "feature-1", ScenarioID: percentageID, Target: domain.ActivationTargetPercentage, Percentage: 100},
+		{FeatureName: "feature-1", ScenarioID: patternID, Target: domain.ActivationTargetPattern, AccountPrefix: "beta-"},
+		{FeatureName: "feature-1", ScenarioID: groupID, Target: domain.ActivationTargetGroup, GroupName: "qa"},
+		{FeatureName: "feature-1", ScenarioID: exactID, AccountId: stringPtr("vip")},
+	}
+	for _, m := range mappings {
+		require.NoError(t, repo.Create(ctx, m))
+	}
+
+	tests := []struct {
+		accountID          *string
+		expectedScenarioID primitive.ObjectID
+	}{
+		{stringPtr("vip"), exactID},          // exact wins over its group
+		{stringPtr("beta-9"), groupID},       // group wins over the prefix
+		{stringPtr("beta-1"), patternID},     // prefix wins over the rollout
+		{stringPtr("someone"), percentageID}, // 100% rollout
+		{nil, globalID},
+	}
+	for _, tt := range tests {
+		result, err := repo.GetActiveScenario(ctx, "feature-1", tt.accountID)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expectedScenarioID, result.ScenarioID, "account %v", tt.accountID)
+	}
+
+	// Replacing the rollout leaves the other cohorts alone.
+	require.NoError(t, repo.DeactivateByTarget(ctx, &domain.AccountScenario{FeatureName: "feature-1", Target: domain.ActivationTargetPercentage}))
+	result, err := repo.GetActiveScenario(ctx, "feature-1", stringPtr("someone"))
+	require.NoError(t, err)
+	assert.Equal(t, globalID, result.ScenarioID)
+	result, err = repo.GetActiveScenario(ctx, "feature-1", stringPtr("beta-1"))
+	require.NoError(t, err)
+	assert.Equal(t, patternID, result.ScenarioID)
+
+	// A global activation clears every cohort.
+	require.NoError(t, repo.DeactivateAllAccountSpecificMappings(ctx, "feature-1"))
+	result, err = repo.GetActiveScenario(ctx, "feature-1", stringPtr("beta-9"))
+	require.NoError(t, err)
+	assert.Equal(t, globalID, result.ScenarioID)
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
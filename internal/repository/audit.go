@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IAuditRepository interface {
+	Create(ctx context.Context, e *domain.AuditEntry) error
+	List(ctx context.Context, filter domain.AuditFilter, params domain.PaginationParams) ([]domain.AuditEntry, int64, error)
+}
+
+// AuditRepository stores the audit log. Like revisions, entries are never
+// updated or deleted.
+type AuditRepository struct {
+	repo IBaseRepository
+}
+
+func NewAuditRepository(db *mongo.Database) IAuditRepository {
+	return &AuditRepository{
+		repo: NewBaseRepository(db.Collection("audit_logs")),
+	}
+}
+
+func (_self *AuditRepository) Create(ctx context.Context, e *domain.AuditEntry) error {
+	e.ID = primitive.NewObjectID()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = now()
+	}
+	return _self.repo.Insert(ctx, e)
+}
+
+// List returns the matching entries, newest first.
+func (_self *AuditRepository) List(
+	ctx context.Context,
+	filter domain.AuditFilter,
+	params domain.PaginationParams,
+) ([]domain.AuditEntry, int64, error) {
+	query := bson.M{}
+	if filter.FeatureName != "" {
+		query["feature_name"] = filter.FeatureName
+	}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.Source != "" {
+		query["source"] = filter.Source
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	total, err := _self.repo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []domain.AuditEntry
+	err = _self.repo.FindManyWithPagination(ctx, query, params.Skip(), params.Limit(), &result)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
+}
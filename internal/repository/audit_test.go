@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_ListFiltersByFeatureAndTime(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	repo := NewAuditRepository(helper.DB)
+	ctx := helper.GetContext()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, feature := range []string{"f", "f", "other", "f"} {
+		require.NoError(t, repo.Create(ctx, &domain.AuditEntry{
+			Actor:       "alice",
+			Source:      domain.AuditSourceHTTP,
+			Operation:   "PUT /scenarios/:scenario_id",
+			FeatureName: feature,
+			CreatedAt:   start.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	entries, total, err := repo.List(ctx, domain.AuditFilter{
+		FeatureName: "f",
+		From:        start.Add(time.Hour),
+		To:          start.Add(4 * time.Hour),
+	}, domain.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, start.Add(3*time.Hour), entries[0].CreatedAt.UTC(), "newest first")
+	assert.Equal(t, start.Add(time.Hour), entries[1].CreatedAt.UTC())
+
+	_, total, err = repo.List(ctx, domain.AuditFilter{Source: domain.AuditSourceSlack}, domain.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
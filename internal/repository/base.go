@@ -18,9 +18,11 @@ type IBaseRepository interface {
 	FindByID(ctx context.Context, id int64, out interface{}) error
 	FindMany(ctx context.Context, filter bson.M, out interface{}) error
 	FindManyWithPagination(ctx context.Context, filter bson.M, skip int64, limit int64, out interface{}) error
+	FindManySorted(ctx context.Context, filter bson.M, sort bson.D, skip int64, limit int64, out interface{}) error
 	Count(ctx context.Context, filter bson.M) (int64, error)
 	UpdateByID(ctx context.Context, id int64, update bson.M) error
 	UpdateByObjectID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	ReplaceByObjectID(ctx context.Context, id primitive.ObjectID, doc interface{}) error
 	UpdateOne(ctx context.Context, filter bson.M, update bson.M) error
 	UpdateMany(ctx context.Context, filter bson.M, update bson.M) error
 	FindOne(ctx context.Context, filter bson.M, out interface{}) error
@@ -113,6 +115,29 @@ func (_self *BaseRepository) FindManyWithPagination(
 	return cursor.All(ctx, out)
 }
 
+// FindManySorted is FindManyWithPagination with a caller supplied sort.
+func (_self *BaseRepository) FindManySorted(
+	ctx context.Context,
+	filter bson.M,
+	sort bson.D,
+	skip int64,
+	limit int64,
+	out interface{},
+) error {
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(limit).
+		SetSort(sort)
+
+	cursor, err := _self.col.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, out)
+}
+
 func (_self *BaseRepository) Count(ctx context.Context, filter bson.M) (int64, error) {
 	return _self.col.CountDocuments(ctx, filter)
 }
@@ -147,6 +172,22 @@ func (_self *BaseRepository) UpdateByObjectID(
 	return err
 }
 
+// ReplaceByObjectID replaces the whole document, inserting it when it does
+// not exist anymore.
+func (_self *BaseRepository) ReplaceByObjectID(
+	ctx context.Context,
+	id primitive.ObjectID,
+	doc interface{},
+) error {
+	_, err := _self.col.ReplaceOne(
+		ctx,
+		bson.M{"_id": id},
+		doc,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
 func (_self *BaseRepository) UpdateOne(
 	ctx context.Context,
 	filter bson.M,
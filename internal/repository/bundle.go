@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+// BundleWrites are the documents an import creates or replaces, with their
+// IDs already set.
+type BundleWrites struct {
+	Features     []domain.Feature
+	Scenarios    []domain.Scenario
+	MockAPIs     []domain.MockAPI
+	GRPCMockAPIs []domain.GRPCMockAPI
+	Activations  []domain.AccountScenario
+}
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IBundleRepository interface {
+	// Apply replaces every document by ID, inserting the missing ones.
+	Apply(ctx context.Context, writes *BundleWrites) error
+}
+
+// BundleRepository writes an import in one transaction, so a failure leaves
+// the documents as they were.
+type BundleRepository struct {
+	db *mongo.Database
+}
+
+func NewBundleRepository(db *mongo.Database) IBundleRepository {
+	return &BundleRepository{db: db}
+}
+
+func (_self *BundleRepository) Apply(ctx context.Context, writes *BundleWrites) error {
+	return withTransaction(ctx, _self.db, func(sc mongo.SessionContext) error {
+		for i := range writes.Features {
+			if err := _self.replace(sc, "features", writes.Features[i].ID, &writes.Features[i]); err != nil {
+				return err
+			}
+		}
+		for i := range writes.Scenarios {
+			if err := _self.replace(sc, "scenarios", writes.Scenarios[i].ID, &writes.Scenarios[i]); err != nil {
+				return err
+			}
+		}
+		for i := range writes.MockAPIs {
+			if err := _self.replace(sc, "mock_apis", writes.MockAPIs[i].ID, &writes.MockAPIs[i]); err != nil {
+				return err
+			}
+		}
+		for i := range writes.GRPCMockAPIs {
+			if err := _self.replace(sc, "grpc_mock_apis", writes.GRPCMockAPIs[i].ID, &writes.GRPCMockAPIs[i]); err != nil {
+				return err
+			}
+		}
+		for i := range writes.Activations {
+			if err := _self.replace(sc, "account_scenarios", writes.Activations[i].ID, &writes.Activations[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (_self *BundleRepository) replace(sc mongo.SessionContext, collection string, id primitive.ObjectID, doc any) error {
+	_, err := _self.db.Collection(collection).ReplaceOne(sc, bson.M{"_id": id}, doc, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBundleRepository_ApplyUpsertsByID(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	ctx := helper.GetContext()
+	features := NewFeatureRepository(helper.DB)
+	scenarios := NewScenarioRepository(helper.DB)
+	repo := NewBundleRepository(helper.DB)
+
+	featureID, scenarioID := primitive.NewObjectID(), primitive.NewObjectID()
+	writes := &BundleWrites{
+		Features:  []domain.Feature{{ID: featureID, Name: "insertAd", IsActive: true}},
+		Scenarios: []domain.Scenario{{ID: scenarioID, FeatureName: "insertAd", Name: "declined", Description: "before"}},
+	}
+	err := repo.Apply(ctx, writes)
+	if errors.Is(err, ErrReplicaSetRequired) {
+		t.Skip("transactions need MongoDB running as a replica set")
+	}
+	require.NoError(t, err)
+
+	writes.Scenarios[0].Description = "after"
+	require.NoError(t, repo.Apply(ctx, &BundleWrites{Scenarios: writes.Scenarios}))
+
+	feature, err := features.FindByName(ctx, "insertAd")
+	require.NoError(t, err)
+	assert.Equal(t, featureID, feature.ID)
+	scenario, err := scenarios.FindByFeatureNameAndName(ctx, "insertAd", "declined")
+	require.NoError(t, err)
+	assert.Equal(t, scenarioID, scenario.ID)
+	assert.Equal(t, "after", scenario.Description, "the second apply replaced the document")
+}
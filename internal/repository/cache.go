@@ -24,6 +24,12 @@ const (
 	KeyFeatureTemplate = "mocktool:%s:*"
 	// mocktool:seq:<feature>:<scenario>:<account_id>:<path>:<method>:<hash_input>
 	KeySequenceTemplate = "mocktool:seq:%s:%s:%s:%s:%s:%s"
+	// mocktool:<feature>:<scenario>:grpc:<service>:<method>:<hash_input>
+	KeyGRPCMockTemplate = "mocktool:%s:%s:grpc:%s:%s:%s"
+	// mocktool:<feature>:<scenario>:grpc
+	KeyGRPCScenarioTemplate = "mocktool:%s:%s:grpc:*"
+	// mocktool:grpcseq:<feature>:<scenario>:<account_id>:<service>:<method>:<hash_input>
+	KeyGRPCSequenceTemplate = "mocktool:grpcseq:%s:%s:%s:%s:%s:%s"
 )
 
 //go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
@@ -35,6 +41,7 @@ type ICache interface {
 	IncrWithTTL(ctx context.Context, key string, ttl time.Duration) (int64, error)
 	Del(ctx context.Context, key string) error
 	InvalidAllKey(ctx context.Context, key string) error
+	Ping(ctx context.Context) error
 }
 
 type Cache struct {
@@ -142,3 +149,7 @@ func (_self Cache) InvalidAllKey(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+func (_self Cache) Ping(ctx context.Context) error {
+	return _self.redisClient.Ping(ctx).Err()
+}
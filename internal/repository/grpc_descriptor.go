@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IGRPCDescriptorRepository interface {
+	Upsert(ctx context.Context, d *domain.GRPCDescriptor) error
+	FindByServiceName(ctx context.Context, serviceName string) (*domain.GRPCDescriptor, error)
+	ListAll(ctx context.Context) ([]domain.GRPCDescriptor, error)
+	DeleteByServiceName(ctx context.Context, serviceName string) error
+}
+
+type GRPCDescriptorRepository struct {
+	repo IBaseRepository
+}
+
+func NewGRPCDescriptorRepository(db *mongo.Database) IGRPCDescriptorRepository {
+	return &GRPCDescriptorRepository{
+		repo: NewBaseRepository(db.Collection("grpc_descriptors")),
+	}
+}
+
+// Upsert replaces the descriptor stored for d.ServiceName, or inserts a new
+// document when the service has never been uploaded before.
+func (_self *GRPCDescriptorRepository) Upsert(ctx context.Context, d *domain.GRPCDescriptor) error {
+	existing, err := _self.FindByServiceName(ctx, d.ServiceName)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if existing != nil {
+		d.ID = existing.ID
+		d.CreatedAt = existing.CreatedAt
+		d.UpdatedAt = time.Now().UTC()
+		return _self.repo.UpdateByObjectID(ctx, d.ID, bson.M{
+			"source":         d.Source,
+			"files":          d.Files,
+			"descriptor_set": d.DescriptorSet,
+		})
+	}
+	d.ID = primitive.NewObjectID()
+	d.CreatedAt = time.Now().UTC()
+	d.UpdatedAt = d.CreatedAt
+	return _self.repo.Insert(ctx, d)
+}
+
+func (_self *GRPCDescriptorRepository) FindByServiceName(ctx context.Context, serviceName string) (*domain.GRPCDescriptor, error) {
+	var result domain.GRPCDescriptor
+	err := _self.repo.FindOne(ctx, bson.M{"service_name": serviceName}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (_self *GRPCDescriptorRepository) ListAll(ctx context.Context) ([]domain.GRPCDescriptor, error) {
+	var result []domain.GRPCDescriptor
+	err := _self.repo.FindMany(ctx, bson.M{}, &result)
+	return result, err
+}
+
+func (_self *GRPCDescriptorRepository) DeleteByServiceName(ctx context.Context, serviceName string) error {
+	_, err := _self.repo.DeleteMany(ctx, bson.M{"service_name": serviceName})
+	return err
+}
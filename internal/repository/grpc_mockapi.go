@@ -17,8 +17,10 @@ type IGRPCMockAPIRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.GRPCMockAPI, error)
 	FindByFeatureScenarioServiceMethodAndHash(ctx context.Context, featureName, scenarioName, serviceName, methodName, hashInput string) (*domain.GRPCMockAPI, error)
 	ListByFeatureAndScenario(ctx context.Context, featureName, scenarioName string) ([]domain.GRPCMockAPI, error)
+	ListActiveByMethod(ctx context.Context, featureName, scenarioName, serviceName, methodName string) ([]domain.GRPCMockAPI, error)
 	ListAll(ctx context.Context) ([]domain.GRPCMockAPI, error)
 	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	Replace(ctx context.Context, m *domain.GRPCMockAPI) error
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
 }
 
@@ -78,6 +80,22 @@ func (_self *GRPCMockAPIRepository) ListByFeatureAndScenario(ctx context.Context
 	return result, err
 }
 
+// ListActiveByMethod returns every active mock of one method in a scenario.
+func (_self *GRPCMockAPIRepository) ListActiveByMethod(
+	ctx context.Context,
+	featureName, scenarioName, serviceName, methodName string,
+) ([]domain.GRPCMockAPI, error) {
+	var result []domain.GRPCMockAPI
+	err := _self.repo.FindMany(ctx, bson.M{
+		"feature_name":  featureName,
+		"scenario_name": scenarioName,
+		"service_name":  serviceName,
+		"method_name":   methodName,
+		"is_active":     true,
+	}, &result)
+	return result, err
+}
+
 func (_self *GRPCMockAPIRepository) ListAll(ctx context.Context) ([]domain.GRPCMockAPI, error) {
 	var result []domain.GRPCMockAPI
 	err := _self.repo.FindMany(ctx, bson.M{"is_active": true}, &result)
@@ -88,6 +106,11 @@ func (_self *GRPCMockAPIRepository) UpdateByID(ctx context.Context, id primitive
 	return _self.repo.UpdateByObjectID(ctx, id, update)
 }
 
+// Replace writes m over the document with its ID, recreating it if deleted.
+func (_self *GRPCMockAPIRepository) Replace(ctx context.Context, m *domain.GRPCMockAPI) error {
+	return _self.repo.ReplaceByObjectID(ctx, m.ID, m)
+}
+
 func (_self *GRPCMockAPIRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	_, err := _self.repo.DeleteOne(ctx, id)
 	return err
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IGRPCProxyRepository interface {
+	Upsert(ctx context.Context, p *domain.GRPCProxy) error
+	FindByServiceName(ctx context.Context, serviceName string) (*domain.GRPCProxy, error)
+	ListAll(ctx context.Context) ([]domain.GRPCProxy, error)
+	DeleteByServiceName(ctx context.Context, serviceName string) error
+}
+
+type GRPCProxyRepository struct {
+	repo IBaseRepository
+}
+
+func NewGRPCProxyRepository(db *mongo.Database) IGRPCProxyRepository {
+	return &GRPCProxyRepository{
+		repo: NewBaseRepository(db.Collection("grpc_proxies")),
+	}
+}
+
+// Upsert replaces the proxy of p.ServiceName, or inserts a new document when
+// the service has no proxy yet.
+func (_self *GRPCProxyRepository) Upsert(ctx context.Context, p *domain.GRPCProxy) error {
+	existing, err := _self.FindByServiceName(ctx, p.ServiceName)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if existing != nil {
+		p.ID = existing.ID
+		p.CreatedAt = existing.CreatedAt
+		p.UpdatedAt = time.Now().UTC()
+		return _self.repo.UpdateByObjectID(ctx, p.ID, bson.M{
+			"upstream":        p.Upstream,
+			"tls":             p.TLS,
+			"record":          p.Record,
+			"record_feature":  p.RecordFeature,
+			"record_scenario": p.RecordScenario,
+			"is_active":       p.IsActive,
+		})
+	}
+	p.ID = primitive.NewObjectID()
+	p.CreatedAt = time.Now().UTC()
+	p.UpdatedAt = p.CreatedAt
+	return _self.repo.Insert(ctx, p)
+}
+
+func (_self *GRPCProxyRepository) FindByServiceName(ctx context.Context, serviceName string) (*domain.GRPCProxy, error) {
+	var result domain.GRPCProxy
+	err := _self.repo.FindOne(ctx, bson.M{"service_name": serviceName}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (_self *GRPCProxyRepository) ListAll(ctx context.Context) ([]domain.GRPCProxy, error) {
+	var result []domain.GRPCProxy
+	err := _self.repo.FindMany(ctx, bson.M{}, &result)
+	return result, err
+}
+
+func (_self *GRPCProxyRepository) DeleteByServiceName(ctx context.Context, serviceName string) error {
+	_, err := _self.repo.DeleteMany(ctx, bson.M{"service_name": serviceName})
+	return err
+}
@@ -14,12 +14,14 @@ import (
 type IMockAPIRepository interface {
 	ListAllActiveAPIs(ctx context.Context) ([]domain.MockAPI, error)
 	ListActiveAPIsByScenario(ctx context.Context, scenarios []string) ([]domain.MockAPI, error)
+	ListByFeatureAndScenario(ctx context.Context, featureName, scenarioName string) ([]domain.MockAPI, error)
 	ListByScenarioNamePaginated(ctx context.Context, scenarioName string, params domain.PaginationParams) ([]domain.MockAPI, int64, error)
 	SearchByScenarioAndNameOrPath(ctx context.Context, scenarioName, query string, params domain.PaginationParams) ([]domain.MockAPI, int64, error)
 	Create(ctx context.Context, m *domain.MockAPI) error
 	FindByObjectID(ctx context.Context, id primitive.ObjectID) (*domain.MockAPI, error)
 	FindByNameAndFeatureAndScenario(ctx context.Context, mockName, featureName, sceanrioName string) (*domain.MockAPI, error)
 	UpdateByObjectID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	Replace(ctx context.Context, m *domain.MockAPI) error
 	DeletByObjectID(ctx context.Context, id primitive.ObjectID) error
 	FindByFeatureScenarioPathMethodAndHash(ctx context.Context, featureName, scenarioName, path, method, hashInput string) (*domain.MockAPI, error)
 	FindCandidatesByFeatureScenarioAndMethod(ctx context.Context, featureName, scenarioName, method string) ([]domain.MockAPI, error)
@@ -84,6 +86,18 @@ func (_self *MockAPIRepository) ListByScenarioName(
 	return result, err
 }
 
+// ListByFeatureAndScenario lists the mocks of a scenario, or of the whole
+// feature when scenarioName is empty.
+func (_self *MockAPIRepository) ListByFeatureAndScenario(ctx context.Context, featureName, scenarioName string) ([]domain.MockAPI, error) {
+	filter := bson.M{"feature_name": featureName}
+	if scenarioName != "" {
+		filter["scenario_name"] = scenarioName
+	}
+	var result []domain.MockAPI
+	err := _self.repo.FindMany(ctx, filter, &result)
+	return result, err
+}
+
 func (_self *MockAPIRepository) ListByScenarioNamePaginated(
 	ctx context.Context,
 	scenarioName string,
@@ -253,6 +267,11 @@ func (_self *MockAPIRepository) UpdateByObjectID(ctx context.Context, id primiti
 	return _self.repo.UpdateByObjectID(ctx, id, update)
 }
 
+// Replace writes m over the document with its ID, recreating it if deleted.
+func (_self *MockAPIRepository) Replace(ctx context.Context, m *domain.MockAPI) error {
+	return _self.repo.ReplaceByObjectID(ctx, m.ID, m)
+}
+
 func (_self *MockAPIRepository) DeleteByScenarioName(ctx context.Context, scenarioName string) error {
 	filter := bson.M{
 		"scenario_name": scenarioName,
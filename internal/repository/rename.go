@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IRenameRepository interface {
+	// RenameFeature renames a feature and every document referring to it by
+	// name. It returns the number of documents updated per collection, or
+	// mongo.ErrNoDocuments when the feature does not exist.
+	RenameFeature(ctx context.Context, oldName, newName string) (map[string]int64, error)
+	// RenameScenario does the same for a scenario of featureName.
+	RenameScenario(ctx context.Context, featureName, oldName, newName string) (map[string]int64, error)
+}
+
+// RenameRepository rewrites names across collections in one transaction, so
+// a failure leaves every document with its old name. Transactions need
+// MongoDB to run as a replica set (a single node is enough).
+type RenameRepository struct {
+	db *mongo.Database
+}
+
+func NewRenameRepository(db *mongo.Database) IRenameRepository {
+	return &RenameRepository{db: db}
+}
+
+// renameStep is one update of the cascade.
+type renameStep struct {
+	collection string
+	filter     bson.M
+	update     bson.M
+	opts       *options.UpdateOptions
+	// trashEntries, when set, limits the step to the trash documents of the
+	// trash entries it matches.
+	trashEntries bson.M
+}
+
+func (_self *RenameRepository) RenameFeature(ctx context.Context, oldName, newName string) (map[string]int64, error) {
+	now := time.Now().UTC()
+	return _self.run(ctx, []renameStep{
+		{collection: "features", filter: bson.M{"name": oldName}, update: bson.M{"$set": bson.M{"name": newName, "updated_at": now}}},
+		{collection: "scenarios", filter: bson.M{"feature_name": oldName}, update: bson.M{"$set": bson.M{"feature_name": newName}}},
+		{collection: "mock_apis", filter: bson.M{"feature_name": oldName}, update: bson.M{"$set": bson.M{"feature_name": newName}}},
+		{collection: "grpc_mock_apis", filter: bson.M{"feature_name": oldName}, update: bson.M{"$set": bson.M{"feature_name": newName}}},
+		{collection: "account_scenarios", filter: bson.M{"feature_name": oldName}, update: bson.M{"$set": bson.M{"feature_name": newName}}},
+		{collection: "grpc_proxies", filter: bson.M{"record_feature": oldName}, update: bson.M{"$set": bson.M{"record_feature": newName}}},
+		{
+			collection: "session_profiles",
+			filter:     bson.M{"entries.feature_name": oldName},
+			update:     bson.M{"$set": bson.M{"entries.$[e].feature_name": newName}},
+			opts: options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []any{bson.M{"e.feature_name": oldName}},
+			}),
+		},
+		// A session lists each feature once.
+		{collection: "sessions", filter: bson.M{"features": oldName}, update: bson.M{"$set": bson.M{"features.$": newName}}},
+		// Trashed scenarios follow their feature so they can be restored into
+		// it. A trashed feature of the same name is another feature and keeps
+		// its name.
+		{
+			collection:   trashDocumentsCollection,
+			filter:       bson.M{"document.feature_name": oldName},
+			update:       bson.M{"$set": bson.M{"document.feature_name": newName}},
+			trashEntries: trashedScenariosOf(oldName),
+		},
+		{collection: trashCollection, filter: trashedScenariosOf(oldName), update: bson.M{"$set": bson.M{"feature_name": newName}}},
+	})
+}
+
+// trashedScenariosOf matches the trash entries of the scenarios of a feature.
+func trashedScenariosOf(featureName string) bson.M {
+	return bson.M{"feature_name": featureName, "kind": bson.M{"$ne": domain.TrashKindFeature}}
+}
+
+func (_self *RenameRepository) RenameScenario(ctx context.Context, featureName, oldName, newName string) (map[string]int64, error) {
+	now := time.Now().UTC()
+	return _self.run(ctx, []renameStep{
+		{
+			collection: "scenarios",
+			filter:     bson.M{"feature_name": featureName, "name": oldName},
+			update:     bson.M{"$set": bson.M{"name": newName, "updated_at": now}},
+		},
+		{
+			collection: "mock_apis",
+			filter:     bson.M{"feature_name": featureName, "scenario_name": oldName},
+			update:     bson.M{"$set": bson.M{"scenario_name": newName}},
+		},
+		{
+			collection: "grpc_mock_apis",
+			filter:     bson.M{"feature_name": featureName, "scenario_name": oldName},
+			update:     bson.M{"$set": bson.M{"scenario_name": newName}},
+		},
+		{
+			collection: "grpc_proxies",
+			filter:     bson.M{"record_feature": featureName, "record_scenario": oldName},
+			update:     bson.M{"$set": bson.M{"record_scenario": newName}},
+		},
+		{
+			collection: "session_profiles",
+			filter:     bson.M{"entries": bson.M{"$elemMatch": bson.M{"feature_name": featureName, "scenario_name": oldName}}},
+			update:     bson.M{"$set": bson.M{"entries.$[e].scenario_name": newName}},
+			opts: options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []any{bson.M{"e.feature_name": featureName, "e.scenario_name": oldName}},
+			}),
+		},
+	})
+}
+
+// run applies the steps in one transaction. The first step renames the
+// document itself and aborts the transaction when it matches nothing.
+func (_self *RenameRepository) run(ctx context.Context, steps []renameStep) (map[string]int64, error) {
+	updated := make(map[string]int64, len(steps))
+	err := withTransaction(ctx, _self.db, func(sc mongo.SessionContext) error {
+		// The transaction may be retried.
+		clear(updated)
+		for i, step := range steps {
+			opts := step.opts
+			if opts == nil {
+				opts = options.Update()
+			}
+			filter, err := _self.stepFilter(sc, step)
+			if err != nil {
+				return err
+			}
+			res, err := _self.db.Collection(step.collection).UpdateMany(sc, filter, step.update, opts)
+			if err != nil {
+				return err
+			}
+			if i == 0 && res.MatchedCount == 0 {
+				return mongo.ErrNoDocuments
+			}
+			updated[step.collection] += res.ModifiedCount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// stepFilter adds the ids of the trash entries a step is limited to.
+func (_self *RenameRepository) stepFilter(sc mongo.SessionContext, step renameStep) (bson.M, error) {
+	if step.trashEntries == nil {
+		return step.filter, nil
+	}
+	cursor, err := _self.db.Collection(trashCollection).Find(sc, step.trashEntries, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var entries []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(sc, &entries); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	filter := bson.M{"trash_id": bson.M{"$in": ids}}
+	for k, v := range step.filter {
+		filter[k] = v
+	}
+	return filter, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRenameRepository_RenameFeatureCascades(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	ctx := helper.GetContext()
+	features := NewFeatureRepository(helper.DB)
+	scenarios := NewScenarioRepository(helper.DB)
+	mockAPIs := NewMockAPIRepository(helper.DB)
+	profiles := NewSessionProfileRepository(helper.DB)
+	repo := NewRenameRepository(helper.DB)
+
+	require.NoError(t, features.Create(ctx, &domain.Feature{Name: "insertAd"}))
+	require.NoError(t, scenarios.Create(ctx, &domain.Scenario{FeatureName: "insertAd", Name: "declined"}))
+	require.NoError(t, mockAPIs.Create(ctx, &domain.MockAPI{FeatureName: "insertAd", ScenarioName: "declined", Name: "pay", Path: "/pay", Method: "POST"}))
+	require.NoError(t, profiles.Upsert(ctx, &domain.SessionProfile{
+		Name: "e2e",
+		Entries: []domain.SessionProfileEntry{
+			{FeatureName: "search", ScenarioName: "empty"},
+			{FeatureName: "insertAd", ScenarioName: "declined"},
+		},
+	}))
+
+	updated, err := repo.RenameFeature(ctx, "insertAd", "postAd")
+	if errors.Is(err, ErrReplicaSetRequired) {
+		t.Skip("transactions need MongoDB running as a replica set")
+	}
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated["features"])
+	assert.Equal(t, int64(1), updated["mock_apis"])
+
+	scenario, err := scenarios.FindByFeatureNameAndName(ctx, "postAd", "declined")
+	require.NoError(t, err)
+	assert.Equal(t, "declined", scenario.Name)
+	count, err := helper.DB.Collection("mock_apis").CountDocuments(ctx, bson.M{"feature_name": "postAd"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	profile, err := profiles.FindByName(ctx, "e2e")
+	require.NoError(t, err)
+	assert.Equal(t, "search", profile.Entries[0].FeatureName, "other entries are kept")
+	assert.Equal(t, "postAd", profile.Entries[1].FeatureName)
+
+	_, err = repo.RenameFeature(ctx, "insertAd", "other")
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
+
+func TestRenameRepository_RenameFeatureMovesTrashedScenarios(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	ctx := helper.GetContext()
+	features := NewFeatureRepository(helper.DB)
+	scenarios := NewScenarioRepository(helper.DB)
+	mockAPIs := NewMockAPIRepository(helper.DB)
+	trash := NewTrashRepository(helper.DB)
+	repo := NewRenameRepository(helper.DB)
+
+	require.NoError(t, features.Create(ctx, &domain.Feature{Name: "insertAd"}))
+	require.NoError(t, scenarios.Create(ctx, &domain.Scenario{FeatureName: "insertAd", Name: "declined"}))
+	require.NoError(t, mockAPIs.Create(ctx, &domain.MockAPI{FeatureName: "insertAd", ScenarioName: "declined", Name: "pay", Path: "/pay", Method: "POST"}))
+
+	now := time.Now().UTC()
+	entry := &domain.TrashEntry{Kind: domain.TrashKindScenario, FeatureName: "insertAd", ScenarioName: "declined", ExpiresAt: now.Add(time.Hour)}
+	err := trash.TrashScenario(ctx, entry)
+	if errors.Is(err, ErrReplicaSetRequired) {
+		t.Skip("transactions need MongoDB running as a replica set")
+	}
+	require.NoError(t, err)
+	// An earlier feature of the same name, deleted before this one was created.
+	deletedFeature := &domain.TrashEntry{ID: primitive.NewObjectID(), Kind: domain.TrashKindFeature, FeatureName: "insertAd", ExpiresAt: now.Add(time.Hour)}
+	_, err = helper.DB.Collection(trashCollection).InsertOne(ctx, deletedFeature)
+	require.NoError(t, err)
+
+	updated, err := repo.RenameFeature(ctx, "insertAd", "postAd")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated[trashCollection])
+	assert.Equal(t, int64(2), updated[trashDocumentsCollection], "the scenario and its mock API")
+
+	moved, err := trash.FindByID(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "postAd", moved.FeatureName)
+	kept, err := trash.FindByID(ctx, deletedFeature.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "insertAd", kept.FeatureName, "a trashed feature keeps its name")
+
+	_, err = trash.Restore(ctx, entry.ID)
+	require.NoError(t, err)
+	scenario, err := scenarios.FindByFeatureNameAndName(ctx, "postAd", "declined")
+	require.NoError(t, err)
+	assert.Equal(t, "declined", scenario.Name)
+	count, err := helper.DB.Collection("mock_apis").CountDocuments(ctx, bson.M{"feature_name": "postAd", "scenario_name": "declined"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
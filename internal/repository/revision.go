@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type IRevisionRepository interface {
+	Create(ctx context.Context, r *domain.Revision) error
+	FindLatest(ctx context.Context, kind string, resourceID primitive.ObjectID) (*domain.Revision, error)
+	FindByVersion(ctx context.Context, kind string, resourceID primitive.ObjectID, version int) (*domain.Revision, error)
+	ListByResource(ctx context.Context, kind string, resourceID primitive.ObjectID, params domain.PaginationParams) ([]domain.Revision, int64, error)
+}
+
+// RevisionRepository stores the revision log. Revisions are immutable, so
+// there is no update or delete.
+type RevisionRepository struct {
+	repo IBaseRepository
+}
+
+func NewRevisionRepository(db *mongo.Database) IRevisionRepository {
+	return &RevisionRepository{
+		repo: NewBaseRepository(db.Collection("revisions")),
+	}
+}
+
+var newestVersionFirst = bson.D{{Key: "version", Value: -1}}
+
+func (_self *RevisionRepository) Create(ctx context.Context, r *domain.Revision) error {
+	r.ID = primitive.NewObjectID()
+	r.CreatedAt = now()
+	return _self.repo.Insert(ctx, r)
+}
+
+// FindLatest returns the newest revision of a document, mongo.ErrNoDocuments
+// when it has none.
+func (_self *RevisionRepository) FindLatest(
+	ctx context.Context,
+	kind string,
+	resourceID primitive.ObjectID,
+) (*domain.Revision, error) {
+	var result []domain.Revision
+	filter := bson.M{"kind": kind, "resource_id": resourceID}
+	if err := _self.repo.FindManySorted(ctx, filter, newestVersionFirst, 0, 1, &result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &result[0], nil
+}
+
+func (_self *RevisionRepository) FindByVersion(
+	ctx context.Context,
+	kind string,
+	resourceID primitive.ObjectID,
+	version int,
+) (*domain.Revision, error) {
+	var result domain.Revision
+	filter := bson.M{"kind": kind, "resource_id": resourceID, "version": version}
+	if err := _self.repo.FindOne(ctx, filter, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListByResource lists the revisions of a document, newest first.
+func (_self *RevisionRepository) ListByResource(
+	ctx context.Context,
+	kind string,
+	resourceID primitive.ObjectID,
+	params domain.PaginationParams,
+) ([]domain.Revision, int64, error) {
+	filter := bson.M{"kind": kind, "resource_id": resourceID}
+
+	total, err := _self.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []domain.Revision
+	err = _self.repo.FindManySorted(ctx, filter, newestVersionFirst, params.Skip(), params.Limit(), &result)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRevisionRepository_LatestAndList(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	repo := NewRevisionRepository(helper.DB)
+	ctx := helper.GetContext()
+	id := primitive.NewObjectID()
+
+	_, err := repo.FindLatest(ctx, domain.RevisionKindMockAPI, id)
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	for version := 1; version <= 3; version++ {
+		require.NoError(t, repo.Create(ctx, &domain.Revision{
+			Kind:       domain.RevisionKindMockAPI,
+			ResourceID: id,
+			Version:    version,
+			Action:     domain.RevisionActionUpdate,
+			Snapshot:   bson.M{"status_code": int32(200 + version)},
+		}))
+	}
+	// Same id, other kind.
+	require.NoError(t, repo.Create(ctx, &domain.Revision{Kind: domain.RevisionKindScenario, ResourceID: id, Version: 9}))
+
+	latest, err := repo.FindLatest(ctx, domain.RevisionKindMockAPI, id)
+	require.NoError(t, err)
+	assert.Equal(t, 3, latest.Version)
+
+	second, err := repo.FindByVersion(ctx, domain.RevisionKindMockAPI, id, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int32(202), second.Snapshot["status_code"])
+
+	revisions, total, err := repo.ListByResource(ctx, domain.RevisionKindMockAPI, id, domain.PaginationParams{Page: 1, PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 3, revisions[0].Version)
+	assert.Equal(t, 2, revisions[1].Version)
+}
+
+func TestScenarioRepository_ReplaceRecreatesDeleted(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	repo := NewScenarioRepository(helper.DB)
+	ctx := helper.GetContext()
+
+	scenario := &domain.Scenario{FeatureName: "f", Name: "s", Description: "before"}
+	require.NoError(t, repo.Create(ctx, scenario))
+	require.NoError(t, repo.DeleteByObjectID(ctx, scenario.ID))
+
+	scenario.Description = "restored"
+	require.NoError(t, repo.Replace(ctx, scenario))
+
+	result, err := repo.GetByObjectID(ctx, scenario.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "restored", result.Description)
+}
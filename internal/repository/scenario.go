@@ -14,9 +14,11 @@ import (
 type IScenarioRepository interface {
 	Create(ctx context.Context, s *domain.Scenario) error
 	UpdateByObjectID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	Replace(ctx context.Context, s *domain.Scenario) error
 	GetByObjectID(ctx context.Context, id primitive.ObjectID) (*domain.Scenario, error)
 	DeleteByObjectID(ctx context.Context, id primitive.ObjectID) error
 	DeleteByFeatureName(ctx context.Context, featureName string) error
+	ListByFeatureName(ctx context.Context, featureName string) ([]domain.Scenario, error)
 	ListByFeatureNamePaginated(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.Scenario, int64, error)
 	SearchByFeatureAndName(ctx context.Context, featureName, query string, params domain.PaginationParams) ([]domain.Scenario, int64, error)
 	FindByFeatureNameAndName(ctx context.Context, featureName, name string) (*domain.Scenario, error)
@@ -31,6 +33,12 @@ func NewScenarioRepository(db *mongo.Database) *ScenarioRepository {
 	}
 }
 
+func (r *ScenarioRepository) ListByFeatureName(ctx context.Context, featureName string) ([]domain.Scenario, error) {
+	var result []domain.Scenario
+	err := r.repo.FindMany(ctx, bson.M{"feature_name": featureName}, &result)
+	return result, err
+}
+
 func (r *ScenarioRepository) ListByFeatureNamePaginated(
 	ctx context.Context,
 	featureName string,
@@ -98,6 +106,11 @@ func (r *ScenarioRepository) UpdateByObjectID(
 	return r.repo.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
 }
 
+// Replace writes s over the document with its ID, recreating it if deleted.
+func (r *ScenarioRepository) Replace(ctx context.Context, s *domain.Scenario) error {
+	return r.repo.ReplaceByObjectID(ctx, s.ID, s)
+}
+
 func (r *ScenarioRepository) GetByObjectID(
 	ctx context.Context,
 	id primitive.ObjectID,
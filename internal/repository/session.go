@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type ISessionRepository interface {
+	Create(ctx context.Context, s *domain.Session) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Session, error)
+	FindByAccount(ctx context.Context, accountId string) (*domain.Session, error)
+	ListAll(ctx context.Context) ([]domain.Session, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// SessionRepository stores the applied session profiles.
+type SessionRepository struct {
+	repo IBaseRepository
+}
+
+func NewSessionRepository(db *mongo.Database) ISessionRepository {
+	return &SessionRepository{
+		repo: NewBaseRepository(db.Collection("sessions")),
+	}
+}
+
+func (_self *SessionRepository) Create(ctx context.Context, s *domain.Session) error {
+	s.ID = primitive.NewObjectID()
+	return _self.repo.Insert(ctx, s)
+}
+
+func (_self *SessionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Session, error) {
+	var result domain.Session
+	if err := _self.repo.FindOne(ctx, bson.M{"_id": id}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindByAccount returns the session applied to accountId, or
+// mongo.ErrNoDocuments.
+func (_self *SessionRepository) FindByAccount(ctx context.Context, accountId string) (*domain.Session, error) {
+	var result domain.Session
+	if err := _self.repo.FindOne(ctx, bson.M{"account_id": accountId}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (_self *SessionRepository) ListAll(ctx context.Context) ([]domain.Session, error) {
+	var result []domain.Session
+	err := _self.repo.FindMany(ctx, bson.M{}, &result)
+	return result, err
+}
+
+func (_self *SessionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := _self.repo.DeleteOne(ctx, id)
+	return err
+}
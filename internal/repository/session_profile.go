@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type ISessionProfileRepository interface {
+	Upsert(ctx context.Context, p *domain.SessionProfile) error
+	FindByName(ctx context.Context, name string) (*domain.SessionProfile, error)
+	ListAll(ctx context.Context) ([]domain.SessionProfile, error)
+	DeleteByName(ctx context.Context, name string) error
+}
+
+type SessionProfileRepository struct {
+	repo IBaseRepository
+}
+
+func NewSessionProfileRepository(db *mongo.Database) ISessionProfileRepository {
+	return &SessionProfileRepository{
+		repo: NewBaseRepository(db.Collection("session_profiles")),
+	}
+}
+
+// Upsert replaces the profile named p.Name, or inserts it when it does not
+// exist yet.
+func (_self *SessionProfileRepository) Upsert(ctx context.Context, p *domain.SessionProfile) error {
+	existing, err := _self.FindByName(ctx, p.Name)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if p.Entries == nil {
+		p.Entries = []domain.SessionProfileEntry{}
+	}
+	if existing != nil {
+		p.ID = existing.ID
+		p.CreatedAt = existing.CreatedAt
+		p.UpdatedAt = time.Now().UTC()
+		return _self.repo.UpdateByObjectID(ctx, p.ID, bson.M{
+			"description": p.Description,
+			"entries":     p.Entries,
+			"updated_at":  p.UpdatedAt,
+		})
+	}
+	p.ID = primitive.NewObjectID()
+	p.CreatedAt = time.Now().UTC()
+	p.UpdatedAt = p.CreatedAt
+	return _self.repo.Insert(ctx, p)
+}
+
+func (_self *SessionProfileRepository) FindByName(ctx context.Context, name string) (*domain.SessionProfile, error) {
+	var result domain.SessionProfile
+	err := _self.repo.FindOne(ctx, bson.M{"name": name}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (_self *SessionProfileRepository) ListAll(ctx context.Context) ([]domain.SessionProfile, error) {
+	var result []domain.SessionProfile
+	err := _self.repo.FindMany(ctx, bson.M{}, &result)
+	return result, err
+}
+
+func (_self *SessionProfileRepository) DeleteByName(ctx context.Context, name string) error {
+	return _self.repo.DeleteOneByFilter(ctx, bson.M{"name": name})
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestSessionRepository_FindByAccountAndDelete(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	repo := NewSessionRepository(helper.DB)
+	ctx := helper.GetContext()
+
+	session := &domain.Session{
+		ProfileName: "e2e",
+		AccountId:   "acc-1",
+		Features:    []string{"checkout"},
+		Activations: []primitive.ObjectID{primitive.NewObjectID()},
+		CreatedAt:   time.Now().UTC(),
+	}
+	require.NoError(t, repo.Create(ctx, session))
+	assert.False(t, session.ID.IsZero())
+
+	found, err := repo.FindByAccount(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, found.ID)
+	assert.Equal(t, session.Activations, found.Activations)
+
+	_, err = repo.FindByAccount(ctx, "acc-2")
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	require.NoError(t, repo.Delete(ctx, session.ID))
+	_, err = repo.FindByID(ctx, session.ID)
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
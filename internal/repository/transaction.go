@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrReplicaSetRequired is returned instead of starting a transaction on a
+// standalone MongoDB server, which cannot run one.
+var ErrReplicaSetRequired = errors.New("MongoDB must run as a replica set (a single node is enough) to write this change in a transaction")
+
+// withTransaction runs fn in a transaction, retried as the driver sees fit.
+// Transactions need MongoDB to run as a replica set or behind mongos; on a
+// standalone server it returns ErrReplicaSetRequired before anything is
+// written.
+func withTransaction(ctx context.Context, db *mongo.Database, fn func(sc mongo.SessionContext) error) error {
+	if err := requireReplicaSet(ctx, db); err != nil {
+		return err
+	}
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// requireReplicaSet asks the server with hello whether it belongs to a
+// replica set or is a mongos router.
+func requireReplicaSet(ctx context.Context, db *mongo.Database) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := db.Client().Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return err
+	}
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return ErrReplicaSetRequired
+	}
+	return nil
+}
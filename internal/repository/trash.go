@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+const (
+	trashCollection          = "trash"
+	trashDocumentsCollection = "trash_documents"
+	// trashBatchSize bounds the documents written by one insert.
+	trashBatchSize = 500
+)
+
+// ErrScenarioHasChildren is returned when trashing a scenario other
+// scenarios inherit from.
+var ErrScenarioHasChildren = errors.New("scenario has child scenarios")
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/repository/$GOFILE.mock.go -package=$GOPACKAGE
+type ITrashRepository interface {
+	// TrashFeature moves the feature entry.FeatureName with its scenarios,
+	// mocks and activations to the trash, filling in the rest of entry. It
+	// returns mongo.ErrNoDocuments when the feature does not exist.
+	TrashFeature(ctx context.Context, entry *domain.TrashEntry) error
+	// TrashScenario does the same for the scenario entry.ScenarioName of
+	// entry.FeatureName, or returns ErrScenarioHasChildren when other
+	// scenarios inherit from it.
+	TrashScenario(ctx context.Context, entry *domain.TrashEntry) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error)
+	// List returns the entries, of one feature when featureName is set,
+	// newest first.
+	List(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.TrashEntry, int64, error)
+	// Restore writes the documents of an entry back and removes the entry
+	// with them. Activations whose account or cohort got another scenario
+	// since the delete are left out and counted in DroppedActivations.
+	Restore(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error)
+	// Delete purges an entry and its documents, mongo.ErrNoDocuments when
+	// there is none.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// DeleteExpired purges the entries expired at now with their documents.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TrashRepository moves documents between their collection and the trash in
+// one transaction, so a delete or a restore is never left half done.
+type TrashRepository struct {
+	db   *mongo.Database
+	repo IBaseRepository
+}
+
+func NewTrashRepository(db *mongo.Database) ITrashRepository {
+	return &TrashRepository{
+		db:   db,
+		repo: NewBaseRepository(db.Collection(trashCollection)),
+	}
+}
+
+// trashSelection picks the documents of one collection.
+type trashSelection struct {
+	collection string
+	filter     bson.M
+}
+
+func (_self *TrashRepository) TrashFeature(ctx context.Context, entry *domain.TrashEntry) error {
+	feature := entry.FeatureName
+	return _self.move(ctx, entry, trashSelection{"features", bson.M{"name": feature}}, nil, func(primitive.ObjectID) []trashSelection {
+		return []trashSelection{
+			{"scenarios", bson.M{"feature_name": feature}},
+			{"mock_apis", bson.M{"feature_name": feature}},
+			{"grpc_mock_apis", bson.M{"feature_name": feature}},
+			{"account_scenarios", bson.M{"feature_name": feature}},
+		}
+	})
+}
+
+func (_self *TrashRepository) TrashScenario(ctx context.Context, entry *domain.TrashEntry) error {
+	feature, scenario := entry.FeatureName, entry.ScenarioName
+	root := trashSelection{"scenarios", bson.M{"feature_name": feature, "name": scenario}}
+	check := func(sc mongo.SessionContext, doc bson.Raw) error {
+		entry.ParentID = nil
+		if parentID, ok := doc.Lookup("parent_id").ObjectIDOK(); ok {
+			entry.ParentID = &parentID
+		}
+		// A child would be left inheriting from a scenario that is gone.
+		children, err := _self.db.Collection("scenarios").CountDocuments(sc, bson.M{"parent_id": entry.DocumentID})
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrScenarioHasChildren
+		}
+		return nil
+	}
+	return _self.move(ctx, entry, root, check, func(id primitive.ObjectID) []trashSelection {
+		return []trashSelection{
+			{"mock_apis", bson.M{"feature_name": feature, "scenario_name": scenario}},
+			{"grpc_mock_apis", bson.M{"feature_name": feature, "scenario_name": scenario}},
+			{"account_scenarios", bson.M{"scenario_id": id}},
+		}
+	})
+}
+
+// move deletes the root document and the related ones, storing each of them
+// as a TrashDocument of entry. check, when set, can refuse the root document
+// before anything is deleted.
+func (_self *TrashRepository) move(
+	ctx context.Context,
+	entry *domain.TrashEntry,
+	root trashSelection,
+	check func(sc mongo.SessionContext, doc bson.Raw) error,
+	related func(rootID primitive.ObjectID) []trashSelection,
+) error {
+	return withTransaction(ctx, _self.db, func(sc mongo.SessionContext) error {
+		// The transaction may be retried.
+		entry.ID = primitive.NewObjectID()
+		entry.Counts = map[string]int{}
+
+		var doc bson.Raw
+		if err := _self.db.Collection(root.collection).FindOne(sc, root.filter).Decode(&doc); err != nil {
+			return err
+		}
+		entry.DocumentID, _ = doc.Lookup("_id").ObjectIDOK()
+		root.filter = bson.M{"_id": entry.DocumentID}
+		if check != nil {
+			if err := check(sc, doc); err != nil {
+				return err
+			}
+		}
+
+		trashed := &trashBatch{col: _self.db.Collection(trashDocumentsCollection)}
+		for _, sel := range append([]trashSelection{root}, related(entry.DocumentID)...) {
+			col := _self.db.Collection(sel.collection)
+			cursor, err := col.Find(sc, sel.filter)
+			if err != nil {
+				return err
+			}
+			count := 0
+			for cursor.Next(sc) {
+				err = trashed.add(sc, &domain.TrashDocument{
+					ID:         primitive.NewObjectID(),
+					TrashID:    entry.ID,
+					Collection: sel.collection,
+					Document:   append(bson.Raw(nil), cursor.Current...),
+					ExpiresAt:  entry.ExpiresAt,
+				})
+				if err != nil {
+					break
+				}
+				count++
+			}
+			if err == nil {
+				err = cursor.Err()
+			}
+			cursor.Close(sc)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				continue
+			}
+			if _, err := col.DeleteMany(sc, sel.filter); err != nil {
+				return err
+			}
+			entry.Counts[sel.collection] = count
+		}
+		if err := trashed.flush(sc); err != nil {
+			return err
+		}
+		_, err := _self.db.Collection(trashCollection).InsertOne(sc, entry)
+		return err
+	})
+}
+
+// trashBatch buffers the documents written to a collection and inserts them
+// trashBatchSize at a time.
+type trashBatch struct {
+	col  *mongo.Collection
+	docs []any
+}
+
+func (_self *trashBatch) add(ctx context.Context, doc any) error {
+	_self.docs = append(_self.docs, doc)
+	if len(_self.docs) < trashBatchSize {
+		return nil
+	}
+	return _self.flush(ctx)
+}
+
+func (_self *trashBatch) flush(ctx context.Context) error {
+	if len(_self.docs) == 0 {
+		return nil
+	}
+	_, err := _self.col.InsertMany(ctx, _self.docs)
+	_self.docs = _self.docs[:0]
+	return err
+}
+
+func (_self *TrashRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error) {
+	var result domain.TrashEntry
+	if err := _self.repo.FindOne(ctx, bson.M{"_id": id}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (_self *TrashRepository) List(
+	ctx context.Context,
+	featureName string,
+	params domain.PaginationParams,
+) ([]domain.TrashEntry, int64, error) {
+	query := bson.M{}
+	if featureName != "" {
+		query["feature_name"] = featureName
+	}
+	total, err := _self.repo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(params.Skip()).
+		SetLimit(params.Limit())
+	cursor, err := _self.db.Collection(trashCollection).Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	var result []domain.TrashEntry
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (_self *TrashRepository) Restore(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error) {
+	var entry domain.TrashEntry
+	err := withTransaction(ctx, _self.db, func(sc mongo.SessionContext) error {
+		trash := _self.db.Collection(trashCollection)
+		if err := trash.FindOne(sc, bson.M{"_id": id}).Decode(&entry); err != nil {
+			return err
+		}
+		// The transaction may be retried.
+		entry.DroppedActivations = 0
+		documents := _self.db.Collection(trashDocumentsCollection)
+		cursor, err := documents.Find(sc, bson.M{"trash_id": id})
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(sc)
+		batches := map[string]*trashBatch{}
+		for cursor.Next(sc) {
+			var doc domain.TrashDocument
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			if doc.Collection == "account_scenarios" {
+				replaced, err := _self.activationReplaced(sc, doc.Document)
+				if err != nil {
+					return err
+				}
+				if replaced {
+					entry.DroppedActivations++
+					continue
+				}
+			}
+			batch := batches[doc.Collection]
+			if batch == nil {
+				batch = &trashBatch{col: _self.db.Collection(doc.Collection)}
+				batches[doc.Collection] = batch
+			}
+			if err := batch.add(sc, doc.Document); err != nil {
+				return err
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+		for _, batch := range batches {
+			if err := batch.flush(sc); err != nil {
+				return err
+			}
+		}
+		if _, err := documents.DeleteMany(sc, bson.M{"trash_id": id}); err != nil {
+			return err
+		}
+		_, err = trash.DeleteOne(sc, bson.M{"_id": id})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// activationReplaced reports whether the account or cohort of a trashed
+// activation got another scenario since it was deleted.
+func (_self *TrashRepository) activationReplaced(sc mongo.SessionContext, doc bson.Raw) (bool, error) {
+	var as domain.AccountScenario
+	if err := bson.Unmarshal(doc, &as); err != nil {
+		return false, err
+	}
+	n, err := _self.db.Collection("account_scenarios").CountDocuments(sc, activationSlotFilter(&as), options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+func (_self *TrashRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := _self.repo.DeleteOne(ctx, id)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	// Documents left behind by a failure still expire with the entry.
+	_, err = _self.db.Collection(trashDocumentsCollection).DeleteMany(ctx, bson.M{"trash_id": id})
+	return err
+}
+
+func (_self *TrashRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	expired := bson.M{"expires_at": bson.M{"$lte": now}}
+	res, err := _self.repo.DeleteMany(ctx, expired)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := _self.db.Collection(trashDocumentsCollection).DeleteMany(ctx, expired); err != nil {
+		return res.DeletedCount, err
+	}
+	return res.DeletedCount, nil
+}
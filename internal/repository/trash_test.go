@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTrashRepository_TrashAndRestoreFeature(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	ctx := helper.GetContext()
+	features := NewFeatureRepository(helper.DB)
+	scenarios := NewScenarioRepository(helper.DB)
+	mockAPIs := NewMockAPIRepository(helper.DB)
+	repo := NewTrashRepository(helper.DB)
+
+	require.NoError(t, features.Create(ctx, &domain.Feature{Name: "insertAd"}))
+	require.NoError(t, scenarios.Create(ctx, &domain.Scenario{FeatureName: "insertAd", Name: "declined"}))
+	require.NoError(t, mockAPIs.Create(ctx, &domain.MockAPI{FeatureName: "insertAd", ScenarioName: "declined", Name: "pay", Path: "/pay", Method: "POST"}))
+
+	now := time.Now().UTC()
+	entry := &domain.TrashEntry{Kind: domain.TrashKindFeature, FeatureName: "insertAd", DeletedAt: now, ExpiresAt: now.Add(time.Hour)}
+	err := repo.TrashFeature(ctx, entry)
+	if errors.Is(err, ErrReplicaSetRequired) {
+		t.Skip("transactions need MongoDB running as a replica set")
+	}
+	require.NoError(t, err)
+	assert.Equal(t, 1, entry.Counts["scenarios"])
+	assert.Equal(t, 1, entry.Counts["mock_apis"])
+
+	feature, err := features.FindByName(ctx, "insertAd")
+	require.NoError(t, err)
+	assert.Empty(t, feature.Name, "the feature left its collection")
+	entries, total, err := repo.List(ctx, "insertAd", domain.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, entry.ID, entries[0].ID)
+	stored, err := helper.DB.Collection(trashDocumentsCollection).CountDocuments(ctx, bson.M{"trash_id": entry.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stored, "one trash document per deleted document")
+
+	_, err = repo.Restore(ctx, entry.ID)
+	require.NoError(t, err)
+	scenario, err := scenarios.FindByFeatureNameAndName(ctx, "insertAd", "declined")
+	require.NoError(t, err)
+	assert.Equal(t, "declined", scenario.Name)
+	stored, err = helper.DB.Collection(trashDocumentsCollection).CountDocuments(ctx, bson.M{"trash_id": entry.ID})
+	require.NoError(t, err)
+	assert.Zero(t, stored)
+	assert.Equal(t, mongo.ErrNoDocuments, repo.Delete(ctx, entry.ID))
+
+	err = repo.TrashFeature(ctx, &domain.TrashEntry{FeatureName: "missing"})
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
+
+func TestTrashRepository_DeleteExpired(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	ctx := helper.GetContext()
+	repo := NewTrashRepository(helper.DB)
+	now := time.Now().UTC()
+	_, err := helper.DB.Collection(trashCollection).InsertMany(ctx, []any{
+		&domain.TrashEntry{ID: primitive.NewObjectID(), FeatureName: "old", ExpiresAt: now.Add(-time.Minute)},
+		&domain.TrashEntry{ID: primitive.NewObjectID(), FeatureName: "new", ExpiresAt: now.Add(time.Hour)},
+	})
+	require.NoError(t, err)
+	_, err = helper.DB.Collection(trashDocumentsCollection).InsertMany(ctx, []any{
+		&domain.TrashDocument{ID: primitive.NewObjectID(), Collection: "features", ExpiresAt: now.Add(-time.Minute)},
+		&domain.TrashDocument{ID: primitive.NewObjectID(), Collection: "features", ExpiresAt: now.Add(time.Hour)},
+	})
+	require.NoError(t, err)
+
+	n, err := repo.DeleteExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	left, err := helper.DB.Collection(trashDocumentsCollection).CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), left, "the documents expire with their entry")
+}
+
+func TestTrashRepository_ScenarioChildrenAndReplacedActivations(t *testing.T) {
+	helper := SetupTestDB(t)
+	defer helper.Cleanup(t)
+
+	ctx := helper.GetContext()
+	scenarios := NewScenarioRepository(helper.DB)
+	activations := NewAccountScenarioRepository(helper.DB)
+	repo := NewTrashRepository(helper.DB)
+
+	base := &domain.Scenario{FeatureName: "insertAd", Name: "base"}
+	require.NoError(t, scenarios.Create(ctx, base))
+	child := &domain.Scenario{FeatureName: "insertAd", Name: "child", ParentID: &base.ID}
+	require.NoError(t, scenarios.Create(ctx, child))
+	other := &domain.Scenario{FeatureName: "insertAd", Name: "other"}
+	require.NoError(t, scenarios.Create(ctx, other))
+	account := "acc-1"
+	require.NoError(t, activations.Create(ctx, &domain.AccountScenario{FeatureName: "insertAd", ScenarioID: child.ID, AccountId: &account}))
+
+	now := time.Now().UTC()
+	err := repo.TrashScenario(ctx, &domain.TrashEntry{FeatureName: "insertAd", ScenarioName: "base", ExpiresAt: now.Add(time.Hour)})
+	if errors.Is(err, ErrReplicaSetRequired) {
+		t.Skip("transactions need MongoDB running as a replica set")
+	}
+	assert.ErrorIs(t, err, ErrScenarioHasChildren, "a parent is not trashed under its children")
+
+	entry := &domain.TrashEntry{FeatureName: "insertAd", ScenarioName: "child", ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.TrashScenario(ctx, entry))
+	require.NotNil(t, entry.ParentID)
+	assert.Equal(t, base.ID, *entry.ParentID)
+
+	// The account moved to another scenario while child was in the trash.
+	require.NoError(t, activations.Create(ctx, &domain.AccountScenario{FeatureName: "insertAd", ScenarioID: other.ID, AccountId: &account}))
+	restored, err := repo.Restore(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored.DroppedActivations)
+	active, err := activations.GetActiveScenario(ctx, "insertAd", &account)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, active.ScenarioID)
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+// Caller identifies who invokes a tool and through which channel
+// (domain.AuditSource*). The MCP server, the Slack bot and the web chat set
+// it on the context they pass to Registry.Invoke.
+type Caller struct {
+	Actor  string
+	Source string
+}
+
+type callerKey struct{}
+
+func WithCaller(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// CallerFrom returns the caller set by WithCaller, "unknown" when missing.
+func CallerFrom(ctx context.Context) Caller {
+	c, _ := ctx.Value(callerKey{}).(Caller)
+	if c.Actor == "" {
+		c.Actor = "unknown"
+	}
+	return c
+}
+
+// auditTargetArgs are the arguments naming what a tool changes, in the order
+// they appear in AuditEntry.Target.
+var auditTargetArgs = []string{"scenario", "name", "api_id", "kind", "id"}
+
+// record adds a mutating tool call to the audit log. Like cache invalidation,
+// a failure does not fail the call.
+func (r *Registry) record(ctx context.Context, t Tool, args json.RawMessage, result any, callErr error) {
+	caller := CallerFrom(ctx)
+	entry := &domain.AuditEntry{
+		Actor:     caller.Actor,
+		Source:    caller.Source,
+		Operation: t.Name,
+		Request:   jsonMap(args),
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	} else if b, err := json.Marshal(result); err == nil {
+		entry.After = jsonMap(b)
+	}
+
+	var target []string
+	for _, k := range auditTargetArgs {
+		if v, ok := entry.Request[k]; ok && v != "" {
+			target = append(target, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	entry.Target = strings.Join(target, " ")
+	for _, m := range []bson.M{entry.After, entry.Request} {
+		if feature, ok := m["feature"].(string); ok && feature != "" {
+			entry.FeatureName = feature
+			break
+		}
+	}
+	if name, ok := entry.Request["name"].(string); ok && entry.FeatureName == "" && t.Name == "create_feature" {
+		entry.FeatureName = name
+	}
+	_ = r.audit.Create(context.WithoutCancel(ctx), entry)
+}
+
+// jsonMap decodes a JSON object, nil for anything else.
+func jsonMap(raw []byte) bson.M {
+	var m bson.M
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -4,8 +4,7 @@ package tools
 // entry point used by both cmd/mcpserver and cmd/slackbot — adding or removing
 // a tool means editing exactly this slice.
 func BuildAll(d Deps) *Registry {
-	return NewRegistry(
-		// Read
+	reads := readOnly(
 		listFeatures(d),
 		listScenarios(d),
 		searchScenarios(d),
@@ -13,17 +12,27 @@ func BuildAll(d Deps) *Registry {
 		listAPIs(d),
 		searchMocks(d),
 		getMockAPICurl(d),
-
+		listRevisions(d),
+		diffRevisions(d),
+		listAuditLog(d),
+		listTrash(d),
+		exportFeature(d),
+		diffBundle(d),
+	)
+	return NewRegistry(append(reads,
 		// Write
 		createFeature(d),
 		updateFeature(d),
 		enableFeature(d),
 		createScenario(d),
 		updateScenario(d),
+		cloneFeature(d),
+		cloneScenario(d),
 		createMockAPI(d),
 		updateMockAPI(d),
 		resetMockAPICounter(d),
 		activateScenario(d),
+		restoreFromTrash(d),
 
 		// Destructive (Slack-side confirmation required)
 		disableFeature(d),
@@ -32,5 +41,15 @@ func BuildAll(d Deps) *Registry {
 		deleteMockAPI(d),
 		deleteScenario(d),
 		deleteFeature(d),
-	)
+		restoreRevision(d),
+		importBundle(d),
+	)...).WithAudit(d.Audit)
+}
+
+// readOnly marks tools that change nothing, so they are not audited.
+func readOnly(ts ...Tool) []Tool {
+	for i := range ts {
+		ts[i].ReadOnly = true
+	}
+	return ts
 }
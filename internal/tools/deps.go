@@ -2,6 +2,7 @@ package tools
 
 import (
 	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/internal/usecase"
 )
 
 // Deps is the bundle of dependencies the tool handlers need. It is constructed
@@ -13,4 +14,10 @@ type Deps struct {
 	AccountScenario repository.IAccountScenarioRepository
 	MockAPI         repository.IMockAPIRepository
 	Cache           repository.ICache
+	Revisions       usecase.IRevisionUC
+	Audit           repository.IAuditRepository
+	Clones          usecase.ICloneUC
+	Renames         usecase.IRenameUC
+	Trash           usecase.ITrashUC
+	Bundles         usecase.IBundleUC
 }
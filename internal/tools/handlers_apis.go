@@ -279,6 +279,7 @@ func createMockAPI(d Deps) Tool {
 			if err := d.MockAPI.Create(ctx, &req); err != nil {
 				return nil, fmt.Errorf("create mock api: %w", err)
 			}
+			recordRevision(ctx, d, domain.RevisionKindMockAPI, req.ID, domain.RevisionActionCreate)
 
 			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, a.Feature, a.Scenario))
 
@@ -417,6 +418,7 @@ func updateMockAPI(d Deps) Tool {
 			if err := d.MockAPI.UpdateByObjectID(ctx, id, update); err != nil {
 				return nil, fmt.Errorf("update mock api: %w", err)
 			}
+			recordRevision(ctx, d, domain.RevisionKindMockAPI, id, domain.RevisionActionUpdate)
 			updated, _ := d.MockAPI.FindByObjectID(ctx, id)
 			if updated != nil {
 				_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, updated.FeatureName, updated.ScenarioName))
@@ -436,7 +438,7 @@ func deleteMockAPI(d Deps) Tool {
 	}
 	return Tool{
 		Name:        "delete_mock_api",
-		Description: "Delete a mock API by id. Cache is invalidated. Irreversible.",
+		Description: "Delete a mock API by id. Cache is invalidated. It can be brought back with restore_revision.",
 		Destructive: true,
 		InputSchema: schema(`{
             "type": "object",
@@ -459,6 +461,7 @@ func deleteMockAPI(d Deps) Tool {
 			if err != nil || mockAPI == nil || mockAPI.Name == "" {
 				return nil, fmt.Errorf("mock api %s not found", a.APIID)
 			}
+			recordRevision(ctx, d, domain.RevisionKindMockAPI, id, domain.RevisionActionDelete)
 			if err := d.MockAPI.DeletByObjectID(ctx, id); err != nil {
 				return nil, fmt.Errorf("delete mock api: %w", err)
 			}
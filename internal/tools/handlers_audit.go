@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+func listAuditLog(d Deps) Tool {
+	type args struct {
+		Feature  string `json:"feature"`
+		Actor    string `json:"actor"`
+		Source   string `json:"source"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Page     int    `json:"page"`
+		PageSize int    `json:"page_size"`
+	}
+	return Tool{
+		Name:        "list_audit_log",
+		Description: "List who changed what, newest first: admin API calls and write tool calls from MCP, Slack and the web chat. Filter by feature, actor, source and an RFC3339 time range.",
+		InputSchema: schema(`{
+            "type": "object",
+            "properties": {
+                "feature":   {"type": "string"},
+                "actor":     {"type": "string"},
+                "source":    {"type": "string", "enum": ["http", "mcp", "slack", "chat"]},
+                "from":      {"type": "string", "description": "RFC3339, inclusive"},
+                "to":        {"type": "string", "description": "RFC3339, exclusive"},
+                "page":      {"type": "integer", "minimum": 1, "default": 1},
+                "page_size": {"type": "integer", "minimum": 1, "maximum": 100, "default": 50}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			filter := domain.AuditFilter{FeatureName: a.Feature, Actor: a.Actor, Source: a.Source}
+			var err error
+			if filter.From, err = parseTime(a.From); err != nil {
+				return nil, fmt.Errorf("invalid from: %w", err)
+			}
+			if filter.To, err = parseTime(a.To); err != nil {
+				return nil, fmt.Errorf("invalid to: %w", err)
+			}
+			params := normalizePagination(a.Page, a.PageSize)
+			entries, total, err := d.Audit.List(ctx, filter, params)
+			if err != nil {
+				return nil, fmt.Errorf("list audit log: %w", err)
+			}
+			return map[string]any{
+				"entries":   entries,
+				"total":     total,
+				"page":      params.Page,
+				"page_size": params.PageSize,
+			}, nil
+		},
+	}
+}
+
+// parseTime parses an optional RFC3339 time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+const bundleArgSchema = `{"description": "bundle from export_feature, as an object or a YAML/JSON string"}`
+
+func exportFeature(d Deps) Tool {
+	type args struct {
+		Feature   string   `json:"feature"`
+		Scenarios []string `json:"scenarios"`
+	}
+	return Tool{
+		Name:        "export_feature",
+		Description: "Export a feature, or only the named scenarios, with its mock APIs, sequences, gRPC mocks and permanent activations as a portable bundle for import_bundle.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["feature"],
+            "properties": {
+                "feature":   {"type": "string"},
+                "scenarios": {"type": "array", "items": {"type": "string"}, "description": "defaults to every scenario"}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			bundle, err := d.Bundles.Export(ctx, a.Feature, a.Scenarios)
+			if err != nil {
+				return nil, fmt.Errorf("export feature: %w", err)
+			}
+			return bundle, nil
+		},
+	}
+}
+
+func diffBundle(d Deps) Tool {
+	return Tool{
+		Name:        "diff_bundle",
+		Description: "Preview import_bundle: list the features, scenarios, mocks and activations it would create or update, without writing anything.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["bundle"],
+            "properties": {
+                "bundle": ` + bundleArgSchema + `
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			return importBundleArgs(ctx, d, raw, true)
+		},
+	}
+}
+
+func importBundle(d Deps) Tool {
+	return Tool{
+		Name:        "import_bundle",
+		Description: "Import a bundle: create or update its features, scenarios, mocks and activations by name. Re-importing the same bundle changes nothing; nothing missing from the bundle is deleted.",
+		Destructive: true,
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["bundle"],
+            "properties": {
+                "bundle": ` + bundleArgSchema + `
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			return importBundleArgs(ctx, d, raw, false)
+		},
+	}
+}
+
+func importBundleArgs(ctx context.Context, d Deps, raw json.RawMessage, dryRun bool) (any, error) {
+	var a struct {
+		Bundle json.RawMessage `json:"bundle"`
+	}
+	if err := decodeArgs(raw, &a); err != nil {
+		return nil, err
+	}
+	bundle, err := decodeBundleArg(a.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	result, err := d.Bundles.Import(ctx, bundle, dryRun, authorFrom(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("import bundle: %w", err)
+	}
+	return result, nil
+}
+
+// decodeBundleArg accepts the bundle as an object or as the text of a YAML or
+// JSON document.
+func decodeBundleArg(raw json.RawMessage) (*domain.Bundle, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		raw = json.RawMessage(text)
+	}
+	return usecase.DecodeBundle(raw)
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/namnv2496/mocktool/internal/usecase"
+)
+
+func cloneFeature(d Deps) Tool {
+	type args struct {
+		Feature    string `json:"feature"`
+		Name       string `json:"name"`
+		NamePrefix string `json:"name_prefix"`
+	}
+	return Tool{
+		Name:        "clone_feature",
+		Description: "Copy a feature with all its scenarios, mock APIs and gRPC mocks under a new name. Activations are not copied. 'name_prefix' is prepended to the copied scenario and mock names.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["feature", "name"],
+            "properties": {
+                "feature":     {"type": "string", "description": "feature to copy"},
+                "name":        {"type": "string", "description": "name of the new feature"},
+                "name_prefix": {"type": "string"}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			result, err := d.Clones.CloneFeature(ctx, a.Feature, a.Name, usecase.CloneOptions{NamePrefix: a.NamePrefix})
+			if err != nil {
+				return nil, fmt.Errorf("clone feature: %w", err)
+			}
+			return result, nil
+		},
+	}
+}
+
+func cloneScenario(d Deps) Tool {
+	type args struct {
+		Feature       string `json:"feature"`
+		Scenario      string `json:"scenario"`
+		TargetFeature string `json:"target_feature"`
+		Name          string `json:"name"`
+		NamePrefix    string `json:"name_prefix"`
+	}
+	return Tool{
+		Name:        "clone_scenario",
+		Description: "Copy a scenario with its mock APIs and gRPC mocks, within its feature or into 'target_feature'. The copy is named 'name', or the source name with 'name_prefix'; the prefix is also prepended to the mock names.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["feature", "scenario"],
+            "properties": {
+                "feature":        {"type": "string"},
+                "scenario":       {"type": "string", "description": "scenario to copy"},
+                "target_feature": {"type": "string", "description": "defaults to 'feature'"},
+                "name":           {"type": "string", "description": "name of the new scenario"},
+                "name_prefix":    {"type": "string"}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			result, err := d.Clones.CloneScenario(ctx, a.Feature, a.Scenario, a.TargetFeature, a.Name,
+				usecase.CloneOptions{NamePrefix: a.NamePrefix})
+			if err != nil {
+				return nil, fmt.Errorf("clone scenario: %w", err)
+			}
+			return result, nil
+		},
+	}
+}
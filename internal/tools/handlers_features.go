@@ -66,7 +66,7 @@ func deleteFeature(d Deps) Tool {
 	}
 	return Tool{
 		Name:        "delete_feature",
-		Description: "Delete a feature and ALL its scenarios + mock APIs. Irreversible. Cache is invalidated.",
+		Description: "Delete a feature and ALL its scenarios, mock APIs and activations. They stay in the trash for the retention period (restore_from_trash). Cache is invalidated.",
 		Destructive: true,
 		InputSchema: schema(`{
             "type": "object",
@@ -89,20 +89,16 @@ func deleteFeature(d Deps) Tool {
 				return nil, fmt.Errorf("feature %q not found", a.Feature)
 			}
 
-			if err := d.Feature.DeleteById(ctx, feature.ID); err != nil {
+			entry, err := d.Trash.DeleteFeature(ctx, feature.Name, authorFrom(ctx))
+			if err != nil {
 				return nil, fmt.Errorf("delete feature: %w", err)
 			}
-			if err := d.Scenario.DeleteByFeatureName(ctx, feature.Name); err != nil {
-				return nil, fmt.Errorf("delete scenarios: %w", err)
-			}
-			if err := d.MockAPI.DeleteByFeatureName(ctx, feature.Name); err != nil {
-				return nil, fmt.Errorf("delete mock apis: %w", err)
-			}
-			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, feature.Name))
-
 			return map[string]any{
-				"feature":    feature.Name,
-				"deleted_at": time.Now().UTC().Format(time.RFC3339),
+				"feature":    entry.FeatureName,
+				"trash_id":   entry.ID.Hex(),
+				"deleted":    entry.Counts,
+				"deleted_at": entry.DeletedAt.Format(time.RFC3339),
+				"expires_at": entry.ExpiresAt.Format(time.RFC3339),
 			}, nil
 		},
 	}
@@ -160,21 +156,23 @@ func createFeature(d Deps) Tool {
 	}
 }
 
-// updateFeature updates a feature's description and/or active status by name.
+// updateFeature updates a feature's name, description and/or active status by name.
 func updateFeature(d Deps) Tool {
 	type args struct {
 		Feature     string  `json:"feature"`
+		Name        *string `json:"name"`
 		Description *string `json:"description"`
 		Active      *bool   `json:"active"`
 	}
 	return Tool{
 		Name:        "update_feature",
-		Description: "Update a feature's description or active status. At least one of description or active must be provided.",
+		Description: "Update a feature's name, description or active status. At least one of name, description or active must be provided. A new name is applied to every scenario, mock and activation of the feature.",
 		InputSchema: schema(`{
             "type": "object",
             "required": ["feature"],
             "properties": {
                 "feature":     {"type": "string", "description": "feature name to update"},
+                "name":        {"type": "string", "description": "new feature name"},
                 "description": {"type": "string"},
                 "active":      {"type": "boolean"}
             }
@@ -187,13 +185,20 @@ func updateFeature(d Deps) Tool {
 			if a.Feature == "" {
 				return nil, fmt.Errorf("feature is required")
 			}
-			if a.Description == nil && a.Active == nil {
-				return nil, fmt.Errorf("at least one of description or active must be provided")
+			if a.Name == nil && a.Description == nil && a.Active == nil {
+				return nil, fmt.Errorf("at least one of name, description or active must be provided")
 			}
 			feature, _ := d.Feature.FindByName(ctx, a.Feature)
 			if feature == nil || feature.Name == "" {
 				return nil, fmt.Errorf("feature %q not found", a.Feature)
 			}
+			name := a.Feature
+			if a.Name != nil && *a.Name != a.Feature {
+				if _, err := d.Renames.RenameFeature(ctx, a.Feature, *a.Name); err != nil {
+					return nil, fmt.Errorf("rename feature: %w", err)
+				}
+				name = *a.Name
+			}
 			update := map[string]any{"updated_at": time.Now().UTC()}
 			if a.Description != nil {
 				update["description"] = *a.Description
@@ -204,8 +209,8 @@ func updateFeature(d Deps) Tool {
 			if err := d.Feature.UpdateByObjectID(ctx, feature.ID, update); err != nil {
 				return nil, fmt.Errorf("update feature: %w", err)
 			}
-			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, a.Feature))
-			return map[string]any{"feature": a.Feature, "updated": true}, nil
+			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, name))
+			return map[string]any{"feature": name, "updated": true}, nil
 		},
 	}
 }
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+// revisionAuthor is recorded as the author of the changes made by tools when
+// the context carries no Caller.
+const revisionAuthor = "mcp"
+
+func authorFrom(ctx context.Context) string {
+	if c, ok := ctx.Value(callerKey{}).(Caller); ok && c.Actor != "" {
+		return c.Actor
+	}
+	return revisionAuthor
+}
+
+// revisionTargetSchema is shared by the revision tools.
+const revisionTargetSchema = `
+                "kind": {"type": "string", "enum": ["mock_api", "grpc_mock_api", "scenario"]},
+                "id":   {"type": "string", "description": "ObjectID hex of the mock API, gRPC mock API or scenario"}`
+
+// recordRevision snapshots a document after it changed, or before it is
+// deleted. Like cache invalidation, a failure does not fail the tool.
+func recordRevision(ctx context.Context, d Deps, kind string, id primitive.ObjectID, action string) {
+	if d.Revisions != nil {
+		_ = d.Revisions.Record(ctx, kind, id, action, authorFrom(ctx))
+	}
+}
+
+// recordDeletedRevision snapshots doc once its delete succeeded.
+func recordDeletedRevision(ctx context.Context, d Deps, kind string, id primitive.ObjectID, doc any) {
+	if d.Revisions != nil {
+		_ = d.Revisions.RecordDeleted(ctx, kind, id, doc, authorFrom(ctx))
+	}
+}
+
+type revisionTarget struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+func (_self revisionTarget) objectID() (primitive.ObjectID, error) {
+	if _self.Kind == "" || _self.ID == "" {
+		return primitive.NilObjectID, fmt.Errorf("kind and id are required")
+	}
+	id, err := primitive.ObjectIDFromHex(_self.ID)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("invalid id: %w", err)
+	}
+	return id, nil
+}
+
+func listRevisions(d Deps) Tool {
+	type args struct {
+		revisionTarget
+		Page     int `json:"page"`
+		PageSize int `json:"page_size"`
+	}
+	return Tool{
+		Name:        "list_revisions",
+		Description: "List the revisions of a mock API, gRPC mock API or scenario, newest first, with who made each change and the fields it changed.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["kind", "id"],
+            "properties": {` + revisionTargetSchema + `,
+                "page":      {"type": "integer", "minimum": 1, "default": 1},
+                "page_size": {"type": "integer", "minimum": 1, "maximum": 100, "default": 10}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			id, err := a.objectID()
+			if err != nil {
+				return nil, err
+			}
+			params := domain.PaginationParams{Page: a.Page, PageSize: a.PageSize}
+			params.Normalize()
+			revisions, total, err := d.Revisions.List(ctx, a.Kind, id, params)
+			if err != nil {
+				return nil, fmt.Errorf("list revisions: %w", err)
+			}
+			out := make([]map[string]any, 0, len(revisions))
+			for _, r := range revisions {
+				fields := make([]string, 0, len(r.Changes))
+				for _, c := range r.Changes {
+					fields = append(fields, c.Field)
+				}
+				out = append(out, map[string]any{
+					"version":       r.Version,
+					"action":        r.Action,
+					"author":        r.Author,
+					"restored_from": r.RestoredFrom,
+					"fields":        fields,
+					"created_at":    r.CreatedAt,
+				})
+			}
+			return map[string]any{"revisions": out, "total": total, "page": params.Page}, nil
+		},
+	}
+}
+
+func diffRevisions(d Deps) Tool {
+	type args struct {
+		revisionTarget
+		From int `json:"from"`
+		To   int `json:"to"`
+	}
+	return Tool{
+		Name:        "diff_revisions",
+		Description: "Show the fields changed between two revisions. 'to' defaults to the latest revision and 'from' to the one before 'to'.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["kind", "id"],
+            "properties": {` + revisionTargetSchema + `,
+                "from": {"type": "integer", "minimum": 1},
+                "to":   {"type": "integer", "minimum": 1}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			id, err := a.objectID()
+			if err != nil {
+				return nil, err
+			}
+			diff, err := d.Revisions.Diff(ctx, a.Kind, id, a.From, a.To)
+			if err != nil {
+				return nil, fmt.Errorf("diff revisions: %w", err)
+			}
+			return diff, nil
+		},
+	}
+}
+
+func restoreRevision(d Deps) Tool {
+	type args struct {
+		revisionTarget
+		Version int `json:"version"`
+	}
+	return Tool{
+		Name:        "restore_revision",
+		Description: "Restore a mock API, gRPC mock API or scenario to an earlier revision, recreating it if it was deleted. The restore is recorded as a new revision.",
+		Destructive: true,
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["kind", "id", "version"],
+            "properties": {` + revisionTargetSchema + `,
+                "version": {"type": "integer", "minimum": 1}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			id, err := a.objectID()
+			if err != nil {
+				return nil, err
+			}
+			if a.Version < 1 {
+				return nil, fmt.Errorf("version is required")
+			}
+			rev, err := d.Revisions.Restore(ctx, a.Kind, id, a.Version, authorFrom(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("restore revision: %w", err)
+			}
+			return map[string]any{
+				"kind":          a.Kind,
+				"id":            a.ID,
+				"version":       rev.Version,
+				"restored_from": rev.RestoredFrom,
+				"changes":       rev.Changes,
+			}, nil
+		},
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/namnv2496/mocktool/internal/domain"
 	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/internal/usecase"
 )
 
 func listScenarios(d Deps) Tool {
@@ -147,23 +149,27 @@ func searchScenarios(d Deps) Tool {
 	}
 }
 
-// updateScenario updates a scenario's description by feature + scenario name.
+// updateScenario updates a scenario's name, description and parent by feature + scenario name.
 func updateScenario(d Deps) Tool {
 	type args struct {
-		Feature     string `json:"feature"`
-		Scenario    string `json:"scenario"`
-		Description string `json:"description"`
+		Feature     string  `json:"feature"`
+		Scenario    string  `json:"scenario"`
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Parent      *string `json:"parent"`
 	}
 	return Tool{
 		Name:        "update_scenario",
-		Description: "Update a scenario's description.",
+		Description: "Update a scenario's name, description and/or parent. A new name is applied to every mock of the scenario. The scenario inherits every mock of its parent it does not define itself; an empty parent removes it.",
 		InputSchema: schema(`{
             "type": "object",
-            "required": ["feature", "scenario", "description"],
+            "required": ["feature", "scenario"],
             "properties": {
                 "feature":     {"type": "string"},
                 "scenario":    {"type": "string"},
-                "description": {"type": "string"}
+                "name":        {"type": "string", "description": "new scenario name"},
+                "description": {"type": "string"},
+                "parent":      {"type": "string", "description": "name of the parent scenario in the same feature, empty to remove it"}
             }
         }`),
 		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
@@ -174,39 +180,73 @@ func updateScenario(d Deps) Tool {
 			if a.Feature == "" || a.Scenario == "" {
 				return nil, fmt.Errorf("feature and scenario are required")
 			}
+			if a.Name == nil && a.Description == nil && a.Parent == nil {
+				return nil, fmt.Errorf("name, description or parent is required")
+			}
 			scenario, err := d.Scenario.FindByFeatureNameAndName(ctx, a.Feature, a.Scenario)
 			if err != nil || scenario == nil || scenario.Name == "" {
 				return nil, fmt.Errorf("scenario %q not found in feature %q", a.Scenario, a.Feature)
 			}
-			if err := d.Scenario.UpdateByObjectID(ctx, scenario.ID, map[string]any{
-				"description": a.Description,
-				"updated_at":  time.Now().UTC(),
-			}); err != nil {
+			name := a.Scenario
+			if a.Name != nil && *a.Name != a.Scenario {
+				if _, err := d.Renames.RenameScenario(ctx, a.Feature, a.Scenario, *a.Name); err != nil {
+					return nil, fmt.Errorf("rename scenario: %w", err)
+				}
+				name = *a.Name
+			}
+			update := map[string]any{"updated_at": time.Now().UTC()}
+			if a.Description != nil {
+				update["description"] = *a.Description
+			}
+			if a.Parent != nil {
+				update["parent_id"] = nil
+				if *a.Parent != "" {
+					parent, err := findParentScenario(ctx, d, scenario, *a.Parent)
+					if err != nil {
+						return nil, err
+					}
+					update["parent_id"] = parent.ID
+				}
+			}
+			if err := d.Scenario.UpdateByObjectID(ctx, scenario.ID, update); err != nil {
 				return nil, fmt.Errorf("update scenario: %w", err)
 			}
-			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, a.Feature, a.Scenario))
-			return map[string]any{"feature": a.Feature, "scenario": a.Scenario, "updated": true}, nil
+			recordRevision(ctx, d, domain.RevisionKindScenario, scenario.ID, domain.RevisionActionUpdate)
+			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, a.Feature, name))
+			return map[string]any{"feature": a.Feature, "scenario": name, "updated": true}, nil
 		},
 	}
 }
 
+// findParentScenario resolves the parent scenario named name for scenario
+// and checks the chain it would create.
+func findParentScenario(ctx context.Context, d Deps, scenario *domain.Scenario, name string) (*domain.Scenario, error) {
+	parent, err := d.Scenario.FindByFeatureNameAndName(ctx, scenario.FeatureName, name)
+	if err != nil || parent == nil || parent.Name == "" {
+		return nil, fmt.Errorf("parent scenario %q not found in feature %q", name, scenario.FeatureName)
+	}
+	return usecase.ValidateScenarioParent(ctx, d.Scenario, scenario, parent.ID)
+}
+
 // createScenario creates a new scenario under a feature.
 func createScenario(d Deps) Tool {
 	type args struct {
 		Feature     string `json:"feature"`
 		Name        string `json:"name"`
 		Description string `json:"description"`
+		Parent      string `json:"parent"`
 	}
 	return Tool{
 		Name:        "create_scenario",
-		Description: "Create a new scenario under an existing feature. Scenario name must be unique within the feature.",
+		Description: "Create a new scenario under an existing feature. Scenario name must be unique within the feature. With a parent, the scenario inherits every mock of the parent it does not define itself.",
 		InputSchema: schema(`{
             "type": "object",
             "required": ["feature", "name"],
             "properties": {
                 "feature":     {"type": "string"},
                 "name":        {"type": "string", "description": "unique scenario name within the feature"},
-                "description": {"type": "string"}
+                "description": {"type": "string"},
+                "parent":      {"type": "string", "description": "name of the parent scenario in the same feature"}
             }
         }`),
 		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
@@ -229,13 +269,22 @@ func createScenario(d Deps) Tool {
 				CreatedAt:   now,
 				UpdatedAt:   now,
 			}
+			if a.Parent != "" {
+				parent, err := findParentScenario(ctx, d, s, a.Parent)
+				if err != nil {
+					return nil, err
+				}
+				s.ParentID = &parent.ID
+			}
 			if err := d.Scenario.Create(ctx, s); err != nil {
 				return nil, fmt.Errorf("create scenario: %w", err)
 			}
+			recordRevision(ctx, d, domain.RevisionKindScenario, s.ID, domain.RevisionActionCreate)
 			return map[string]any{
 				"feature":     a.Feature,
 				"name":        a.Name,
 				"description": a.Description,
+				"parent":      a.Parent,
 				"created_at":  now.Format(time.RFC3339),
 			}, nil
 		},
@@ -296,18 +345,39 @@ func setScenarioInactive(d Deps) Tool {
 
 func activateScenario(d Deps) Tool {
 	type args struct {
-		Feature  string `json:"feature"`
-		Scenario string `json:"scenario"`
+		Feature        string     `json:"feature"`
+		Scenario       string     `json:"scenario"`
+		AccountID      string     `json:"account_id"`
+		Target         string     `json:"target"`
+		GroupName      string     `json:"group_name"`
+		AccountPattern string     `json:"account_pattern"`
+		AccountPrefix  string     `json:"account_prefix"`
+		Percentage     int        `json:"percentage"`
+		Duration       string     `json:"duration"`
+		StartsAt       *time.Time `json:"starts_at"`
+		ExpiresAt      *time.Time `json:"expires_at"`
 	}
 	return Tool{
-		Name:        "activate_scenario",
-		Description: "Activate a scenario globally for a feature. Replaces any existing global activation and clears all account-specific mappings.",
+		Name: "activate_scenario",
+		Description: "Activate a scenario for a feature, globally, for one account, or for a cohort (target group, pattern or percentage). " +
+			"Precedence for an account is exact account > group > pattern > percentage > global. " +
+			"Without a time window it replaces the existing activation of the same account or cohort (a global one also clears all account-specific and cohort mappings). " +
+			"With duration (e.g. \"30m\") or starts_at/expires_at it only applies inside that window, then the previous activation takes over again.",
 		InputSchema: schema(`{
             "type": "object",
             "required": ["feature", "scenario"],
             "properties": {
-                "feature":  {"type": "string"},
-                "scenario": {"type": "string"}
+                "feature":    {"type": "string"},
+                "scenario":   {"type": "string"},
+                "account_id": {"type": "string", "description": "activate for this account only; global when empty"},
+                "target":     {"type": "string", "enum": ["group", "pattern", "percentage"], "description": "activate for a cohort instead of account_id"},
+                "group_name": {"type": "string", "description": "account group, for target group"},
+                "account_pattern": {"type": "string", "description": "regex on the account ID, for target pattern"},
+                "account_prefix":  {"type": "string", "description": "account ID prefix, for target pattern"},
+                "percentage": {"type": "integer", "minimum": 1, "maximum": 100, "description": "share of accounts (stable hash of feature and account), for target percentage"},
+                "duration":   {"type": "string", "description": "Go duration such as 30m or 2h"},
+                "starts_at":  {"type": "string", "format": "date-time"},
+                "expires_at": {"type": "string", "format": "date-time"}
             }
         }`),
 		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
@@ -323,30 +393,76 @@ func activateScenario(d Deps) Tool {
 				return nil, fmt.Errorf("scenario %q not found in feature %q", a.Scenario, a.Feature)
 			}
 
-			// Global activation: clear all account-specific mappings, then the
-			// global mapping, then create a fresh global mapping.
-			if err := d.AccountScenario.DeactivateAllAccountSpecificMappings(ctx, a.Feature); err != nil {
-				return nil, fmt.Errorf("clear account-specific mappings: %w", err)
+			now := time.Now().UTC()
+			var accountID *string
+			if a.AccountID != "" {
+				accountID = &a.AccountID
+			}
+			mapping := &domain.AccountScenario{
+				FeatureName:    a.Feature,
+				ScenarioID:     scenario.ID,
+				AccountId:      accountID,
+				Target:         a.Target,
+				GroupName:      strings.TrimSpace(a.GroupName),
+				AccountPattern: a.AccountPattern,
+				AccountPrefix:  a.AccountPrefix,
+				Percentage:     a.Percentage,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := mapping.ValidateTarget(); err != nil {
+				return nil, err
 			}
-			if err := d.AccountScenario.DeactivateByFeatureAndAccount(ctx, a.Feature, nil); err != nil {
-				return nil, fmt.Errorf("clear global mapping: %w", err)
+			var duration time.Duration
+			if a.Duration != "" {
+				if duration, err = time.ParseDuration(a.Duration); err != nil {
+					return nil, fmt.Errorf("invalid duration: %w", err)
+				}
 			}
-			now := time.Now().UTC()
-			if err := d.AccountScenario.Create(ctx, &domain.AccountScenario{
-				FeatureName: a.Feature,
-				ScenarioID:  scenario.ID,
-				CreatedAt:   now,
-				UpdatedAt:   now,
-			}); err != nil {
+			if err := mapping.SetWindow(now, a.StartsAt, a.ExpiresAt, duration); err != nil {
+				return nil, err
+			}
+
+			// Permanent activation: clear the existing mappings first (all
+			// account-specific and cohort ones too when global). A time-boxed
+			// one is layered on top of them.
+			if !mapping.IsTimeBoxed() && mapping.Target != "" {
+				if err := d.AccountScenario.DeactivateByTarget(ctx, mapping); err != nil {
+					return nil, fmt.Errorf("clear existing mapping: %w", err)
+				}
+			} else if !mapping.IsTimeBoxed() {
+				if accountID == nil {
+					if err := d.AccountScenario.DeactivateAllAccountSpecificMappings(ctx, a.Feature); err != nil {
+						return nil, fmt.Errorf("clear account-specific mappings: %w", err)
+					}
+				}
+				if err := d.AccountScenario.DeactivateByFeatureAndAccount(ctx, a.Feature, accountID); err != nil {
+					return nil, fmt.Errorf("clear existing mapping: %w", err)
+				}
+			}
+			if err := d.AccountScenario.Create(ctx, mapping); err != nil {
 				return nil, fmt.Errorf("activate: %w", err)
 			}
 			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, a.Feature))
-			return map[string]any{
-				"feature":     a.Feature,
-				"scenario":    a.Scenario,
-				"activated":   true,
+			res := map[string]any{
+				"feature":      a.Feature,
+				"scenario":     a.Scenario,
+				"activated":    true,
 				"activated_at": now.Format(time.RFC3339),
-			}, nil
+			}
+			if a.AccountID != "" {
+				res["account_id"] = a.AccountID
+			}
+			if mapping.Target != "" {
+				res["target"] = mapping.Target
+			}
+			if mapping.StartsAt != nil {
+				res["starts_at"] = mapping.StartsAt.Format(time.RFC3339)
+			}
+			if mapping.ExpiresAt != nil {
+				res["expires_at"] = mapping.ExpiresAt.Format(time.RFC3339)
+			}
+			return res, nil
 		},
 	}
 }
@@ -392,7 +508,7 @@ func deleteScenario(d Deps) Tool {
 	}
 	return Tool{
 		Name:        "delete_scenario",
-		Description: "Delete a scenario and all its mock APIs. Cache is invalidated. Irreversible.",
+		Description: "Delete a scenario with its mock APIs and activations. They stay in the trash for the retention period (restore_from_trash). Cache is invalidated.",
 		Destructive: true,
 		InputSchema: schema(`{
             "type": "object",
@@ -414,20 +530,18 @@ func deleteScenario(d Deps) Tool {
 			if err != nil || scenario == nil || scenario.Name == "" {
 				return nil, fmt.Errorf("scenario %q not found in feature %q", a.Scenario, a.Feature)
 			}
-			if err := d.Scenario.DeleteByObjectID(ctx, scenario.ID); err != nil {
+			entry, err := d.Trash.DeleteScenario(ctx, a.Feature, a.Scenario, authorFrom(ctx))
+			if err != nil {
 				return nil, fmt.Errorf("delete scenario: %w", err)
 			}
-			if err := d.MockAPI.DeleteByScenarioName(ctx, scenario.Name); err != nil {
-				return nil, fmt.Errorf("delete mock apis: %w", err)
-			}
-			if err := d.AccountScenario.DeleteByScenarioId(ctx, scenario.ID); err != nil {
-				return nil, fmt.Errorf("delete account-scenario mapping: %w", err)
-			}
-			_ = d.Cache.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, scenario.FeatureName, scenario.Name))
+			recordDeletedRevision(ctx, d, domain.RevisionKindScenario, scenario.ID, scenario)
 			return map[string]any{
 				"feature":    a.Feature,
 				"scenario":   a.Scenario,
-				"deleted_at": time.Now().UTC().Format(time.RFC3339),
+				"trash_id":   entry.ID.Hex(),
+				"deleted":    entry.Counts,
+				"deleted_at": entry.DeletedAt.Format(time.RFC3339),
+				"expires_at": entry.ExpiresAt.Format(time.RFC3339),
 			}, nil
 		},
 	}
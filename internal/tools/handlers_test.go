@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,7 +13,9 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/usecase"
 	repomock "github.com/namnv2496/mocktool/mocks/repository"
+	usecasemock "github.com/namnv2496/mocktool/mocks/usecase"
 )
 
 type mockDeps struct {
@@ -21,6 +24,7 @@ type mockDeps struct {
 	account  *repomock.MockIAccountScenarioRepository
 	api      *repomock.MockIMockAPIRepository
 	cache    *repomock.MockICache
+	trash    *usecasemock.MockITrashUC
 }
 
 func newDeps(t *testing.T) (Deps, mockDeps) {
@@ -31,6 +35,7 @@ func newDeps(t *testing.T) (Deps, mockDeps) {
 		account:  repomock.NewMockIAccountScenarioRepository(ctrl),
 		api:      repomock.NewMockIMockAPIRepository(ctrl),
 		cache:    repomock.NewMockICache(ctrl),
+		trash:    usecasemock.NewMockITrashUC(ctrl),
 	}
 	return Deps{
 		Feature:         m.feature,
@@ -38,6 +43,7 @@ func newDeps(t *testing.T) (Deps, mockDeps) {
 		AccountScenario: m.account,
 		MockAPI:         m.api,
 		Cache:           m.cache,
+		Trash:           m.trash,
 	}, m
 }
 
@@ -47,11 +53,14 @@ func TestBuildAll_ExposesExpectedTools(t *testing.T) {
 
 	got := r.Names()
 	want := []string{
-		"activate_scenario", "create_feature", "create_mock_api",
+		"activate_scenario", "clone_feature", "clone_scenario", "create_feature", "create_mock_api",
 		"create_scenario", "deactivate_scenario", "delete_feature",
 		"delete_mock_api", "delete_scenario", "disable_feature",
 		"enable_feature", "get_active_scenario", "get_mock_api_curl",
 		"list_apis", "list_features", "list_scenarios",
+		"list_revisions", "diff_revisions", "restore_revision",
+		"list_audit_log", "list_trash", "restore_from_trash",
+		"export_feature", "diff_bundle", "import_bundle",
 		"reset_mock_api_counter", "search_mocks", "search_scenarios",
 		"set_scenario_inactive", "update_feature", "update_mock_api",
 		"update_scenario",
@@ -70,6 +79,8 @@ func TestBuildAll_DestructiveFlagsCorrectlySet(t *testing.T) {
 		"delete_mock_api":      true,
 		"disable_feature":      true,
 		"set_scenario_inactive": true,
+		"restore_revision":      true,
+		"import_bundle":         true,
 	}
 	for _, tool := range r.List() {
 		assert.Equal(t, destructive[tool.Name], tool.Destructive, "tool=%s", tool.Name)
@@ -101,18 +112,24 @@ func TestListFeatures_WithQueryUsesSearch(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestDeleteFeature_CascadesAndInvalidatesCache(t *testing.T) {
+func TestDeleteFeature_MovesToTrash(t *testing.T) {
 	d, m := newDeps(t)
 	featureID := primitive.NewObjectID()
 	m.feature.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{ID: featureID, Name: "insertAd"}, nil)
-	m.feature.EXPECT().DeleteById(gomock.Any(), featureID).Return(nil)
-	m.scenario.EXPECT().DeleteByFeatureName(gomock.Any(), "insertAd").Return(nil)
-	m.api.EXPECT().DeleteByFeatureName(gomock.Any(), "insertAd").Return(nil)
-	m.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil)
+	entry := &domain.TrashEntry{
+		ID:          primitive.NewObjectID(),
+		Kind:        domain.TrashKindFeature,
+		FeatureName: "insertAd",
+		Counts:      map[string]int{"features": 1, "scenarios": 2, "mock_apis": 5},
+	}
+	m.trash.EXPECT().DeleteFeature(gomock.Any(), "insertAd", "mcp").Return(entry, nil)
 
 	res, err := BuildAll(d).Invoke(context.Background(), "delete_feature", json.RawMessage(`{"feature":"insertAd"}`))
 	require.NoError(t, err)
-	assert.Equal(t, "insertAd", res.(map[string]any)["feature"])
+	got := res.(map[string]any)
+	assert.Equal(t, "insertAd", got["feature"])
+	assert.Equal(t, entry.ID.Hex(), got["trash_id"])
+	assert.Equal(t, entry.Counts, got["deleted"])
 }
 
 func TestDeleteFeature_NotFoundReturnsError(t *testing.T) {
@@ -148,6 +165,224 @@ func TestActivateScenario_ClearsAllMappingsAndCreatesGlobal(t *testing.T) {
 	assert.Equal(t, true, res.(map[string]any)["activated"])
 }
 
+func TestActivateScenario_TimeBoxedKeepsExistingMappings(t *testing.T) {
+	d, m := newDeps(t)
+	scenarioID := primitive.NewObjectID()
+
+	m.scenario.EXPECT().
+		FindByFeatureNameAndName(gomock.Any(), "insertAd", "error_500").
+		Return(&domain.Scenario{ID: scenarioID, FeatureName: "insertAd", Name: "error_500"}, nil).
+		Times(2)
+	m.account.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, as *domain.AccountScenario) error {
+			require.NotNil(t, as.AccountId)
+			assert.Equal(t, "qa-1", *as.AccountId)
+			require.NotNil(t, as.ExpiresAt)
+			assert.WithinDuration(t, time.Now().Add(30*time.Minute), *as.ExpiresAt, 5*time.Second)
+			return nil
+		},
+	)
+	m.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil)
+
+	res, err := BuildAll(d).Invoke(context.Background(), "activate_scenario",
+		json.RawMessage(`{"feature":"insertAd","scenario":"error_500","account_id":"qa-1","duration":"30m"}`))
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.(map[string]any)["expires_at"])
+
+	_, err = BuildAll(d).Invoke(context.Background(), "activate_scenario",
+		json.RawMessage(`{"feature":"insertAd","scenario":"error_500","duration":"soon"}`))
+	assert.Error(t, err)
+}
+
+func TestActivateScenario_PatternReplacesSameCohort(t *testing.T) {
+	d, m := newDeps(t)
+	scenarioID := primitive.NewObjectID()
+
+	m.scenario.EXPECT().
+		FindByFeatureNameAndName(gomock.Any(), "insertAd", "beta").
+		Return(&domain.Scenario{ID: scenarioID, FeatureName: "insertAd", Name: "beta"}, nil).
+		Times(2)
+	m.account.EXPECT().DeactivateByTarget(gomock.Any(), gomock.Any()).Return(nil)
+	m.account.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, as *domain.AccountScenario) error {
+			assert.Equal(t, domain.ActivationTargetPattern, as.Target)
+			assert.Equal(t, "beta-", as.AccountPrefix)
+			assert.Nil(t, as.AccountId)
+			return nil
+		},
+	)
+	m.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil)
+
+	res, err := BuildAll(d).Invoke(context.Background(), "activate_scenario",
+		json.RawMessage(`{"feature":"insertAd","scenario":"beta","target":"pattern","account_prefix":"beta-"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "pattern", res.(map[string]any)["target"])
+
+	_, err = BuildAll(d).Invoke(context.Background(), "activate_scenario",
+		json.RawMessage(`{"feature":"insertAd","scenario":"beta","target":"percentage","percentage":0}`))
+	assert.Error(t, err)
+}
+
+func TestCreateScenario_WithParent(t *testing.T) {
+	d, m := newDeps(t)
+	parentID := primitive.NewObjectID()
+	parent := &domain.Scenario{ID: parentID, FeatureName: "insertAd", Name: "base"}
+
+	m.feature.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{Name: "insertAd"}, nil)
+	m.scenario.EXPECT().FindByFeatureNameAndName(gomock.Any(), "insertAd", "base").Return(parent, nil)
+	m.scenario.EXPECT().GetByObjectID(gomock.Any(), parentID).Return(parent, nil)
+	m.scenario.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, s *domain.Scenario) error {
+			require.NotNil(t, s.ParentID)
+			assert.Equal(t, parentID, *s.ParentID)
+			return nil
+		},
+	)
+
+	res, err := BuildAll(d).Invoke(context.Background(), "create_scenario",
+		json.RawMessage(`{"feature":"insertAd","name":"child","parent":"base"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "base", res.(map[string]any)["parent"])
+}
+
+func TestRevisions_RecordAndRestore(t *testing.T) {
+	d, m := newDeps(t)
+	revisions := usecasemock.NewMockIRevisionUC(gomock.NewController(t))
+	d.Revisions = revisions
+	apiID := primitive.NewObjectID()
+
+	m.api.EXPECT().FindByObjectID(gomock.Any(), apiID).
+		Return(&domain.MockAPI{ID: apiID, FeatureName: "insertAd", ScenarioName: "s1", Name: "createUser"}, nil)
+	gomock.InOrder(
+		revisions.EXPECT().Record(gomock.Any(), domain.RevisionKindMockAPI, apiID, domain.RevisionActionDelete, "mcp").Return(nil),
+		m.api.EXPECT().DeletByObjectID(gomock.Any(), apiID).Return(nil),
+	)
+	m.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := BuildAll(d).Invoke(context.Background(), "delete_mock_api",
+		json.RawMessage(`{"api_id":"`+apiID.Hex()+`"}`))
+	require.NoError(t, err)
+
+	revisions.EXPECT().Restore(gomock.Any(), domain.RevisionKindMockAPI, apiID, 2, "mcp").
+		Return(&domain.Revision{Version: 4, RestoredFrom: 2}, nil)
+
+	res, err := BuildAll(d).Invoke(context.Background(), "restore_revision",
+		json.RawMessage(`{"kind":"mock_api","id":"`+apiID.Hex()+`","version":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, 4, res.(map[string]any)["version"])
+
+	_, err = BuildAll(d).Invoke(context.Background(), "restore_revision",
+		json.RawMessage(`{"kind":"mock_api","id":"`+apiID.Hex()+`"}`))
+	assert.Error(t, err)
+}
+
+func TestAudit_RecordsWriteToolsWithCaller(t *testing.T) {
+	d, m := newDeps(t)
+	audit := repomock.NewMockIAuditRepository(gomock.NewController(t))
+	d.Audit = audit
+	r := BuildAll(d)
+	ctx := WithCaller(context.Background(), Caller{Actor: "U123", Source: domain.AuditSourceSlack})
+
+	// Reads are not recorded.
+	m.feature.EXPECT().ListAllPaginated(gomock.Any(), gomock.Any()).Return(nil, int64(0), nil)
+	_, err := r.Invoke(ctx, "list_features", json.RawMessage(`{}`))
+	require.NoError(t, err)
+
+	m.feature.EXPECT().FindByName(gomock.Any(), "insertAd").
+		Return(&domain.Feature{ID: primitive.NewObjectID(), Name: "insertAd"}, nil)
+	m.feature.EXPECT().UpdateByObjectID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	m.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil)
+	audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, "U123", e.Actor)
+			assert.Equal(t, domain.AuditSourceSlack, e.Source)
+			assert.Equal(t, "enable_feature", e.Operation)
+			assert.Equal(t, "insertAd", e.FeatureName)
+			assert.Equal(t, true, e.After["is_active"])
+			assert.Empty(t, e.Error)
+			return nil
+		},
+	)
+	_, err = r.Invoke(ctx, "enable_feature", json.RawMessage(`{"feature":"insertAd"}`))
+	require.NoError(t, err)
+
+	// Failed calls are recorded with their error.
+	m.feature.EXPECT().FindByName(gomock.Any(), "missing").Return(nil, nil)
+	audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, "missing", e.FeatureName)
+			assert.Contains(t, e.Error, "not found")
+			return nil
+		},
+	)
+	_, err = r.Invoke(ctx, "enable_feature", json.RawMessage(`{"feature":"missing"}`))
+	assert.Error(t, err)
+}
+
+func TestCloneScenario_DelegatesToUseCase(t *testing.T) {
+	d, _ := newDeps(t)
+	clones := usecasemock.NewMockICloneUC(gomock.NewController(t))
+	d.Clones = clones
+
+	clones.EXPECT().
+		CloneScenario(gomock.Any(), "insertAd", "s1", "search", "", usecase.CloneOptions{NamePrefix: "v2-"}).
+		Return(&usecase.CloneResult{Feature: "search", Scenarios: []string{"v2-s1"}, MockAPIs: 3}, nil)
+	res, err := BuildAll(d).Invoke(context.Background(), "clone_scenario",
+		json.RawMessage(`{"feature":"insertAd","scenario":"s1","target_feature":"search","name_prefix":"v2-"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.(*usecase.CloneResult).MockAPIs)
+
+	clones.EXPECT().CloneFeature(gomock.Any(), "insertAd", "insertAd", gomock.Any()).
+		Return(nil, usecase.ErrCloneConflict)
+	_, err = BuildAll(d).Invoke(context.Background(), "clone_feature",
+		json.RawMessage(`{"feature":"insertAd","name":"insertAd"}`))
+	assert.ErrorIs(t, err, usecase.ErrCloneConflict)
+}
+
+func TestImportBundle_AcceptsObjectOrDocument(t *testing.T) {
+	d, _ := newDeps(t)
+	bundles := usecasemock.NewMockIBundleUC(gomock.NewController(t))
+	d.Bundles = bundles
+
+	isBundle := gomock.Cond(func(b *domain.Bundle) bool {
+		return len(b.Features) == 1 && b.Features[0].Name == "insertAd"
+	})
+	bundles.EXPECT().Import(gomock.Any(), isBundle, true, "mcp").
+		Return(&usecase.ImportResult{DryRun: true, Created: 1}, nil)
+	res, err := BuildAll(d).Invoke(context.Background(), "diff_bundle",
+		json.RawMessage(`{"bundle":{"version":1,"features":[{"name":"insertAd"}]}}`))
+	require.NoError(t, err)
+	assert.True(t, res.(*usecase.ImportResult).DryRun)
+
+	bundles.EXPECT().Import(gomock.Any(), isBundle, false, "mcp").
+		Return(&usecase.ImportResult{Created: 1}, nil)
+	_, err = BuildAll(d).Invoke(context.Background(), "import_bundle",
+		json.RawMessage(`{"bundle":"version: 1\nfeatures:\n  - name: insertAd\n"}`))
+	require.NoError(t, err)
+
+	_, err = BuildAll(d).Invoke(context.Background(), "import_bundle",
+		json.RawMessage(`{"bundle":{"features":[{"name":"insertAd","color":"red"}]}}`))
+	assert.ErrorIs(t, err, usecase.ErrInvalidBundle)
+}
+
+func TestUpdateFeature_RenameDelegatesToUseCase(t *testing.T) {
+	d, m := newDeps(t)
+	renames := usecasemock.NewMockIRenameUC(gomock.NewController(t))
+	d.Renames = renames
+	featureID := primitive.NewObjectID()
+
+	m.feature.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{ID: featureID, Name: "insertAd"}, nil)
+	renames.EXPECT().RenameFeature(gomock.Any(), "insertAd", "postAd").
+		Return(&usecase.RenameResult{Feature: "postAd", OldName: "insertAd", NewName: "postAd"}, nil)
+	m.feature.EXPECT().UpdateByObjectID(gomock.Any(), featureID, gomock.Any()).Return(nil)
+	m.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:postAd:*").Return(nil)
+
+	res, err := BuildAll(d).Invoke(context.Background(), "update_feature",
+		json.RawMessage(`{"feature":"insertAd","name":"postAd"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "postAd", res.(map[string]any)["feature"])
+}
+
 func TestCreateMockAPI_PersistsAndInvalidatesCache(t *testing.T) {
 	d, m := newDeps(t)
 
@@ -212,16 +447,14 @@ func TestDeleteMockAPI_NotFoundReturnsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "not found")
 }
 
-func TestDeleteScenario_CascadesMockAPIsAndAccountScenario(t *testing.T) {
+func TestDeleteScenario_MovesToTrash(t *testing.T) {
 	d, m := newDeps(t)
 	scenarioID := primitive.NewObjectID()
 
 	m.scenario.EXPECT().FindByFeatureNameAndName(gomock.Any(), "insertAd", "s1").Return(
 		&domain.Scenario{ID: scenarioID, FeatureName: "insertAd", Name: "s1"}, nil)
-	m.scenario.EXPECT().DeleteByObjectID(gomock.Any(), scenarioID).Return(nil)
-	m.api.EXPECT().DeleteByScenarioName(gomock.Any(), "s1").Return(nil)
-	m.account.EXPECT().DeleteByScenarioId(gomock.Any(), scenarioID).Return(nil)
-	m.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil)
+	m.trash.EXPECT().DeleteScenario(gomock.Any(), "insertAd", "s1", "mcp").
+		Return(&domain.TrashEntry{ID: primitive.NewObjectID(), Kind: domain.TrashKindScenario}, nil)
 
 	_, err := BuildAll(d).Invoke(context.Background(), "delete_scenario", json.RawMessage(`{"feature":"insertAd","scenario":"s1"}`))
 	require.NoError(t, err)
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func listTrash(d Deps) Tool {
+	type args struct {
+		Feature  string `json:"feature"`
+		Page     int    `json:"page"`
+		PageSize int    `json:"page_size"`
+	}
+	return Tool{
+		Name:        "list_trash",
+		Description: "List the deleted features and scenarios that can still be restored, newest first, with what was deleted with them and when they are purged.",
+		InputSchema: schema(`{
+            "type": "object",
+            "properties": {
+                "feature":   {"type": "string"},
+                "page":      {"type": "integer", "minimum": 1, "default": 1},
+                "page_size": {"type": "integer", "minimum": 1, "maximum": 100, "default": 50}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			params := normalizePagination(a.Page, a.PageSize)
+			entries, total, err := d.Trash.List(ctx, a.Feature, params)
+			if err != nil {
+				return nil, fmt.Errorf("list trash: %w", err)
+			}
+			return map[string]any{
+				"entries":   entries,
+				"total":     total,
+				"page":      params.Page,
+				"page_size": params.PageSize,
+			}, nil
+		},
+	}
+}
+
+func restoreFromTrash(d Deps) Tool {
+	type args struct {
+		ID string `json:"id"`
+	}
+	return Tool{
+		Name:        "restore_from_trash",
+		Description: "Undo the delete of a feature or scenario: restore it with its mocks and activations as they were deleted. Fails when the name was reused since.",
+		InputSchema: schema(`{
+            "type": "object",
+            "required": ["id"],
+            "properties": {
+                "id": {"type": "string", "description": "trash entry ID from list_trash"}
+            }
+        }`),
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var a args
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			id, err := primitive.ObjectIDFromHex(a.ID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id: %w", err)
+			}
+			entry, err := d.Trash.Restore(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("restore: %w", err)
+			}
+			return map[string]any{
+				"kind":                entry.Kind,
+				"feature":             entry.FeatureName,
+				"scenario":            entry.ScenarioName,
+				"restored":            entry.Counts,
+				"dropped_activations": entry.DroppedActivations,
+			}, nil
+		},
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+
+	"github.com/namnv2496/mocktool/internal/repository"
 )
 
 // HandlerFunc receives raw JSON arguments and returns any JSON-serializable
@@ -26,11 +28,15 @@ type HandlerFunc func(ctx context.Context, args json.RawMessage) (any, error)
 // Slack-side dispatch holds destructive calls behind an explicit confirmation
 // step; MCP clients receive a hint via the standard `destructiveHint`
 // annotation.
+//
+// ReadOnly marks tools that change nothing; every other call is written to
+// the audit log.
 type Tool struct {
 	Name        string
 	Description string
 	InputSchema json.RawMessage
 	Destructive bool
+	ReadOnly    bool
 	Handler     HandlerFunc
 }
 
@@ -41,6 +47,7 @@ var ErrUnknownTool = fmt.Errorf("unknown tool")
 type Registry struct {
 	byName map[string]Tool
 	order  []string
+	audit  repository.IAuditRepository
 }
 
 // NewRegistry constructs a Registry from the given tools. Duplicate names
@@ -81,6 +88,12 @@ func (r *Registry) Get(name string) (Tool, bool) {
 	return t, ok
 }
 
+// WithAudit makes Invoke record every call of a tool that is not ReadOnly.
+func (r *Registry) WithAudit(audit repository.IAuditRepository) *Registry {
+	r.audit = audit
+	return r
+}
+
 // Invoke dispatches to the named tool's handler.
 func (r *Registry) Invoke(ctx context.Context, name string, args json.RawMessage) (any, error) {
 	t, ok := r.byName[name]
@@ -90,5 +103,9 @@ func (r *Registry) Invoke(ctx context.Context, name string, args json.RawMessage
 	if t.Handler == nil {
 		return nil, fmt.Errorf("tools: %q has no handler", name)
 	}
-	return t.Handler(ctx, args)
+	result, err := t.Handler(ctx, args)
+	if r.audit != nil && !t.ReadOnly {
+		r.record(ctx, t, args, result, err)
+	}
+	return result, err
 }
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+// ActivationReaper removes time-boxed scenario activations once they expire
+// and drops the cache entries they were serving. GetActiveScenario already
+// ignores expired activations; the reaper keeps the collection and the cache
+// clean.
+type ActivationReaper struct {
+	accountScenarioRepo repository.IAccountScenarioRepository
+	scenarioRepo        repository.IScenarioRepository
+	cacheRepo           repository.ICache
+
+	cancel context.CancelFunc
+}
+
+func NewActivationReaper(
+	accountScenarioRepo repository.IAccountScenarioRepository,
+	scenarioRepo repository.IScenarioRepository,
+	cacheRepo repository.ICache,
+) *ActivationReaper {
+	return &ActivationReaper{
+		accountScenarioRepo: accountScenarioRepo,
+		scenarioRepo:        scenarioRepo,
+		cacheRepo:           cacheRepo,
+	}
+}
+
+// Reap deletes the activations expired at now and returns how many were
+// removed.
+func (_self *ActivationReaper) Reap(ctx context.Context, now time.Time) (int, error) {
+	expired, err := _self.accountScenarioRepo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, as := range expired {
+		if err := _self.accountScenarioRepo.Delete(ctx, as.ID); err != nil {
+			return removed, err
+		}
+		removed++
+		_self.invalidate(ctx, as)
+	}
+	return removed, nil
+}
+
+func (_self *ActivationReaper) invalidate(ctx context.Context, as domain.AccountScenario) {
+	scenario, err := _self.scenarioRepo.GetByObjectID(ctx, as.ScenarioID)
+	if err != nil {
+		// Deleting the scenario already dropped its cache entries.
+		return
+	}
+	if as.AccountId == nil {
+		_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, as.FeatureName, scenario.Name))
+		return
+	}
+	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplateAccount, as.FeatureName, scenario.Name, *as.AccountId))
+}
+
+// StartWorker reaps expired activations every interval until ctx is done or
+// StopWorker is called. A non-positive interval disables the worker.
+func (_self *ActivationReaper) StartWorker(ctx context.Context, interval time.Duration) {
+	if _self == nil || interval <= 0 {
+		return
+	}
+	workerCtx, cancel := context.WithCancel(ctx)
+	_self.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		slog.Info("scenario activation reaper started", "interval", interval)
+
+		for {
+			select {
+			case <-ticker.C:
+				reapCtx, cancel := context.WithTimeout(workerCtx, 10*time.Second)
+				if n, err := _self.Reap(reapCtx, time.Now().UTC()); err != nil {
+					slog.Warn("reap expired scenario activations", "error", err)
+				} else if n > 0 {
+					slog.Info("expired scenario activations removed", "count", n)
+				}
+				cancel()
+			case <-workerCtx.Done():
+				slog.Info("scenario activation reaper stopped")
+				return
+			}
+		}
+	}()
+}
+
+// StopWorker stops the reaper.
+func (_self *ActivationReaper) StopWorker() {
+	if _self == nil || _self.cancel == nil {
+		return
+	}
+	_self.cancel()
+}
+
+// activationCacheTTL is how long a response served under as may stay
+// cached: until the activation expires, or 0 for no limit.
+func activationCacheTTL(as *domain.AccountScenario) time.Duration {
+	if as == nil || as.ExpiresAt == nil {
+		return 0
+	}
+	ttl := time.Until(*as.ExpiresAt)
+	if ttl <= 0 {
+		// Already expired: keep it for a moment rather than forever.
+		return time.Second
+	}
+	return ttl
+}
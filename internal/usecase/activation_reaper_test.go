@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	mockrepo "github.com/namnv2496/mocktool/mocks/repository"
+)
+
+func TestActivationReaper_Reap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountScenarioRepo := mockrepo.NewMockIAccountScenarioRepository(ctrl)
+	scenarioRepo := mockrepo.NewMockIScenarioRepository(ctrl)
+	cache := mockrepo.NewMockICache(ctrl)
+	reaper := NewActivationReaper(accountScenarioRepo, scenarioRepo, cache)
+
+	now := time.Now().UTC()
+	account := "qa-1"
+	errorScenario, deletedScenario := primitive.NewObjectID(), primitive.NewObjectID()
+	expired := []domain.AccountScenario{
+		{ID: primitive.NewObjectID(), FeatureName: "checkout", ScenarioID: errorScenario, AccountId: &account},
+		{ID: primitive.NewObjectID(), FeatureName: "checkout", ScenarioID: errorScenario},
+		{ID: primitive.NewObjectID(), FeatureName: "checkout", ScenarioID: deletedScenario},
+	}
+	accountScenarioRepo.EXPECT().ListExpired(gomock.Any(), now).Return(expired, nil)
+	for _, as := range expired {
+		accountScenarioRepo.EXPECT().Delete(gomock.Any(), as.ID).Return(nil)
+	}
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), errorScenario).
+		Return(&domain.Scenario{ID: errorScenario, Name: "error_500"}, nil).Times(2)
+	scenarioRepo.EXPECT().GetByObjectID(gomock.Any(), deletedScenario).Return(nil, mongo.ErrNoDocuments)
+	cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:checkout:error_500:qa-1:*").Return(nil)
+	cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:checkout:error_500:*").Return(nil)
+
+	removed, err := reaper.Reap(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+}
+
+func TestAccountScenario_SetWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	var as domain.AccountScenario
+	require.NoError(t, as.SetWindow(now, &later, nil, 30*time.Minute))
+	assert.Equal(t, later, *as.StartsAt)
+	assert.Equal(t, later.Add(30*time.Minute), *as.ExpiresAt, "the duration counts from the start")
+	assert.True(t, as.IsTimeBoxed())
+
+	past := now.Add(-time.Minute)
+	assert.Error(t, (&domain.AccountScenario{}).SetWindow(now, nil, &past, 0))
+	assert.Error(t, (&domain.AccountScenario{}).SetWindow(now, &later, &later, 0))
+	assert.Error(t, (&domain.AccountScenario{}).SetWindow(now, nil, &later, time.Minute))
+
+	var permanent domain.AccountScenario
+	require.NoError(t, permanent.SetWindow(now, nil, nil, 0))
+	assert.False(t, permanent.IsTimeBoxed())
+}
+
+func TestAccountScenario_Targets(t *testing.T) {
+	pattern := domain.AccountScenario{FeatureName: "checkout", Target: domain.ActivationTargetPattern, AccountPattern: `^qa-\d+$`}
+	require.NoError(t, pattern.ValidateTarget())
+	assert.True(t, pattern.MatchesAccount("qa-12"))
+	assert.False(t, pattern.MatchesAccount("prod-qa-12"))
+
+	prefix := domain.AccountScenario{Target: domain.ActivationTargetPattern, AccountPrefix: "beta-"}
+	assert.True(t, prefix.MatchesAccount("beta-7"))
+	assert.False(t, prefix.MatchesAccount("alpha-7"))
+
+	// The same accounts stay in a rollout when its percentage grows.
+	small := domain.AccountScenario{FeatureName: "checkout", Target: domain.ActivationTargetPercentage, Percentage: 10}
+	large := small
+	large.Percentage = 50
+	inSmall := 0
+	for i := 0; i < 1000; i++ {
+		account := fmt.Sprintf("account-%d", i)
+		if small.MatchesAccount(account) {
+			inSmall++
+			assert.True(t, large.MatchesAccount(account))
+		}
+	}
+	assert.InDelta(t, 100, inSmall, 40)
+
+	accountID := "qa-1"
+	invalid := []domain.AccountScenario{
+		{Target: domain.ActivationTargetGroup},
+		{Target: domain.ActivationTargetPattern},
+		{Target: domain.ActivationTargetPattern, AccountPattern: "("},
+		{Target: domain.ActivationTargetPattern, AccountPattern: "a", AccountPrefix: "b"},
+		{Target: domain.ActivationTargetPercentage, Percentage: 101},
+		{Target: domain.ActivationTargetGroup, GroupName: "qa", AccountId: &accountID},
+		{Target: "everyone"},
+	}
+	for _, as := range invalid {
+		assert.Error(t, as.ValidateTarget(), "%+v", as)
+	}
+}
+
+func TestActivationCacheTTL(t *testing.T) {
+	assert.Zero(t, activationCacheTTL(nil))
+	assert.Zero(t, activationCacheTTL(&domain.AccountScenario{}))
+
+	expiry := time.Now().Add(10 * time.Minute)
+	ttl := activationCacheTTL(&domain.AccountScenario{ExpiresAt: &expiry})
+	assert.InDelta(t, (10 * time.Minute).Seconds(), ttl.Seconds(), 5)
+}
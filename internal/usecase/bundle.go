@@ -0,0 +1,619 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+var (
+	ErrBundleNotFound = errors.New("export source not found")
+	ErrInvalidBundle  = errors.New("invalid bundle")
+	ErrBundleConflict = errors.New("bundle conflicts with existing mocks")
+)
+
+// ImportResult tells what an import changed, or would change on a dry run.
+type ImportResult struct {
+	DryRun    bool `json:"dry_run"`
+	Created   int  `json:"created"`
+	Updated   int  `json:"updated"`
+	Unchanged int  `json:"unchanged"`
+	// Changes lists the created and updated documents.
+	Changes []domain.BundleChange `json:"changes"`
+}
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type IBundleUC interface {
+	// Export copies a feature, or only the named scenarios of it, with their
+	// mock APIs, gRPC mock APIs and permanent activations.
+	Export(ctx context.Context, featureName string, scenarios []string) (*domain.Bundle, error)
+	// Import creates or updates the documents of a bundle, matched by name
+	// (gRPC mocks by service, method, input and matchers). Nothing is
+	// deleted, so importing a bundle twice changes nothing the second time.
+	// A dry run only reports the changes.
+	Import(ctx context.Context, bundle *domain.Bundle, dryRun bool, actor string) (*ImportResult, error)
+}
+
+// BundleUC plans an import against the current documents, then writes the
+// changes in one transaction.
+type BundleUC struct {
+	featureRepo         repository.IFeatureRepository
+	scenarioRepo        repository.IScenarioRepository
+	accountScenarioRepo repository.IAccountScenarioRepository
+	mockAPIRepo         repository.IMockAPIRepository
+	grpcMockAPIRepo     repository.IGRPCMockAPIRepository
+	bundleRepo          repository.IBundleRepository
+	descriptors         *GRPCDescriptorRegistry
+	revisions           IRevisionUC
+	cacheRepo           repository.ICache
+}
+
+func NewBundleUC(
+	featureRepo repository.IFeatureRepository,
+	scenarioRepo repository.IScenarioRepository,
+	accountScenarioRepo repository.IAccountScenarioRepository,
+	mockAPIRepo repository.IMockAPIRepository,
+	grpcMockAPIRepo repository.IGRPCMockAPIRepository,
+	bundleRepo repository.IBundleRepository,
+	descriptors *GRPCDescriptorRegistry,
+	revisions IRevisionUC,
+	cacheRepo repository.ICache,
+) IBundleUC {
+	return &BundleUC{
+		featureRepo:         featureRepo,
+		scenarioRepo:        scenarioRepo,
+		accountScenarioRepo: accountScenarioRepo,
+		mockAPIRepo:         mockAPIRepo,
+		grpcMockAPIRepo:     grpcMockAPIRepo,
+		bundleRepo:          bundleRepo,
+		descriptors:         descriptors,
+		revisions:           revisions,
+		cacheRepo:           cacheRepo,
+	}
+}
+
+func (_self *BundleUC) Export(ctx context.Context, featureName string, names []string) (*domain.Bundle, error) {
+	feature, err := _self.featureRepo.FindByName(ctx, featureName)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil || feature.Name == "" {
+		return nil, fmt.Errorf("%w: feature %q", ErrBundleNotFound, featureName)
+	}
+	scenarios, err := _self.scenarioRepo.ListByFeatureName(ctx, featureName)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+	scenarioNames := make(map[primitive.ObjectID]string, len(scenarios))
+	for _, s := range scenarios {
+		scenarioNames[s.ID] = s.Name
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(scenarios, func(s domain.Scenario) bool { return s.Name == name }) {
+			return nil, fmt.Errorf("%w: scenario %q of feature %q", ErrBundleNotFound, name, featureName)
+		}
+	}
+
+	activations, err := _self.accountScenarioRepo.ListByFeature(ctx, featureName)
+	if err != nil {
+		return nil, err
+	}
+	// Time-boxed activations are tied to their window and are not exported.
+	byScenario := map[primitive.ObjectID][]domain.BundleActivation{}
+	for i := range activations {
+		if !activations[i].IsTimeBoxed() {
+			byScenario[activations[i].ScenarioID] = append(byScenario[activations[i].ScenarioID], bundleActivation(&activations[i]))
+		}
+	}
+
+	out := domain.BundleFeature{
+		Name:        feature.Name,
+		Description: feature.Description,
+		IsActive:    activeFlag(feature.IsActive),
+	}
+	for _, s := range scenarios {
+		if len(names) > 0 && !slices.Contains(names, s.Name) {
+			continue
+		}
+		scenario := domain.BundleScenario{Name: s.Name, Description: s.Description}
+		if s.ParentID != nil {
+			scenario.Parent = scenarioNames[*s.ParentID]
+		}
+
+		mocks, err := _self.mockAPIRepo.ListByFeatureAndScenario(ctx, featureName, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range mocks {
+			scenario.MockAPIs = append(scenario.MockAPIs, bundleMockAPI(&mocks[i]))
+		}
+		sort.Slice(scenario.MockAPIs, func(i, j int) bool { return scenario.MockAPIs[i].Name < scenario.MockAPIs[j].Name })
+
+		grpcMocks, err := _self.grpcMockAPIRepo.ListByFeatureAndScenario(ctx, featureName, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range grpcMocks {
+			scenario.GRPCMockAPIs = append(scenario.GRPCMockAPIs, bundleGRPCMockAPI(&grpcMocks[i]))
+		}
+		sort.SliceStable(scenario.GRPCMockAPIs, func(i, j int) bool {
+			a, b := scenario.GRPCMockAPIs[i], scenario.GRPCMockAPIs[j]
+			return a.ServiceName+"/"+a.MethodName < b.ServiceName+"/"+b.MethodName
+		})
+
+		scenario.Activations = byScenario[s.ID]
+		sort.Slice(scenario.Activations, func(i, j int) bool {
+			return activationKey(scenario.Activations[i]) < activationKey(scenario.Activations[j])
+		})
+		out.Scenarios = append(out.Scenarios, scenario)
+	}
+
+	now := time.Now().UTC()
+	return &domain.Bundle{Version: domain.BundleVersion, ExportedAt: &now, Features: []domain.BundleFeature{out}}, nil
+}
+
+// importRun collects the writes and the report of an import.
+type importRun struct {
+	uc        *BundleUC
+	now       time.Time
+	result    *ImportResult
+	writes    repository.BundleWrites
+	revisions []importRevision
+	features  []string // features written to, for the cache
+}
+
+type importRevision struct {
+	kind   string
+	id     primitive.ObjectID
+	action string
+}
+
+func (_self *BundleUC) Import(ctx context.Context, bundle *domain.Bundle, dryRun bool, actor string) (*ImportResult, error) {
+	if bundle.Version != 0 && bundle.Version != domain.BundleVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBundle, bundle.Version)
+	}
+	run := &importRun{
+		uc:     _self,
+		now:    time.Now().UTC(),
+		result: &ImportResult{DryRun: dryRun, Changes: []domain.BundleChange{}},
+	}
+	seen := map[string]bool{}
+	for i := range bundle.Features {
+		f := &bundle.Features[i]
+		if err := validBundleName("feature", f.Name); err != nil {
+			return nil, err
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("%w: feature %q is listed twice", ErrInvalidBundle, f.Name)
+		}
+		seen[f.Name] = true
+		if err := run.feature(ctx, f); err != nil {
+			return nil, err
+		}
+	}
+	if dryRun || len(run.result.Changes) == 0 {
+		return run.result, nil
+	}
+
+	if err := _self.bundleRepo.Apply(ctx, &run.writes); err != nil {
+		return nil, err
+	}
+	if _self.revisions != nil {
+		for _, r := range run.revisions {
+			_ = _self.revisions.Record(ctx, r.kind, r.id, r.action, actor)
+		}
+	}
+	for _, name := range run.features {
+		_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, name))
+	}
+	return run.result, nil
+}
+
+// record reports a change of before into after, both in their bundle form,
+// and tells whether there is one.
+func (_self *importRun) record(change domain.BundleChange, exists bool, before, after any) bool {
+	if exists {
+		change.Action = domain.BundleActionUpdate
+		change.Fields = changedFields(before, after)
+		if len(change.Fields) == 0 {
+			_self.result.Unchanged++
+			return false
+		}
+		_self.result.Updated++
+	} else {
+		change.Action = domain.BundleActionCreate
+		_self.result.Created++
+	}
+	_self.result.Changes = append(_self.result.Changes, change)
+	if !slices.Contains(_self.features, change.Feature) {
+		_self.features = append(_self.features, change.Feature)
+	}
+	return true
+}
+
+func (_self *importRun) revision(kind string, id primitive.ObjectID, exists bool) {
+	action := domain.RevisionActionCreate
+	if exists {
+		action = domain.RevisionActionUpdate
+	}
+	_self.revisions = append(_self.revisions, importRevision{kind: kind, id: id, action: action})
+}
+
+func (_self *importRun) feature(ctx context.Context, f *domain.BundleFeature) error {
+	uc := _self.uc
+	existing, err := uc.featureRepo.FindByName(ctx, f.Name)
+	if err != nil {
+		return err
+	}
+	exists := existing != nil && existing.Name != ""
+	doc := domain.Feature{ID: primitive.NewObjectID(), CreatedAt: _self.now}
+	var before domain.BundleFeature
+	if exists {
+		doc = *existing
+		before = domain.BundleFeature{Name: existing.Name, Description: existing.Description, IsActive: activeFlag(existing.IsActive)}
+	}
+	doc.Name = f.Name
+	doc.Description = f.Description
+	doc.IsActive = isActive(f.IsActive)
+	doc.UpdatedAt = _self.now
+	after := domain.BundleFeature{Name: doc.Name, Description: doc.Description, IsActive: activeFlag(doc.IsActive)}
+	if _self.record(domain.BundleChange{Kind: domain.BundleKindFeature, Feature: f.Name}, exists, before, after) {
+		_self.writes.Features = append(_self.writes.Features, doc)
+	}
+
+	var current []domain.Scenario
+	if exists {
+		if current, err = uc.scenarioRepo.ListByFeatureName(ctx, f.Name); err != nil {
+			return err
+		}
+	}
+	byName := make(map[string]*domain.Scenario, len(current))
+	names := make(map[primitive.ObjectID]string, len(current))
+	parents := map[string]string{} // parent names once imported
+	for i := range current {
+		byName[current[i].Name] = &current[i]
+		names[current[i].ID] = current[i].Name
+	}
+	for _, s := range current {
+		if s.ParentID != nil {
+			parents[s.Name] = names[*s.ParentID]
+		}
+	}
+
+	// Every scenario gets its ID first, for parents and activations.
+	ids := make(map[string]primitive.ObjectID, len(current)+len(f.Scenarios))
+	for name, s := range byName {
+		ids[name] = s.ID
+	}
+	listed := map[string]bool{}
+	for _, s := range f.Scenarios {
+		if err := validBundleName("scenario", s.Name); err != nil {
+			return err
+		}
+		if listed[s.Name] {
+			return fmt.Errorf("%w: scenario %q of feature %q is listed twice", ErrInvalidBundle, s.Name, f.Name)
+		}
+		listed[s.Name] = true
+		if _, ok := ids[s.Name]; !ok {
+			ids[s.Name] = primitive.NewObjectID()
+		}
+		parents[s.Name] = s.Parent
+	}
+	for _, s := range f.Scenarios {
+		if err := checkBundleParents(f.Name, s.Name, parents, ids); err != nil {
+			return err
+		}
+	}
+
+	var activations []domain.AccountScenario
+	if exists {
+		if activations, err = uc.accountScenarioRepo.ListByFeature(ctx, f.Name); err != nil {
+			return err
+		}
+	}
+	permanent := map[string]*domain.AccountScenario{}
+	for i := range activations {
+		a := &activations[i]
+		if key := activationKey(bundleActivation(a)); !a.IsTimeBoxed() && permanent[key] == nil {
+			permanent[key] = a
+		}
+	}
+	targets := map[string]string{} // activation key -> scenario listing it
+
+	for i := range f.Scenarios {
+		s := &f.Scenarios[i]
+		if err := _self.scenario(ctx, f.Name, s, byName[s.Name], names, ids); err != nil {
+			return err
+		}
+		for _, a := range s.Activations {
+			key := activationKey(a)
+			if other, ok := targets[key]; ok {
+				return fmt.Errorf("%w: activation %s of feature %q is listed by scenarios %q and %q",
+					ErrInvalidBundle, key, f.Name, other, s.Name)
+			}
+			targets[key] = s.Name
+			if err := _self.activation(f.Name, s.Name, ids[s.Name], a, permanent[key], names); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkBundleParents follows the parents of a scenario, which must exist and
+// not loop.
+func checkBundleParents(feature, name string, parents map[string]string, ids map[string]primitive.ObjectID) error {
+	visited := map[string]bool{name: true}
+	for current := name; parents[current] != ""; current = parents[current] {
+		parent := parents[current]
+		if _, ok := ids[parent]; !ok {
+			return fmt.Errorf("%w: parent %q of scenario %q of feature %q does not exist", ErrInvalidBundle, parent, current, feature)
+		}
+		if visited[parent] {
+			return fmt.Errorf("%w: scenario %q of feature %q inherits from itself", ErrInvalidBundle, name, feature)
+		}
+		visited[parent] = true
+	}
+	return nil
+}
+
+func (_self *importRun) scenario(
+	ctx context.Context,
+	feature string,
+	s *domain.BundleScenario,
+	existing *domain.Scenario,
+	names map[primitive.ObjectID]string,
+	ids map[string]primitive.ObjectID,
+) error {
+	uc := _self.uc
+	exists := existing != nil
+	doc := domain.Scenario{CreatedAt: _self.now}
+	var before domain.BundleScenario
+	if exists {
+		doc = *existing
+		before = domain.BundleScenario{Name: existing.Name, Description: existing.Description}
+		if existing.ParentID != nil {
+			before.Parent = names[*existing.ParentID]
+		}
+	}
+	doc.ID = ids[s.Name]
+	doc.FeatureName = feature
+	doc.Name = s.Name
+	doc.Description = s.Description
+	doc.ParentID = nil
+	if s.Parent != "" {
+		parentID := ids[s.Parent]
+		doc.ParentID = &parentID
+	}
+	doc.UpdatedAt = _self.now
+	after := domain.BundleScenario{Name: s.Name, Description: s.Description, Parent: s.Parent}
+	change := domain.BundleChange{Kind: domain.BundleKindScenario, Feature: feature, Scenario: s.Name}
+	if _self.record(change, exists, before, after) {
+		_self.writes.Scenarios = append(_self.writes.Scenarios, doc)
+		_self.revision(domain.RevisionKindScenario, doc.ID, exists)
+	}
+
+	var mocks []domain.MockAPI
+	var grpcMocks []domain.GRPCMockAPI
+	if exists {
+		var err error
+		if mocks, err = uc.mockAPIRepo.ListByFeatureAndScenario(ctx, feature, s.Name); err != nil {
+			return err
+		}
+		if grpcMocks, err = uc.grpcMockAPIRepo.ListByFeatureAndScenario(ctx, feature, s.Name); err != nil {
+			return err
+		}
+	}
+	if err := _self.mockAPIs(feature, s, mocks); err != nil {
+		return err
+	}
+	return _self.grpcMockAPIs(feature, s, grpcMocks)
+}
+
+func (_self *importRun) mockAPIs(feature string, s *domain.BundleScenario, current []domain.MockAPI) error {
+	byName := make(map[string]*domain.MockAPI, len(current))
+	// The request each mock answers, which must stay unique in the scenario.
+	requests := make(map[string]string, len(current))
+	for i := range current {
+		byName[current[i].Name] = &current[i]
+		requests[current[i].Name] = mockAPIRequestKey(&current[i])
+	}
+	listed := map[string]bool{}
+	for i := range s.MockAPIs {
+		b := &s.MockAPIs[i]
+		if err := validBundleName("mock API", b.Name); err != nil {
+			return err
+		}
+		if b.Path == "" || b.Method == "" {
+			return fmt.Errorf("%w: mock API %q of scenario %q needs a path and a method", ErrInvalidBundle, b.Name, s.Name)
+		}
+		if listed[b.Name] {
+			return fmt.Errorf("%w: mock API %q of scenario %q is listed twice", ErrInvalidBundle, b.Name, s.Name)
+		}
+		listed[b.Name] = true
+		m, err := mockAPIFromBundle(feature, s.Name, b)
+		if err != nil {
+			return fmt.Errorf("%w: mock API %q of scenario %q: %v", ErrInvalidBundle, b.Name, s.Name, err)
+		}
+		requests[b.Name] = mockAPIRequestKey(m)
+
+		existing := byName[b.Name]
+		exists := existing != nil
+		m.ID = primitive.NewObjectID()
+		m.CreatedAt = _self.now
+		var before domain.BundleMockAPI
+		if exists {
+			m.ID = existing.ID
+			m.CreatedAt = existing.CreatedAt
+			before = bundleMockAPI(existing)
+		}
+		m.UpdatedAt = _self.now
+		change := domain.BundleChange{Kind: domain.BundleKindMockAPI, Feature: feature, Scenario: s.Name, Name: b.Name}
+		if _self.record(change, exists, before, bundleMockAPI(m)) {
+			_self.writes.MockAPIs = append(_self.writes.MockAPIs, *m)
+			_self.revision(domain.RevisionKindMockAPI, m.ID, exists)
+		}
+	}
+
+	served := make(map[string]string, len(requests))
+	for name, key := range requests {
+		if other, ok := served[key]; ok {
+			first, second := min(name, other), max(name, other)
+			return fmt.Errorf("%w: mock APIs %q and %q of scenario %q of feature %q answer the same request",
+				ErrBundleConflict, first, second, s.Name, feature)
+		}
+		served[key] = name
+	}
+	return nil
+}
+
+func mockAPIRequestKey(m *domain.MockAPI) string {
+	return strings.ToUpper(m.Method) + " " + m.Path + " " + m.HashInput
+}
+
+func (_self *importRun) grpcMockAPIs(feature string, s *domain.BundleScenario, current []domain.GRPCMockAPI) error {
+	byKey := make(map[string]*domain.GRPCMockAPI, len(current))
+	for i := range current {
+		byKey[grpcMockAPIKey(&current[i])] = &current[i]
+	}
+	listed := map[string]bool{}
+	for i := range s.GRPCMockAPIs {
+		b := &s.GRPCMockAPIs[i]
+		name := b.ServiceName + "/" + b.MethodName
+		if b.ServiceName == "" || b.MethodName == "" {
+			return fmt.Errorf("%w: gRPC mock of scenario %q needs a service_name and a method_name", ErrInvalidBundle, s.Name)
+		}
+		m, err := grpcMockAPIFromBundle(feature, s.Name, b)
+		if err == nil {
+			_, err = _self.uc.descriptors.HashTypedInputs(m)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: gRPC mock %s of scenario %q: %v", ErrInvalidBundle, name, s.Name, err)
+		}
+		key := grpcMockAPIKey(m)
+		if listed[key] {
+			return fmt.Errorf("%w: gRPC mock %s of scenario %q is listed twice with the same input and matchers",
+				ErrInvalidBundle, name, s.Name)
+		}
+		listed[key] = true
+
+		existing := byKey[key]
+		exists := existing != nil
+		m.ID = primitive.NewObjectID()
+		m.CreatedAt = _self.now
+		var before domain.BundleGRPCMockAPI
+		if exists {
+			m.ID = existing.ID
+			m.CreatedAt = existing.CreatedAt
+			before = bundleGRPCMockAPI(existing)
+		}
+		m.UpdatedAt = _self.now
+		change := domain.BundleChange{Kind: domain.BundleKindGRPCMockAPI, Feature: feature, Scenario: s.Name, Name: name}
+		if _self.record(change, exists, before, bundleGRPCMockAPI(m)) {
+			_self.writes.GRPCMockAPIs = append(_self.writes.GRPCMockAPIs, *m)
+			_self.revision(domain.RevisionKindGRPCMockAPI, m.ID, exists)
+		}
+	}
+	return nil
+}
+
+// grpcMockAPIKey identifies a gRPC mock within its scenario: two mocks with
+// the same key would answer the same calls.
+func grpcMockAPIKey(m *domain.GRPCMockAPI) string {
+	matchers, _ := json.Marshal(m.Matchers)
+	return m.ServiceName + "/" + m.MethodName + " " + m.HashInput + " " + string(matchers)
+}
+
+// bundleActivationState is what an import sets on an activation.
+type bundleActivationState struct {
+	Scenario   string `json:"scenario"`
+	Percentage int    `json:"percentage,omitempty"`
+}
+
+func (_self *importRun) activation(
+	feature, scenario string,
+	scenarioID primitive.ObjectID,
+	a domain.BundleActivation,
+	existing *domain.AccountScenario,
+	names map[primitive.ObjectID]string,
+) error {
+	doc := domain.AccountScenario{
+		ID:             primitive.NewObjectID(),
+		FeatureName:    feature,
+		Target:         a.Target,
+		GroupName:      strings.TrimSpace(a.GroupName),
+		AccountPattern: a.AccountPattern,
+		AccountPrefix:  a.AccountPrefix,
+		CreatedAt:      _self.now,
+	}
+	if a.AccountId != "" {
+		accountId := a.AccountId
+		doc.AccountId = &accountId
+	}
+	if err := doc.ValidateTarget(); err != nil {
+		return fmt.Errorf("%w: activation of scenario %q of feature %q: %v", ErrInvalidBundle, scenario, feature, err)
+	}
+	exists := existing != nil
+	var before bundleActivationState
+	if exists {
+		doc = *existing
+		before = bundleActivationState{Scenario: names[existing.ScenarioID], Percentage: existing.Percentage}
+	}
+	doc.ScenarioID = scenarioID
+	doc.Percentage = a.Percentage
+	doc.UpdatedAt = _self.now
+	after := bundleActivationState{Scenario: scenario, Percentage: a.Percentage}
+	change := domain.BundleChange{Kind: domain.BundleKindActivation, Feature: feature, Scenario: scenario, Name: activationKey(a)}
+	if _self.record(change, exists, before, after) {
+		_self.writes.Activations = append(_self.writes.Activations, doc)
+	}
+	return nil
+}
+
+func validBundleName(kind, name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: a %s has no name", ErrInvalidBundle, kind)
+	case strings.ContainsAny(name, " \t\n"):
+		return fmt.Errorf("%w: %s name %q cannot contain spaces", ErrInvalidBundle, kind, name)
+	}
+	return nil
+}
+
+// changedFields lists the top-level JSON fields that differ between before
+// and after.
+func changedFields(before, after any) []string {
+	b, a := jsonFields(before), jsonFields(after)
+	var fields []string
+	for key, value := range a {
+		if !reflect.DeepEqual(b[key], value) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func jsonFields(v any) map[string]any {
+	data, _ := json.Marshal(v)
+	var fields map[string]any
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
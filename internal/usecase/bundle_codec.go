@@ -0,0 +1,378 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gopkg.in/yaml.v3"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/pkg/security"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+// Bundle formats.
+const (
+	BundleFormatJSON = "json"
+	BundleFormatYAML = "yaml"
+)
+
+// EncodeBundle writes a bundle as indented JSON or as YAML, keeping the field
+// order of the JSON form so exports diff well in git.
+func EncodeBundle(bundle *domain.Bundle, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", BundleFormatJSON:
+		return data, nil
+	case BundleFormatYAML:
+		// JSON is YAML: parse it as a node tree and print it in block style.
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		blockStyle(&node)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), enc.Close()
+	}
+	return nil, fmt.Errorf("%w: unknown format %q, expected json or yaml", ErrInvalidBundle, format)
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// DecodeBundle reads a JSON or YAML bundle. Unknown fields are rejected to
+// catch typos in hand-edited bundles.
+func DecodeBundle(data []byte) (*domain.Bundle, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("%w: empty bundle", ErrInvalidBundle)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var bundle domain.Bundle
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	return &bundle, nil
+}
+
+// payloadToJSON turns a stored payload into a plain JSON value.
+func payloadToJSON(raw bson.Raw) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// payloadToBSON stores a JSON object, or a string holding one, like the
+// create endpoints do.
+func payloadToBSON(raw json.RawMessage) (bson.Raw, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	if str, ok := data.(string); ok {
+		if err := json.Unmarshal([]byte(str), &data); err != nil {
+			return nil, err
+		}
+	}
+	return bson.Marshal(data)
+}
+
+// headersToBSON stores an HTTP header object sanitized like the create
+// endpoint does.
+func headersToBSON(raw json.RawMessage) (bson.Raw, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal(raw, &headers); err != nil {
+		return nil, err
+	}
+	sanitized, _ := security.ValidateAndSanitizeHeaders(headers)
+	return bson.Marshal(sanitized)
+}
+
+// activeFlag leaves the default (active) out of the bundle.
+func activeFlag(active bool) *bool {
+	if active {
+		return nil
+	}
+	return &active
+}
+
+func isActive(flag *bool) bool {
+	return flag == nil || *flag
+}
+
+func bundleMockAPI(m *domain.MockAPI) domain.BundleMockAPI {
+	out := domain.BundleMockAPI{
+		Name:        m.Name,
+		Description: m.Description,
+		IsActive:    activeFlag(m.IsActive),
+		BaseURL:     m.BaseURL,
+		Path:        m.Path,
+		Method:      m.Method,
+		Input:       payloadToJSON(m.Input),
+		Headers:     payloadToJSON(m.Headers),
+		Output:      payloadToJSON(m.Output),
+		StatusCode:  m.StatusCode,
+		Latency:     m.Latency,
+	}
+	for _, r := range m.Responses {
+		out.Responses = append(out.Responses, domain.BundleSequenceResponse{
+			From:       r.From,
+			To:         r.To,
+			StatusCode: r.StatusCode,
+			Output:     payloadToJSON(r.Output),
+			Headers:    payloadToJSON(r.Headers),
+			Latency:    r.Latency,
+		})
+	}
+	return out
+}
+
+// mockAPIFromBundle builds the stored form of a mock, without ID and
+// timestamps.
+func mockAPIFromBundle(featureName, scenarioName string, b *domain.BundleMockAPI) (*domain.MockAPI, error) {
+	m := &domain.MockAPI{
+		FeatureName:  featureName,
+		ScenarioName: scenarioName,
+		Name:         b.Name,
+		Description:  b.Description,
+		IsActive:     isActive(b.IsActive),
+		BaseURL:      b.BaseURL,
+		Path:         b.Path,
+		Method:       b.Method,
+		StatusCode:   b.StatusCode,
+		Latency:      b.Latency,
+	}
+	var err error
+	if m.Input, err = payloadToBSON(b.Input); err != nil {
+		return nil, fmt.Errorf("input: %w", err)
+	}
+	m.HashInput = utils.GenerateHashFromInput(m.Input)
+	if m.Headers, err = headersToBSON(b.Headers); err != nil {
+		return nil, fmt.Errorf("headers: %w", err)
+	}
+	if m.Output, err = payloadToBSON(b.Output); err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	if m.Output == nil && len(b.Responses) == 0 {
+		return nil, fmt.Errorf("output is required")
+	}
+	for i, r := range b.Responses {
+		resp := domain.SequenceResponse{From: r.From, To: r.To, StatusCode: r.StatusCode, Latency: r.Latency}
+		if resp.Output, err = payloadToBSON(r.Output); err != nil {
+			return nil, fmt.Errorf("responses[%d].output: %w", i, err)
+		}
+		if resp.Headers, err = headersToBSON(r.Headers); err != nil {
+			return nil, fmt.Errorf("responses[%d].headers: %w", i, err)
+		}
+		m.Responses = append(m.Responses, resp)
+	}
+	return m, nil
+}
+
+func bundleGRPCMockAPI(m *domain.GRPCMockAPI) domain.BundleGRPCMockAPI {
+	out := domain.BundleGRPCMockAPI{
+		ServiceName:   m.ServiceName,
+		MethodName:    m.MethodName,
+		IsActive:      activeFlag(m.IsActive),
+		Input:         payloadToJSON(m.Input),
+		Matchers:      m.Matchers,
+		Output:        payloadToJSON(m.Output),
+		StatusCode:    m.StatusCode,
+		StatusMessage: m.StatusMessage,
+		Latency:       m.Latency,
+		Hang:          m.Hang,
+		StreamType:    m.StreamType,
+		Responses:     bundleStreamMessages(m.Responses),
+		Aggregate:     m.Aggregate,
+		Headers:       m.Headers,
+		Trailers:      m.Trailers,
+	}
+	for _, d := range m.Details {
+		out.Details = append(out.Details, domain.BundleGRPCStatusDetail{Type: d.Type, Value: payloadToJSON(d.Value)})
+	}
+	for _, r := range m.BidiRules {
+		out.BidiRules = append(out.BidiRules, domain.BundleGRPCBidiRule{
+			Input:     payloadToJSON(r.Input),
+			Responses: bundleStreamMessages(r.Responses),
+		})
+	}
+	for _, s := range m.Sequence {
+		out.Sequence = append(out.Sequence, domain.BundleGRPCSequenceResponse{
+			From:          s.From,
+			To:            s.To,
+			StatusCode:    s.StatusCode,
+			StatusMessage: s.StatusMessage,
+			Output:        payloadToJSON(s.Output),
+			Latency:       s.Latency,
+		})
+	}
+	return out
+}
+
+func bundleStreamMessages(msgs []domain.GRPCStreamMessage) []domain.BundleGRPCStreamMessage {
+	var out []domain.BundleGRPCStreamMessage
+	for _, msg := range msgs {
+		out = append(out, domain.BundleGRPCStreamMessage{Output: payloadToJSON(msg.Output), Delay: msg.Delay})
+	}
+	return out
+}
+
+// grpcMockAPIFromBundle builds the stored form of a gRPC mock, without ID
+// and timestamps.
+func grpcMockAPIFromBundle(featureName, scenarioName string, b *domain.BundleGRPCMockAPI) (*domain.GRPCMockAPI, error) {
+	m := &domain.GRPCMockAPI{
+		FeatureName:   featureName,
+		ScenarioName:  scenarioName,
+		ServiceName:   b.ServiceName,
+		MethodName:    b.MethodName,
+		IsActive:      isActive(b.IsActive),
+		Matchers:      b.Matchers,
+		StatusCode:    b.StatusCode,
+		StatusMessage: b.StatusMessage,
+		Latency:       b.Latency,
+		Hang:          b.Hang,
+		StreamType:    b.StreamType,
+		Aggregate:     b.Aggregate,
+		Headers:       b.Headers,
+		Trailers:      b.Trailers,
+	}
+	switch b.StreamType {
+	case domain.GRPCStreamUnary, domain.GRPCStreamServer, domain.GRPCStreamClient:
+	case domain.GRPCStreamBidi:
+		if len(b.BidiRules) == 0 {
+			return nil, fmt.Errorf("bidi_rules is required for bidi mocks")
+		}
+	default:
+		return nil, fmt.Errorf("unknown stream_type %q", b.StreamType)
+	}
+	var err error
+	if m.Input, err = payloadToBSON(b.Input); err != nil {
+		return nil, fmt.Errorf("input: %w", err)
+	}
+	if m.Input != nil {
+		m.HashInput = utils.HashInputConsistent(m.Input)
+	}
+	if m.Output, err = payloadToBSON(b.Output); err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	if m.Output == nil && ((b.StreamType == domain.GRPCStreamUnary && len(b.Sequence) == 0) || b.StreamType == domain.GRPCStreamClient) {
+		return nil, fmt.Errorf("output is required")
+	}
+	for i, d := range b.Details {
+		value, err := payloadToBSON(d.Value)
+		if err != nil {
+			return nil, fmt.Errorf("details[%d].value: %w", i, err)
+		}
+		m.Details = append(m.Details, domain.GRPCStatusDetail{Type: d.Type, Value: value})
+	}
+	if m.Responses, err = streamMessagesFromBundle(b.Responses); err != nil {
+		return nil, err
+	}
+	for i, r := range b.BidiRules {
+		rule := domain.GRPCBidiRule{}
+		if rule.Input, err = payloadToBSON(r.Input); err != nil {
+			return nil, fmt.Errorf("bidi_rules[%d].input: %w", i, err)
+		}
+		if rule.Input != nil {
+			rule.HashInput = utils.GenerateHashFromInput(rule.Input)
+		}
+		if rule.Responses, err = streamMessagesFromBundle(r.Responses); err != nil {
+			return nil, fmt.Errorf("bidi_rules[%d]: %w", i, err)
+		}
+		m.BidiRules = append(m.BidiRules, rule)
+	}
+	for i, s := range b.Sequence {
+		step := domain.GRPCSequenceResponse{
+			From:          s.From,
+			To:            s.To,
+			StatusCode:    s.StatusCode,
+			StatusMessage: s.StatusMessage,
+			Latency:       s.Latency,
+		}
+		if step.Output, err = payloadToBSON(s.Output); err != nil {
+			return nil, fmt.Errorf("sequence[%d].output: %w", i, err)
+		}
+		m.Sequence = append(m.Sequence, step)
+	}
+	return m, nil
+}
+
+func streamMessagesFromBundle(msgs []domain.BundleGRPCStreamMessage) ([]domain.GRPCStreamMessage, error) {
+	var out []domain.GRPCStreamMessage
+	for i, msg := range msgs {
+		output, err := payloadToBSON(msg.Output)
+		if err != nil {
+			return nil, fmt.Errorf("responses[%d].output: %w", i, err)
+		}
+		out = append(out, domain.GRPCStreamMessage{Output: output, Delay: msg.Delay})
+	}
+	return out, nil
+}
+
+func bundleActivation(as *domain.AccountScenario) domain.BundleActivation {
+	out := domain.BundleActivation{
+		Target:         as.Target,
+		GroupName:      as.GroupName,
+		AccountPattern: as.AccountPattern,
+		AccountPrefix:  as.AccountPrefix,
+		Percentage:     as.Percentage,
+	}
+	if as.AccountId != nil {
+		out.AccountId = *as.AccountId
+	}
+	return out
+}
+
+// activationKey identifies the activations an ActivateScenario call with the
+// same target would replace.
+func activationKey(a domain.BundleActivation) string {
+	switch a.Target {
+	case "":
+		if a.AccountId == "" {
+			return "global"
+		}
+		return "account:" + a.AccountId
+	case domain.ActivationTargetGroup:
+		return "group:" + a.GroupName
+	case domain.ActivationTargetPattern:
+		if a.AccountPrefix != "" {
+			return "prefix:" + a.AccountPrefix
+		}
+		return "pattern:" + a.AccountPattern
+	}
+	return a.Target
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+// recordedRevisions keeps the Record calls; usecase mocks cannot be imported
+// here.
+type recordedRevisions struct {
+	IRevisionUC
+	actions []string
+}
+
+func (_self *recordedRevisions) Record(_ context.Context, kind string, _ primitive.ObjectID, action, author string) error {
+	_self.actions = append(_self.actions, kind+" "+action+" "+author)
+	return nil
+}
+
+func newTestBundleUC(t *testing.T) (IBundleUC, *repoMocks, *recordedRevisions) {
+	r := newRepoMocks(t)
+	revisions := &recordedRevisions{}
+	return NewBundleUC(r.features, r.scenarios, r.activations, r.mockAPIs, r.grpcMocks, r.bundles, nil, revisions, r.cache), r, revisions
+}
+
+func bsonDoc(t *testing.T, v any) bson.Raw {
+	data, err := bson.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+// expectStoredBundle sets up insertAd with a "base" scenario and a "declined"
+// one inheriting from it, active for everyone, and returns their IDs.
+func expectStoredBundle(t *testing.T, r *repoMocks) (primitive.ObjectID, primitive.ObjectID) {
+	baseID, declinedID := primitive.NewObjectID(), primitive.NewObjectID()
+	expiry := time.Now().Add(time.Hour)
+	accountId := "42"
+	input := bsonDoc(t, bson.M{"amount": 10})
+	r.features.EXPECT().FindByName(gomock.Any(), "insertAd").
+		Return(&domain.Feature{ID: primitive.NewObjectID(), Name: "insertAd", Description: "post an ad", IsActive: true}, nil).AnyTimes()
+	r.scenarios.EXPECT().ListByFeatureName(gomock.Any(), "insertAd").Return([]domain.Scenario{
+		{ID: declinedID, FeatureName: "insertAd", Name: "declined", ParentID: &baseID},
+		{ID: baseID, FeatureName: "insertAd", Name: "base"},
+	}, nil).AnyTimes()
+	r.activations.EXPECT().ListByFeature(gomock.Any(), "insertAd").Return([]domain.AccountScenario{
+		{ID: primitive.NewObjectID(), FeatureName: "insertAd", ScenarioID: declinedID},
+		{ID: primitive.NewObjectID(), FeatureName: "insertAd", ScenarioID: baseID, AccountId: &accountId, ExpiresAt: &expiry},
+	}, nil).AnyTimes()
+	r.mockAPIs.EXPECT().ListByFeatureAndScenario(gomock.Any(), "insertAd", "base").Return(nil, nil).AnyTimes()
+	r.mockAPIs.EXPECT().ListByFeatureAndScenario(gomock.Any(), "insertAd", "declined").Return([]domain.MockAPI{{
+		ID:           primitive.NewObjectID(),
+		FeatureName:  "insertAd",
+		ScenarioName: "declined",
+		Name:         "pay",
+		IsActive:     true,
+		Path:         "/pay",
+		Method:       "POST",
+		Input:        input,
+		HashInput:    "h",
+		Output:       bsonDoc(t, bson.M{"status": "declined"}),
+		StatusCode:   402,
+	}}, nil).AnyTimes()
+	r.grpcMocks.EXPECT().ListByFeatureAndScenario(gomock.Any(), "insertAd", "base").Return(nil, nil).AnyTimes()
+	r.grpcMocks.EXPECT().ListByFeatureAndScenario(gomock.Any(), "insertAd", "declined").Return(nil, nil).AnyTimes()
+	return baseID, declinedID
+}
+
+func TestBundleUC_Export(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("whole feature", func(t *testing.T) {
+		uc, r, _ := newTestBundleUC(t)
+		expectStoredBundle(t, r)
+
+		bundle, err := uc.Export(ctx, "insertAd", nil)
+		require.NoError(t, err)
+		require.Len(t, bundle.Features, 1)
+		feature := bundle.Features[0]
+		assert.Nil(t, feature.IsActive, "active is the default")
+		require.Len(t, feature.Scenarios, 2)
+		assert.Equal(t, "base", feature.Scenarios[0].Name, "sorted by name")
+		declined := feature.Scenarios[1]
+		assert.Equal(t, "base", declined.Parent)
+		require.Len(t, declined.MockAPIs, 1)
+		assert.JSONEq(t, `{"amount":10}`, string(declined.MockAPIs[0].Input))
+		assert.JSONEq(t, `{"status":"declined"}`, string(declined.MockAPIs[0].Output))
+		assert.Equal(t, []domain.BundleActivation{{}}, declined.Activations)
+		assert.Empty(t, feature.Scenarios[0].Activations, "time-boxed activations are not exported")
+	})
+
+	t.Run("unknown scenario", func(t *testing.T) {
+		uc, r, _ := newTestBundleUC(t)
+		expectStoredBundle(t, r)
+
+		_, err := uc.Export(ctx, "insertAd", []string{"missing"})
+		assert.True(t, errors.Is(err, ErrBundleNotFound))
+	})
+}
+
+func TestBundleUC_ImportIsIdempotent(t *testing.T) {
+	uc, r, _ := newTestBundleUC(t)
+	expectStoredBundle(t, r)
+	ctx := context.Background()
+
+	bundle, err := uc.Export(ctx, "insertAd", nil)
+	require.NoError(t, err)
+	// Through the file format and back, as when shared between machines.
+	data, err := EncodeBundle(bundle, BundleFormatYAML)
+	require.NoError(t, err)
+	bundle, err = DecodeBundle(data)
+	require.NoError(t, err)
+
+	result, err := uc.Import(ctx, bundle, false, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, result.Changes)
+	assert.Equal(t, 0, result.Created+result.Updated)
+	assert.Equal(t, 5, result.Unchanged) // feature, 2 scenarios, mock, activation
+}
+
+func TestBundleUC_ImportDryRun(t *testing.T) {
+	uc, r, revisions := newTestBundleUC(t)
+	_, declinedID := expectStoredBundle(t, r)
+	ctx := context.Background()
+
+	bundle, err := uc.Export(ctx, "insertAd", nil)
+	require.NoError(t, err)
+	declined := &bundle.Features[0].Scenarios[1]
+	declined.MockAPIs[0].StatusCode = 400
+	declined.MockAPIs = append(declined.MockAPIs, domain.BundleMockAPI{
+		Name: "refund", Path: "/refund", Method: "POST", Output: json.RawMessage(`{"ok":true}`),
+	})
+	// everyone moves to base
+	bundle.Features[0].Scenarios[0].Activations = declined.Activations
+	declined.Activations = nil
+
+	result, err := uc.Import(ctx, bundle, true, "alice")
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 2, result.Updated)
+	assert.Contains(t, result.Changes, domain.BundleChange{
+		Kind: domain.BundleKindMockAPI, Action: domain.BundleActionUpdate, Feature: "insertAd", Scenario: "declined",
+		Name: "pay", Fields: []string{"status_code"},
+	})
+	assert.Contains(t, result.Changes, domain.BundleChange{
+		Kind: domain.BundleKindActivation, Action: domain.BundleActionUpdate, Feature: "insertAd", Scenario: "base",
+		Name: "global", Fields: []string{"scenario"},
+	})
+
+	// The same import for real writes the three documents.
+	r.bundles.EXPECT().Apply(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, writes *repository.BundleWrites) error {
+			assert.Len(t, writes.MockAPIs, 2)
+			require.Len(t, writes.Activations, 1)
+			assert.NotEqual(t, declinedID, writes.Activations[0].ScenarioID)
+			assert.Empty(t, writes.Scenarios)
+			return nil
+		},
+	)
+	r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:insertAd:*").Return(nil)
+
+	result, err = uc.Import(ctx, bundle, false, "alice")
+	require.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Len(t, result.Changes, 3)
+	assert.Equal(t, []string{"mock_api update alice", "mock_api create alice"}, revisions.actions)
+}
+
+func TestBundleUC_ImportNewFeature(t *testing.T) {
+	uc, r, revisions := newTestBundleUC(t)
+	ctx := context.Background()
+	r.features.EXPECT().FindByName(gomock.Any(), "search").Return(&domain.Feature{}, nil)
+	r.bundles.EXPECT().Apply(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, writes *repository.BundleWrites) error {
+			require.Len(t, writes.Scenarios, 2)
+			empty := writes.Scenarios[1]
+			require.NotNil(t, empty.ParentID)
+			assert.Equal(t, writes.Scenarios[0].ID, *empty.ParentID)
+			require.Len(t, writes.GRPCMockAPIs, 1)
+			assert.NotEmpty(t, writes.GRPCMockAPIs[0].HashInput)
+			assert.True(t, writes.GRPCMockAPIs[0].IsActive)
+			return nil
+		},
+	)
+	r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:search:*").Return(nil)
+
+	bundle, err := DecodeBundle([]byte(`
+version: 1
+features:
+  - name: search
+    scenarios:
+      - name: base
+      - name: empty
+        parent: base
+        grpc_mock_apis:
+          - service_name: search.v1.Search
+            method_name: Query
+            input: {q: shoes}
+            output: {results: []}
+`))
+	require.NoError(t, err)
+	result, err := uc.Import(ctx, bundle, false, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Created)
+	assert.Equal(t, []string{"scenario create alice", "scenario create alice", "grpc_mock_api create alice"}, revisions.actions)
+}
+
+func TestBundleUC_ImportRejects(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown parent", func(t *testing.T) {
+		uc, r, _ := newTestBundleUC(t)
+		r.features.EXPECT().FindByName(gomock.Any(), "search").Return(&domain.Feature{}, nil)
+		bundle := &domain.Bundle{Features: []domain.BundleFeature{{
+			Name:      "search",
+			Scenarios: []domain.BundleScenario{{Name: "empty", Parent: "base"}},
+		}}}
+
+		_, err := uc.Import(ctx, bundle, false, "alice")
+		assert.True(t, errors.Is(err, ErrInvalidBundle))
+	})
+
+	t.Run("two mocks for one request", func(t *testing.T) {
+		uc, r, _ := newTestBundleUC(t)
+		r.features.EXPECT().FindByName(gomock.Any(), "search").Return(&domain.Feature{}, nil)
+		output := json.RawMessage(`{"ok":true}`)
+		bundle := &domain.Bundle{Features: []domain.BundleFeature{{
+			Name: "search",
+			Scenarios: []domain.BundleScenario{{Name: "base", MockAPIs: []domain.BundleMockAPI{
+				{Name: "a", Path: "/search", Method: "GET", Output: output},
+				{Name: "b", Path: "/search", Method: "get", Output: output},
+			}}},
+		}}}
+
+		_, err := uc.Import(ctx, bundle, false, "alice")
+		assert.True(t, errors.Is(err, ErrBundleConflict))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := DecodeBundle([]byte(`{"version": 1, "features": [{"name": "search", "senarios": []}]}`))
+		assert.True(t, errors.Is(err, ErrInvalidBundle))
+	})
+}
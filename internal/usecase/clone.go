@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+var (
+	ErrCloneSourceNotFound = errors.New("clone source not found")
+	ErrCloneConflict       = errors.New("clone target already exists")
+	ErrInvalidClone        = errors.New("invalid clone")
+)
+
+// CloneOptions tune how the copies are named.
+type CloneOptions struct {
+	// NamePrefix is prepended to the names of the copied scenarios and mock
+	// APIs, e.g. "v2-".
+	NamePrefix string `json:"name_prefix"`
+}
+
+// CloneResult summarizes what a clone created.
+type CloneResult struct {
+	Feature      string   `json:"feature"`
+	Scenarios    []string `json:"scenarios"`
+	MockAPIs     int      `json:"mock_apis"`
+	GRPCMockAPIs int      `json:"grpc_mock_apis"`
+}
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type ICloneUC interface {
+	// CloneFeature copies a feature with all its scenarios, mock APIs and
+	// gRPC mock APIs under the name target.
+	CloneFeature(ctx context.Context, source, target string, opts CloneOptions) (*CloneResult, error)
+	// CloneScenario copies a scenario with its mocks into targetFeature,
+	// which may be its own feature. targetScenario defaults to the prefixed
+	// source name.
+	CloneScenario(ctx context.Context, sourceFeature, sourceScenario, targetFeature, targetScenario string, opts CloneOptions) (*CloneResult, error)
+}
+
+// CloneUC copies documents with new IDs and rewritten names. There is no
+// transaction: when a step fails, the documents created so far are deleted.
+// Scenario activations are not copied.
+type CloneUC struct {
+	featureRepo     repository.IFeatureRepository
+	scenarioRepo    repository.IScenarioRepository
+	mockAPIRepo     repository.IMockAPIRepository
+	grpcMockAPIRepo repository.IGRPCMockAPIRepository
+	cacheRepo       repository.ICache
+}
+
+func NewCloneUC(
+	featureRepo repository.IFeatureRepository,
+	scenarioRepo repository.IScenarioRepository,
+	mockAPIRepo repository.IMockAPIRepository,
+	grpcMockAPIRepo repository.IGRPCMockAPIRepository,
+	cacheRepo repository.ICache,
+) ICloneUC {
+	return &CloneUC{
+		featureRepo:     featureRepo,
+		scenarioRepo:    scenarioRepo,
+		mockAPIRepo:     mockAPIRepo,
+		grpcMockAPIRepo: grpcMockAPIRepo,
+		cacheRepo:       cacheRepo,
+	}
+}
+
+// cloneRun remembers the created documents to delete them on failure.
+type cloneRun struct {
+	uc     *CloneUC
+	result *CloneResult
+	undo   []func(context.Context)
+}
+
+func (_self *cloneRun) rollback(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	for i := len(_self.undo) - 1; i >= 0; i-- {
+		_self.undo[i](ctx)
+	}
+}
+
+func (_self *CloneUC) CloneFeature(ctx context.Context, source, target string, opts CloneOptions) (*CloneResult, error) {
+	if source == "" || target == "" {
+		return nil, fmt.Errorf("%w: source and target features are required", ErrInvalidClone)
+	}
+	feature, err := _self.featureRepo.FindByName(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil || feature.Name == "" {
+		return nil, fmt.Errorf("%w: feature %q", ErrCloneSourceNotFound, source)
+	}
+	existing, err := _self.featureRepo.FindByName(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Name != "" {
+		return nil, fmt.Errorf("%w: feature %q", ErrCloneConflict, target)
+	}
+	scenarios, err := _self.scenarioRepo.ListByFeatureName(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &cloneRun{uc: _self, result: &CloneResult{Feature: target, Scenarios: []string{}}}
+	now := time.Now().UTC()
+	copied := &domain.Feature{
+		Name:        target,
+		Description: feature.Description,
+		IsActive:    feature.IsActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := _self.featureRepo.Create(ctx, copied); err != nil {
+		return nil, err
+	}
+	run.undo = append(run.undo, func(ctx context.Context) { _ = _self.featureRepo.DeleteById(ctx, copied.ID) })
+
+	// Parents are linked once every scenario has its new ID.
+	newIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(scenarios))
+	clones := make([]*domain.Scenario, 0, len(scenarios))
+	for i := range scenarios {
+		clone, err := run.scenario(ctx, &scenarios[i], target, opts.NamePrefix+scenarios[i].Name, nil, opts.NamePrefix)
+		if err != nil {
+			run.rollback(ctx)
+			return nil, err
+		}
+		newIDs[scenarios[i].ID] = clone.ID
+		clones = append(clones, clone)
+	}
+	for i, s := range scenarios {
+		if s.ParentID == nil {
+			continue
+		}
+		parentID, ok := newIDs[*s.ParentID]
+		if !ok {
+			continue // dangling parent of the source
+		}
+		if err := _self.scenarioRepo.UpdateByObjectID(ctx, clones[i].ID, bson.M{"parent_id": parentID}); err != nil {
+			run.rollback(ctx)
+			return nil, err
+		}
+	}
+	_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, target))
+	return run.result, nil
+}
+
+func (_self *CloneUC) CloneScenario(
+	ctx context.Context,
+	sourceFeature, sourceScenario, targetFeature, targetScenario string,
+	opts CloneOptions,
+) (*CloneResult, error) {
+	if sourceFeature == "" || sourceScenario == "" {
+		return nil, fmt.Errorf("%w: source feature and scenario are required", ErrInvalidClone)
+	}
+	if targetFeature == "" {
+		targetFeature = sourceFeature
+	}
+	if targetScenario == "" {
+		targetScenario = opts.NamePrefix + sourceScenario
+	}
+	if targetFeature == sourceFeature && targetScenario == sourceScenario {
+		return nil, fmt.Errorf("%w: a scenario cannot be cloned onto itself, set a target scenario or a name prefix", ErrInvalidClone)
+	}
+
+	scenario, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, sourceFeature, sourceScenario)
+	if err != nil {
+		return nil, err
+	}
+	if scenario == nil || scenario.Name == "" {
+		return nil, fmt.Errorf("%w: scenario %q of feature %q", ErrCloneSourceNotFound, sourceScenario, sourceFeature)
+	}
+	feature, err := _self.featureRepo.FindByName(ctx, targetFeature)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil || feature.Name == "" {
+		return nil, fmt.Errorf("%w: feature %q", ErrCloneSourceNotFound, targetFeature)
+	}
+	existing, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, targetFeature, targetScenario)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Name != "" {
+		return nil, fmt.Errorf("%w: scenario %q of feature %q", ErrCloneConflict, targetScenario, targetFeature)
+	}
+
+	// A copy in the same feature keeps inheriting from the same parent. In
+	// another feature it inherits from the scenario of the same name there.
+	parentID := scenario.ParentID
+	if parentID != nil && targetFeature != sourceFeature {
+		parent, err := _self.scenarioRepo.GetByObjectID(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		targetParent, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, targetFeature, parent.Name)
+		if err != nil {
+			return nil, err
+		}
+		if targetParent == nil || targetParent.Name == "" {
+			return nil, fmt.Errorf("%w: parent scenario %q of feature %q, clone it first", ErrCloneSourceNotFound, parent.Name, targetFeature)
+		}
+		parentID = &targetParent.ID
+	}
+
+	run := &cloneRun{uc: _self, result: &CloneResult{Feature: targetFeature, Scenarios: []string{}}}
+	if _, err := run.scenario(ctx, scenario, targetFeature, targetScenario, parentID, opts.NamePrefix); err != nil {
+		run.rollback(ctx)
+		return nil, err
+	}
+	return run.result, nil
+}
+
+// scenario copies one scenario as feature/name with its mocks, prepending
+// mockPrefix to the mock API names.
+func (_self *cloneRun) scenario(
+	ctx context.Context,
+	source *domain.Scenario,
+	feature, name string,
+	parentID *primitive.ObjectID,
+	mockPrefix string,
+) (*domain.Scenario, error) {
+	uc := _self.uc
+	now := time.Now().UTC()
+	clone := &domain.Scenario{
+		FeatureName: feature,
+		Name:        name,
+		Description: source.Description,
+		ParentID:    parentID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := uc.scenarioRepo.Create(ctx, clone); err != nil {
+		return nil, err
+	}
+	_self.undo = append(_self.undo, func(ctx context.Context) { _ = uc.scenarioRepo.DeleteByObjectID(ctx, clone.ID) })
+	_self.result.Scenarios = append(_self.result.Scenarios, name)
+
+	mocks, err := uc.mockAPIRepo.ListByFeatureAndScenario(ctx, source.FeatureName, source.Name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range mocks {
+		m := mocks[i]
+		m.FeatureName = feature
+		m.ScenarioName = name
+		m.Name = mockPrefix + m.Name
+		m.CreatedAt = now
+		m.UpdatedAt = now
+		if err := uc.mockAPIRepo.Create(ctx, &m); err != nil {
+			return nil, err
+		}
+		id := m.ID
+		_self.undo = append(_self.undo, func(ctx context.Context) { _ = uc.mockAPIRepo.DeletByObjectID(ctx, id) })
+		_self.result.MockAPIs++
+	}
+
+	grpcMocks, err := uc.grpcMockAPIRepo.ListByFeatureAndScenario(ctx, source.FeatureName, source.Name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range grpcMocks {
+		m := grpcMocks[i]
+		m.ID = primitive.NewObjectID()
+		m.FeatureName = feature
+		m.ScenarioName = name
+		m.CreatedAt = now
+		m.UpdatedAt = now
+		// Create would activate the copy of a disabled mock.
+		if err := uc.grpcMockAPIRepo.Replace(ctx, &m); err != nil {
+			return nil, err
+		}
+		id := m.ID
+		_self.undo = append(_self.undo, func(ctx context.Context) { _ = uc.grpcMockAPIRepo.DeleteByID(ctx, id) })
+		_self.result.GRPCMockAPIs++
+	}
+
+	_ = uc.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, feature, name))
+	return clone, nil
+}
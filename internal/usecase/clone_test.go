@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+func newTestCloneUC(t *testing.T) (ICloneUC, *repoMocks) {
+	r := newRepoMocks(t)
+	r.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return NewCloneUC(r.features, r.scenarios, r.mockAPIs, r.grpcMocks, r.cache), r
+}
+
+func TestCloneUC_CloneFeature(t *testing.T) {
+	uc, r := newTestCloneUC(t)
+	ctx := context.Background()
+	base := domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "checkout", Name: "base"}
+	child := domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "checkout", Name: "declined", ParentID: &base.ID}
+
+	r.features.EXPECT().FindByName(gomock.Any(), "checkout").Return(&domain.Feature{Name: "checkout", IsActive: true}, nil)
+	r.features.EXPECT().FindByName(gomock.Any(), "checkout-v2").Return(&domain.Feature{}, nil)
+	r.scenarios.EXPECT().ListByFeatureName(gomock.Any(), "checkout").Return([]domain.Scenario{base, child}, nil)
+	r.features.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feature *domain.Feature) error {
+			assert.Equal(t, "checkout-v2", feature.Name)
+			assert.True(t, feature.IsActive)
+			feature.ID = primitive.NewObjectID()
+			return nil
+		},
+	)
+	created := map[string]*domain.Scenario{}
+	r.scenarios.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, s *domain.Scenario) error {
+			assert.Equal(t, "checkout-v2", s.FeatureName)
+			assert.Nil(t, s.ParentID, "parents are linked after every scenario exists")
+			s.ID = primitive.NewObjectID()
+			created[s.Name] = s
+			return nil
+		},
+	).Times(2)
+	r.mockAPIs.EXPECT().ListByFeatureAndScenario(gomock.Any(), "checkout", "base").
+		Return([]domain.MockAPI{{ID: base.ID, FeatureName: "checkout", ScenarioName: "base", Name: "pay", Path: "/pay", Method: "POST"}}, nil)
+	r.mockAPIs.EXPECT().ListByFeatureAndScenario(gomock.Any(), "checkout", "declined").Return(nil, nil)
+	r.mockAPIs.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, m *domain.MockAPI) error {
+			assert.Equal(t, "checkout-v2", m.FeatureName)
+			assert.Equal(t, "v2-base", m.ScenarioName)
+			assert.Equal(t, "v2-pay", m.Name)
+			assert.Equal(t, "/pay", m.Path)
+			m.ID = primitive.NewObjectID()
+			return nil
+		},
+	)
+	r.grpcMocks.EXPECT().ListByFeatureAndScenario(gomock.Any(), "checkout", "base").Return(nil, nil)
+	grpcID := primitive.NewObjectID()
+	r.grpcMocks.EXPECT().ListByFeatureAndScenario(gomock.Any(), "checkout", "declined").
+		Return([]domain.GRPCMockAPI{{ID: grpcID, FeatureName: "checkout", ScenarioName: "declined", ServiceName: "pay.v1.Pay", IsActive: false}}, nil)
+	r.grpcMocks.EXPECT().Replace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, m *domain.GRPCMockAPI) error {
+			assert.NotEqual(t, grpcID, m.ID)
+			assert.Equal(t, "v2-declined", m.ScenarioName)
+			assert.False(t, m.IsActive, "a disabled mock stays disabled")
+			return nil
+		},
+	)
+	r.scenarios.EXPECT().UpdateByObjectID(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id primitive.ObjectID, update bson.M) error {
+			assert.Equal(t, created["v2-declined"].ID, id)
+			assert.Equal(t, created["v2-base"].ID, update["parent_id"])
+			return nil
+		},
+	)
+
+	result, err := uc.CloneFeature(ctx, "checkout", "checkout-v2", CloneOptions{NamePrefix: "v2-"})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", result.Feature)
+	assert.Equal(t, []string{"v2-base", "v2-declined"}, result.Scenarios)
+	assert.Equal(t, 1, result.MockAPIs)
+	assert.Equal(t, 1, result.GRPCMockAPIs)
+}
+
+func TestCloneUC_CloneFeature_RollsBackOnFailure(t *testing.T) {
+	uc, r := newTestCloneUC(t)
+	ctx := context.Background()
+	featureID := primitive.NewObjectID()
+	scenarioID := primitive.NewObjectID()
+
+	r.features.EXPECT().FindByName(gomock.Any(), "a").Return(&domain.Feature{Name: "a"}, nil)
+	r.features.EXPECT().FindByName(gomock.Any(), "b").Return(&domain.Feature{}, nil)
+	r.scenarios.EXPECT().ListByFeatureName(gomock.Any(), "a").Return([]domain.Scenario{{FeatureName: "a", Name: "s"}}, nil)
+	r.features.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feature *domain.Feature) error { feature.ID = featureID; return nil },
+	)
+	r.scenarios.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, s *domain.Scenario) error { s.ID = scenarioID; return nil },
+	)
+	r.mockAPIs.EXPECT().ListByFeatureAndScenario(gomock.Any(), "a", "s").Return(nil, errors.New("boom"))
+	gomock.InOrder(
+		r.scenarios.EXPECT().DeleteByObjectID(gomock.Any(), scenarioID).Return(nil),
+		r.features.EXPECT().DeleteById(gomock.Any(), featureID).Return(nil),
+	)
+
+	_, err := uc.CloneFeature(ctx, "a", "b", CloneOptions{})
+	assert.EqualError(t, err, "boom")
+}
+
+func TestCloneUC_CloneScenario(t *testing.T) {
+	ctx := context.Background()
+	parentID := primitive.NewObjectID()
+	source := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "checkout", Name: "declined", ParentID: &parentID}
+
+	t.Run("onto itself", func(t *testing.T) {
+		uc, _ := newTestCloneUC(t)
+		_, err := uc.CloneScenario(ctx, "checkout", "declined", "", "", CloneOptions{})
+		assert.True(t, errors.Is(err, ErrInvalidClone))
+	})
+
+	t.Run("name taken", func(t *testing.T) {
+		uc, r := newTestCloneUC(t)
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "checkout", "declined").Return(source, nil)
+		r.features.EXPECT().FindByName(gomock.Any(), "checkout").Return(&domain.Feature{Name: "checkout"}, nil)
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "checkout", "declined-2").
+			Return(&domain.Scenario{Name: "declined-2"}, nil)
+		_, err := uc.CloneScenario(ctx, "checkout", "declined", "", "declined-2", CloneOptions{})
+		assert.True(t, errors.Is(err, ErrCloneConflict))
+	})
+
+	t.Run("into another feature maps the parent by name", func(t *testing.T) {
+		uc, r := newTestCloneUC(t)
+		targetParentID := primitive.NewObjectID()
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "checkout", "declined").Return(source, nil)
+		r.features.EXPECT().FindByName(gomock.Any(), "refund").Return(&domain.Feature{Name: "refund"}, nil)
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "refund", "declined").Return(&domain.Scenario{}, nil)
+		r.scenarios.EXPECT().GetByObjectID(gomock.Any(), parentID).Return(&domain.Scenario{ID: parentID, Name: "base"}, nil)
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "refund", "base").
+			Return(&domain.Scenario{ID: targetParentID, Name: "base"}, nil)
+		r.scenarios.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, s *domain.Scenario) error {
+				assert.Equal(t, "refund", s.FeatureName)
+				assert.Equal(t, "declined", s.Name)
+				require.NotNil(t, s.ParentID)
+				assert.Equal(t, targetParentID, *s.ParentID)
+				return nil
+			},
+		)
+		r.mockAPIs.EXPECT().ListByFeatureAndScenario(gomock.Any(), "checkout", "declined").
+			Return([]domain.MockAPI{{Name: "pay"}, {Name: "refund"}}, nil)
+		r.mockAPIs.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, m *domain.MockAPI) error {
+				assert.Equal(t, "refund", m.FeatureName)
+				assert.Equal(t, "declined", m.ScenarioName)
+				return nil
+			},
+		).Times(2)
+		r.grpcMocks.EXPECT().ListByFeatureAndScenario(gomock.Any(), "checkout", "declined").Return(nil, nil)
+
+		result, err := uc.CloneScenario(ctx, "checkout", "declined", "refund", "", CloneOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"declined"}, result.Scenarios)
+		assert.Equal(t, 2, result.MockAPIs)
+	})
+}
@@ -154,117 +154,158 @@ func (_self *ForwardUC) forward(
 		hash = utils.GenerateHashFromInput(bson.Raw(bodyBytes))
 	}
 
-	// 5. Build cache key
 	acc := ""
 	if accountId != nil {
 		acc = *accountId
 	}
 
-	cacheKey := fmt.Sprintf(
-		repository.KeyMockAPITemplate,
-		featureName,
-		scenarioName,
-		acc,
-		path,
-		method,
-		hash,
-	)
-
-	if cached, err := _self.cacheRepo.Get(ctx, cacheKey); err == nil {
-		observability.MockAPICacheHits.WithLabelValues("hit").Inc()
-		observability.MockAPILookupDuration.Observe(time.Since(start).Seconds())
-		if cached.(string) == notFoundSentinel {
-			return echo.NewHTTPError(http.StatusNotFound, "mock API not found")
-		}
-		var entry entity.CachedEntry
-		if err := json.Unmarshal([]byte(cached.(string)), &entry); err == nil {
-			if entry.Latency > 0 {
-				time.Sleep(time.Duration(entry.Latency) * time.Second)
-			}
-			c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-			sc := http.StatusOK
-			if entry.StatusCode != 0 {
-				sc = entry.StatusCode
-			}
-			c.Response().WriteHeader(sc)
-			_, err := c.Response().Write([]byte(entry.Output))
-			fn := entry.FeatureName
-			sn := entry.ScenarioName
-			if fn == "" {
-				fn = featureName
-			}
-			if sn == "" {
-				sn = scenarioName
-			}
-			_self.stats.Record(fn, sn, path, method, true, float64(time.Since(start).Milliseconds()))
-			return err
-		}
-	}
-
-	// Cache miss: use singleflight to prevent thundering herd.
-	// Use a detached context for the fetch so a cancelled caller does not abort
-	// the shared in-flight request and invalidate results for other waiters.
-	fetchCtx := context.WithoutCancel(ctx)
-	v, err, _ := _self.sfGroup.Do(cacheKey, func() (any, error) {
-		mockAPI, err := _self.MockAPIRepo.FindByFeatureScenarioPathMethodAndHash(
-			fetchCtx,
+	// 5. Look the mock up in the active scenario, then in its parents. Each
+	// scenario only caches its own mocks, so a change to a parent is seen by
+	// its children right away.
+	source := scenario
+	var v any
+	for depth := 0; ; depth++ {
+		cacheKey := fmt.Sprintf(
+			repository.KeyMockAPITemplate,
 			featureName,
-			scenarioName,
+			source.Name,
+			acc,
 			path,
 			method,
 			hash,
 		)
-		if err != nil {
-			// if err == mongo.ErrNoDocuments {
-			// 	// Exact path miss — try pattern matching (e.g. /api/users/:id).
-			// 	mockAPI, err = findByPathPattern(fetchCtx, _self.MockAPIRepo, featureName, scenarioName, path, method, hash)
-			// }
-			// if err != nil {
-			// 	// Negative cache: store sentinel so subsequent waves skip the DB.
-			// 	_self.cacheRepo.SetWithTTL(fetchCtx, cacheKey, notFoundSentinel, notFoundCacheTTL)
-			// 	if err == mongo.ErrNoDocuments {
-			// 		metadata := map[string]string{
-			// 			"x-trace-id": uuid.NewString(),
-			// 		}
-			// 		return nil, errorcustome.NewError(
-			// 			codes.Internal,
-			// 			"ERR.001",
-			// 			"Mock API not found: %s",
-			// 			metadata,
-			// 			"not found",
-			// 		)
-			// 	}
-			// }
-			return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+
+		if cached, err := _self.cacheRepo.Get(ctx, cacheKey); err == nil {
+			if cached.(string) == notFoundSentinel {
+				parent, err := parentScenario(ctx, _self.ScenarioRepo, source, depth)
+				if err != nil {
+					return err
+				}
+				if parent != nil {
+					source = parent
+					continue
+				}
+			}
+			observability.MockAPICacheHits.WithLabelValues("hit").Inc()
+			observability.MockAPILookupDuration.Observe(time.Since(start).Seconds())
+			if cached.(string) == notFoundSentinel {
+				return echo.NewHTTPError(http.StatusNotFound, "mock API not found")
+			}
+			var entry entity.CachedEntry
+			if err := json.Unmarshal([]byte(cached.(string)), &entry); err == nil {
+				if entry.Latency > 0 {
+					time.Sleep(time.Duration(entry.Latency) * time.Second)
+				}
+				c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				sc := http.StatusOK
+				if entry.StatusCode != 0 {
+					sc = entry.StatusCode
+				}
+				c.Response().WriteHeader(sc)
+				_, err := c.Response().Write([]byte(entry.Output))
+				fn := entry.FeatureName
+				sn := entry.ScenarioName
+				if fn == "" {
+					fn = featureName
+				}
+				if sn == "" {
+					sn = scenarioName
+				}
+				_self.stats.Record(fn, sn, path, method, true, float64(time.Since(start).Milliseconds()))
+				return err
+			}
 		}
 
-		if len(mockAPI.Responses) > 0 {
-			return &sfResolved{mockAPI: mockAPI, isSequence: true}, nil
+		// Cache miss: use singleflight to prevent thundering herd.
+		// Use a detached context for the fetch so a cancelled caller does not abort
+		// the shared in-flight request and invalidate results for other waiters.
+		fetchCtx := context.WithoutCancel(ctx)
+		v, err, _ = _self.sfGroup.Do(cacheKey, func() (any, error) {
+			mockAPI, err := _self.MockAPIRepo.FindByFeatureScenarioPathMethodAndHash(
+				fetchCtx,
+				featureName,
+				source.Name,
+				path,
+				method,
+				hash,
+			)
+			if err == mongo.ErrNoDocuments {
+				// Not overridden here: remember it and fall through to the
+				// parent, or answer 404 at the end of the chain.
+				if source.ParentID != nil {
+					_self.cacheRepo.SetWithTTL(fetchCtx, cacheKey, notFoundSentinel, notFoundCacheTTL)
+				}
+				return nil, err
+			}
+			if err != nil {
+				// if err == mongo.ErrNoDocuments {
+				// 	// Exact path miss — try pattern matching (e.g. /api/users/:id).
+				// 	mockAPI, err = findByPathPattern(fetchCtx, _self.MockAPIRepo, featureName, scenarioName, path, method, hash)
+				// }
+				// if err != nil {
+				// 	// Negative cache: store sentinel so subsequent waves skip the DB.
+				// 	_self.cacheRepo.SetWithTTL(fetchCtx, cacheKey, notFoundSentinel, notFoundCacheTTL)
+				// 	if err == mongo.ErrNoDocuments {
+				// 		metadata := map[string]string{
+				// 			"x-trace-id": uuid.NewString(),
+				// 		}
+				// 		return nil, errorcustome.NewError(
+				// 			codes.Internal,
+				// 			"ERR.001",
+				// 			"Mock API not found: %s",
+				// 			metadata,
+				// 			"not found",
+				// 		)
+				// 	}
+				// }
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+
+			if len(mockAPI.Responses) > 0 {
+				return &sfResolved{mockAPI: mockAPI, isSequence: true}, nil
+			}
+			outputBytes, err := rawToJSON(mockAPI.Output)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to parse output")
+			}
+			entry := entity.CachedEntry{
+				Output:       string(outputBytes),
+				Latency:      mockAPI.Latency,
+				StatusCode:   mockAPI.StatusCode,
+				FeatureName:  mockAPI.FeatureName,
+				ScenarioName: mockAPI.ScenarioName,
+			}
+			if entryBytes, err := json.Marshal(entry); err == nil {
+				// Entries served by a time-boxed activation leave the cache with it.
+				if ttl := activationCacheTTL(accountScenario); ttl > 0 {
+					_self.cacheRepo.SetWithTTL(fetchCtx, cacheKey, string(entryBytes), ttl)
+				} else {
+					_self.cacheRepo.Set(fetchCtx, cacheKey, string(entryBytes))
+				}
+			}
+			return &sfResolved{
+				outputBytes: outputBytes,
+				headersRaw:  mockAPI.Headers,
+				latency:     mockAPI.Latency,
+				statusCode:  mockAPI.StatusCode,
+				isSequence:  false,
+			}, nil
+		})
+		if err == mongo.ErrNoDocuments {
+			parent, err := parentScenario(ctx, _self.ScenarioRepo, source, depth)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return echo.NewHTTPError(http.StatusNotFound, "mock API not found")
+			}
+			source = parent
+			continue
 		}
-		outputBytes, err := rawToJSON(mockAPI.Output)
 		if err != nil {
-			return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to parse output")
-		}
-		entry := entity.CachedEntry{
-			Output:       string(outputBytes),
-			Latency:      mockAPI.Latency,
-			StatusCode:   mockAPI.StatusCode,
-			FeatureName:  mockAPI.FeatureName,
-			ScenarioName: mockAPI.ScenarioName,
-		}
-		if entryBytes, err := json.Marshal(entry); err == nil {
-			_self.cacheRepo.Set(fetchCtx, cacheKey, string(entryBytes))
+			return err
 		}
-		return &sfResolved{
-			outputBytes: outputBytes,
-			headersRaw:  mockAPI.Headers,
-			latency:     mockAPI.Latency,
-			statusCode:  mockAPI.StatusCode,
-			isSequence:  false,
-		}, nil
-	})
-	if err != nil {
-		return err
+		break
 	}
 
 	r := v.(*sfResolved)
@@ -278,7 +319,7 @@ func (_self *ForwardUC) forward(
 		seqKey := fmt.Sprintf(
 			repository.KeySequenceTemplate,
 			featureName,
-			scenarioName,
+			source.Name,
 			acc,
 			path,
 			method,
@@ -13,6 +13,7 @@ import (
 	mocks "github.com/namnv2496/mocktool/mocks/repository"
 	repositoryMocks "github.com/namnv2496/mocktool/mocks/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -31,6 +32,7 @@ func TestForwardUC_ResponseMockData(t *testing.T) {
 		setupMocks     func()
 		expectedStatus int
 		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+		checkErr       func(*testing.T, error)
 		wantErr        bool
 	}{
 		{
@@ -105,6 +107,37 @@ func TestForwardUC_ResponseMockData(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "mock not found in the root scenario",
+			setupRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/forward/api/v1/missing", nil)
+				req.Header.Set("X-Account-Id", "test-account")
+				req.Header.Set("X-Feature-Name", "test-feature")
+				return req
+			},
+			setupMocks: func() {
+				scenarioID := primitive.NewObjectID()
+				accountIdPtr := "test-account"
+				accountScenarioRepo.EXPECT().
+					GetActiveScenario(gomock.Any(), "test-feature", &accountIdPtr).
+					Return(&domain.AccountScenario{ScenarioID: scenarioID}, nil)
+				scenarioRepo.EXPECT().
+					GetByObjectID(gomock.Any(), scenarioID).
+					Return(&domain.Scenario{ID: scenarioID, Name: "test-scenario"}, nil)
+				cacheRepo.EXPECT().
+					Get(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("not found"))
+				mockAPIRepo.EXPECT().
+					FindByFeatureScenarioPathMethodAndHash(gomock.Any(), "test-feature", "test-scenario", gomock.Any(), "GET", gomock.Any()).
+					Return(nil, mongo.ErrNoDocuments)
+			},
+			checkErr: func(t *testing.T, err error) {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, http.StatusNotFound, httpErr.Code)
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing X-Account-Id header",
 			setupRequest: func() *http.Request {
@@ -277,6 +310,9 @@ func TestForwardUC_ResponseMockData(t *testing.T) {
 			if tt.checkResponse != nil {
 				tt.checkResponse(t, rec)
 			}
+			if tt.checkErr != nil {
+				tt.checkErr(t, err)
+			}
 		})
 	}
 }
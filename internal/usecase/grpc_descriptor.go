@@ -0,0 +1,449 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+// GRPCDescriptorRegistry keeps the uploaded protobuf descriptors in memory so
+// the gRPC mock server can decode requests and encode responses with the real
+// message types instead of google.protobuf.Struct.
+//
+// The whole collection is loaded lazily on first use and replaced after every
+// upload or delete made through this instance.
+type GRPCDescriptorRegistry struct {
+	repo repository.IGRPCDescriptorRepository
+
+	mu         sync.RWMutex
+	loaded     bool
+	services   map[string]*protoregistry.Files // service name → files declaring it
+	generation uint64                          // bumped on every change
+}
+
+func NewGRPCDescriptorRegistry(repo repository.IGRPCDescriptorRepository) *GRPCDescriptorRegistry {
+	return &GRPCDescriptorRegistry{
+		repo:     repo,
+		services: make(map[string]*protoregistry.Files),
+	}
+}
+
+// CompileProtoFiles compiles .proto sources (file name → content) into a
+// FileDescriptorSet. Imports may reference other uploaded files, the protoc
+// standard imports, or any descriptor linked into this binary (for example
+// google/api/annotations.proto).
+func CompileProtoFiles(ctx context.Context, sources map[string]string) (*descriptorpb.FileDescriptorSet, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no .proto files provided")
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.CompositeResolver{
+			protocompile.WithStandardImports(&protocompile.SourceResolver{
+				Accessor: protocompile.SourceAccessorFromMap(sources),
+			}),
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+				if err != nil {
+					return protocompile.SearchResult{}, err
+				}
+				return protocompile.SearchResult{Desc: fd}, nil
+			}),
+		},
+	}
+	compiled, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, fmt.Errorf("compile proto: %w", err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	for _, fd := range compiled {
+		appendFileWithImports(set, fd, seen)
+	}
+	return set, nil
+}
+
+// appendFileWithImports adds fd to set after all of its transitive imports so
+// the resulting set can be passed straight to protodesc.NewFiles.
+func appendFileWithImports(set *descriptorpb.FileDescriptorSet, fd protoreflect.FileDescriptor, seen map[string]bool) {
+	if seen[fd.Path()] {
+		return
+	}
+	seen[fd.Path()] = true
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		appendFileWithImports(set, imports.Get(i).FileDescriptor, seen)
+	}
+	set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+}
+
+// Register validates a FileDescriptorSet and stores it once for every service
+// it declares. It returns the full names of the registered services.
+func (_self *GRPCDescriptorRegistry) Register(ctx context.Context, set *descriptorpb.FileDescriptorSet, source string) ([]string, error) {
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	raw, err := proto.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	fileNames := make([]string, 0, len(set.File))
+	for _, f := range set.File {
+		fileNames = append(fileNames, f.GetName())
+	}
+
+	var serviceNames []string
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		svcs := fd.Services()
+		for i := 0; i < svcs.Len(); i++ {
+			serviceNames = append(serviceNames, string(svcs.Get(i).FullName()))
+		}
+		return true
+	})
+	if len(serviceNames) == 0 {
+		return nil, fmt.Errorf("descriptor set does not declare any service")
+	}
+	sort.Strings(serviceNames)
+
+	for _, svc := range serviceNames {
+		if err := _self.repo.Upsert(ctx, &domain.GRPCDescriptor{
+			ServiceName:   svc,
+			Source:        source,
+			Files:         fileNames,
+			DescriptorSet: raw,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	_self.mu.Lock()
+	for _, svc := range serviceNames {
+		_self.services[svc] = files
+	}
+	_self.generation++
+	_self.mu.Unlock()
+	return serviceNames, nil
+}
+
+// Delete removes the descriptor of one service; its methods fall back to the
+// untyped google.protobuf.Struct behaviour.
+func (_self *GRPCDescriptorRegistry) Delete(ctx context.Context, serviceName string) error {
+	if err := _self.repo.DeleteByServiceName(ctx, serviceName); err != nil {
+		return err
+	}
+	_self.mu.Lock()
+	delete(_self.services, serviceName)
+	_self.generation++
+	_self.mu.Unlock()
+	return nil
+}
+
+// List returns the stored descriptor documents.
+func (_self *GRPCDescriptorRegistry) List(ctx context.Context) ([]domain.GRPCDescriptor, error) {
+	return _self.repo.ListAll(ctx)
+}
+
+// Reload replaces the in-memory index with the content of the database.
+func (_self *GRPCDescriptorRegistry) Reload(ctx context.Context) error {
+	docs, err := _self.repo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	services := make(map[string]*protoregistry.Files, len(docs))
+	for _, d := range docs {
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(d.DescriptorSet, &set); err != nil {
+			slog.Warn("skip invalid gRPC descriptor", "service", d.ServiceName, "error", err)
+			continue
+		}
+		files, err := protodesc.NewFiles(&set)
+		if err != nil {
+			slog.Warn("skip invalid gRPC descriptor", "service", d.ServiceName, "error", err)
+			continue
+		}
+		services[d.ServiceName] = files
+	}
+	_self.mu.Lock()
+	_self.services = services
+	_self.loaded = true
+	_self.generation++
+	_self.mu.Unlock()
+	return nil
+}
+
+func (_self *GRPCDescriptorRegistry) ensureLoaded() {
+	if _self == nil {
+		return
+	}
+	_self.mu.RLock()
+	loaded := _self.loaded
+	_self.mu.RUnlock()
+	if loaded {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := _self.Reload(ctx); err != nil {
+		slog.Warn("load gRPC descriptors", "error", err)
+	}
+}
+
+// Generation changes whenever descriptors are registered, deleted or
+// reloaded, so callers can cache what they derive from them.
+func (_self *GRPCDescriptorRegistry) Generation() uint64 {
+	if _self == nil {
+		return 0
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	return _self.generation
+}
+
+// ServiceNames returns every service that has an uploaded descriptor.
+func (_self *GRPCDescriptorRegistry) ServiceNames() []string {
+	if _self == nil {
+		return nil
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	out := make([]string, 0, len(_self.services))
+	for name := range _self.services {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// FindService returns the descriptor of an uploaded service, or nil.
+func (_self *GRPCDescriptorRegistry) FindService(serviceName string) protoreflect.ServiceDescriptor {
+	if _self == nil {
+		return nil
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	files := _self.services[serviceName]
+	_self.mu.RUnlock()
+	if files == nil {
+		return nil
+	}
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil
+	}
+	svc, _ := desc.(protoreflect.ServiceDescriptor)
+	return svc
+}
+
+// FindMethod resolves a gRPC full method name (/pkg.Service/Method) to its
+// descriptor, or nil when the service has no uploaded descriptor.
+func (_self *GRPCDescriptorRegistry) FindMethod(fullMethod string) protoreflect.MethodDescriptor {
+	serviceName, methodName := utils.SplitGRPCMethod(fullMethod)
+	svc := _self.FindService(serviceName)
+	if svc == nil {
+		return nil
+	}
+	return svc.Methods().ByName(protoreflect.Name(methodName))
+}
+
+// FindFileByPath searches the uploaded descriptor sets for a file.
+func (_self *GRPCDescriptorRegistry) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if _self == nil {
+		return nil, protoregistry.NotFound
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	for _, files := range _self.services {
+		if fd, err := files.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+// FindDescriptorByName searches the uploaded descriptor sets for any named
+// element (service, message, enum, ...).
+func (_self *GRPCDescriptorRegistry) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if _self == nil {
+		return nil, protoregistry.NotFound
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	for _, files := range _self.services {
+		if desc, err := files.FindDescriptorByName(name); err == nil {
+			return desc, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+// HashTypedInputs rehashes the input and bidi rule inputs of a mock of a typed
+// method from their canonical form. The admin JSON is parsed into the request
+// message and rendered like a decoded call, so {"id": 5} matches a call with
+// an int64 id of 5 and {"flag": false} matches a call without flag. Mocks of
+// methods without a descriptor keep their hashes. It reports whether a hash
+// changed.
+func (_self *GRPCDescriptorRegistry) HashTypedInputs(m *domain.GRPCMockAPI) (bool, error) {
+	method := _self.FindMethod("/" + m.ServiceName + "/" + m.MethodName)
+	if method == nil {
+		return false, nil
+	}
+	return hashTypedInputs(method, m)
+}
+
+func hashTypedInputs(method protoreflect.MethodDescriptor, m *domain.GRPCMockAPI) (bool, error) {
+	changed := false
+	rehash := func(input bson.Raw, aggregate string, hash *string) error {
+		if len(input) == 0 {
+			return nil
+		}
+		canonical, err := canonicalTypedInput(method.Input(), input, aggregate)
+		if err != nil {
+			return err
+		}
+		if h := hashTypedRequest(canonical); h != *hash {
+			*hash, changed = h, true
+		}
+		return nil
+	}
+
+	aggregate := ""
+	if m.StreamType == domain.GRPCStreamClient {
+		aggregate = m.Aggregate
+	}
+	if err := rehash(m.Input, aggregate, &m.HashInput); err != nil {
+		return false, fmt.Errorf("input: %w", err)
+	}
+	for i := range m.BidiRules {
+		if err := rehash(m.BidiRules[i].Input, "", &m.BidiRules[i].HashInput); err != nil {
+			return false, fmt.Errorf("bidi_rules[%d].input: %w", i, err)
+		}
+	}
+	return changed, nil
+}
+
+// canonicalTypedInput renders a stored input the way calls are decoded. A
+// client-stream input is compared with the aggregate of the messages, so with
+// "all" every message is rendered and "count" is left as is.
+func canonicalTypedInput(desc protoreflect.MessageDescriptor, input bson.Raw, aggregate string) (map[string]any, error) {
+	jsonBytes, err := bson.MarshalExtJSON(input, false, false)
+	if err != nil {
+		return nil, err
+	}
+	switch aggregate {
+	case domain.GRPCAggregateCount:
+		out := map[string]any{}
+		if err := json.Unmarshal(jsonBytes, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	case domain.GRPCAggregateAll:
+		var in struct {
+			Messages []json.RawMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(jsonBytes, &in); err != nil {
+			return nil, err
+		}
+		all := make([]any, 0, len(in.Messages))
+		for _, raw := range in.Messages {
+			msg, err := canonicalTypedMessage(desc, raw)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, msg)
+		}
+		return map[string]any{"messages": all}, nil
+	}
+	return canonicalTypedMessage(desc, jsonBytes)
+}
+
+func canonicalTypedMessage(desc protoreflect.MessageDescriptor, jsonBytes []byte) (map[string]any, error) {
+	msg := dynamicpb.NewMessage(desc)
+	if err := protojson.Unmarshal(jsonBytes, msg); err != nil {
+		return nil, fmt.Errorf("does not match %s: %w", desc.FullName(), err)
+	}
+	return typedMessageToMap(msg)
+}
+
+// decodeTypedRequest decodes wire bytes of a typed request into the canonical
+// protobuf JSON mapping (original field names, 64-bit integers as strings,
+// default values dropped).
+func decodeTypedRequest(desc protoreflect.MessageDescriptor, b []byte) (map[string]any, error) {
+	msg := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return typedMessageToMap(msg)
+}
+
+func typedMessageToMap(msg proto.Message) (map[string]any, error) {
+	jsonBytes, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]any{}
+	if err := json.Unmarshal(jsonBytes, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// hashTypedRequest hashes a decoded typed request like the admin API hashes a
+// stored input (JSON → BSON → utils.GenerateHashFromInput). Stored inputs of
+// typed methods are first made canonical by HashTypedInputs, since protojson
+// renders 64-bit integers as strings and drops default values.
+func hashTypedRequest(m map[string]any) string {
+	if len(m) == 0 {
+		return ""
+	}
+	raw, err := bson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return utils.GenerateHashFromInput(raw)
+}
+
+// encodeTypedResponse converts a stored BSON output into the real response
+// message. Unknown fields are dropped so a partial mock still encodes.
+func encodeTypedResponse(desc protoreflect.MessageDescriptor, raw bson.Raw) (proto.Message, error) {
+	msg := dynamicpb.NewMessage(desc)
+	if len(raw) == 0 {
+		return msg, nil
+	}
+	jsonBytes, err := bson.MarshalExtJSON(raw, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(jsonBytes, msg); err != nil {
+		return nil, fmt.Errorf("output does not match %s: %w", desc.FullName(), err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const testUserProto = `
+syntax = "proto3";
+package demo.v1;
+
+import "google/protobuf/timestamp.proto";
+
+message GetUserRequest {
+  string user_id = 1;
+  int32 page = 2;
+  int64 account_id = 3;
+  bool active = 4;
+}
+
+message GetUserResponse {
+  string name = 1;
+  int64 balance = 2;
+  google.protobuf.Timestamp created_at = 3;
+}
+
+service UserService {
+  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+}
+`
+
+func compileTestService(t *testing.T) protoreflect.ServiceDescriptor {
+	t.Helper()
+	set, err := CompileProtoFiles(context.Background(), map[string]string{"demo/v1/user.proto": testUserProto})
+	assert.NoError(t, err)
+	assert.Equal(t, "google/protobuf/timestamp.proto", set.File[0].GetName(), "imports come first")
+
+	files, err := protodesc.NewFiles(set)
+	assert.NoError(t, err)
+	desc, err := files.FindDescriptorByName("demo.v1.UserService")
+	assert.NoError(t, err)
+	return desc.(protoreflect.ServiceDescriptor)
+}
+
+func TestCompileProtoFiles_Invalid(t *testing.T) {
+	_, err := CompileProtoFiles(context.Background(), map[string]string{"bad.proto": "syntax = \"proto3\"; message {"})
+	assert.Error(t, err)
+
+	_, err = CompileProtoFiles(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestTypedRequestHash_MatchesAdminHash(t *testing.T) {
+	method := compileTestService(t).Methods().ByName("GetUser")
+
+	req := dynamicpb.NewMessage(method.Input())
+	req.Set(method.Input().Fields().ByName("user_id"), protoreflect.ValueOfString("u-1"))
+	req.Set(method.Input().Fields().ByName("page"), protoreflect.ValueOfInt32(2))
+	wire, err := proto.Marshal(req)
+	assert.NoError(t, err)
+
+	decoded, err := decodeTypedRequest(method.Input(), wire)
+	assert.NoError(t, err)
+
+	// Same path the admin API uses for the stored input.
+	var input any
+	assert.NoError(t, json.Unmarshal([]byte(`{"page": 2, "user_id": "u-1"}`), &input))
+	raw, err := bson.Marshal(input)
+	assert.NoError(t, err)
+
+	assert.Equal(t, utils.GenerateHashFromInput(raw), hashTypedRequest(decoded))
+}
+
+func TestHashTypedInputs_CanonicalInput(t *testing.T) {
+	method := compileTestService(t).Methods().ByName("GetUser")
+
+	req := dynamicpb.NewMessage(method.Input())
+	req.Set(method.Input().Fields().ByName("account_id"), protoreflect.ValueOfInt64(5))
+	wire, err := proto.Marshal(req)
+	assert.NoError(t, err)
+	decoded, err := decodeTypedRequest(method.Input(), wire)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input bson.M
+	}{
+		{"int64 as number", bson.M{"account_id": 5}},
+		{"int64 as JSON number", bson.M{"account_id": float64(5)}},
+		{"int64 as string", bson.M{"account_id": "5"}},
+		{"zero values", bson.M{"account_id": 5, "active": false, "page": 0, "user_id": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.input)
+			assert.NoError(t, err)
+			m := &domain.GRPCMockAPI{Input: raw, HashInput: utils.HashInputConsistent(raw)}
+
+			_, err = hashTypedInputs(method, m)
+			assert.NoError(t, err)
+			assert.Equal(t, hashTypedRequest(decoded), m.HashInput)
+		})
+	}
+
+	m := &domain.GRPCMockAPI{Input: mustBSON(t, bson.M{"active": false})}
+	_, err = hashTypedInputs(method, m)
+	assert.NoError(t, err)
+	assert.Empty(t, m.HashInput, "an input of default values matches like an empty one")
+
+	m = &domain.GRPCMockAPI{Input: mustBSON(t, bson.M{"account_id": "five"})}
+	_, err = hashTypedInputs(method, m)
+	assert.Error(t, err)
+}
+
+func mustBSON(t *testing.T, v any) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(v)
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestDecodeTypedRequest_InvalidBytes(t *testing.T) {
+	method := compileTestService(t).Methods().ByName("GetUser")
+
+	_, err := decodeTypedRequest(method.Input(), []byte{0xff, 0xff, 0xff})
+	assert.Error(t, err)
+}
+
+func TestEncodeTypedResponse(t *testing.T) {
+	method := compileTestService(t).Methods().ByName("GetUser")
+
+	raw, err := bson.Marshal(bson.M{
+		"name":       "Alice",
+		"balance":    "1500",
+		"created_at": "2024-01-02T03:04:05Z",
+		"unknown":    true,
+	})
+	assert.NoError(t, err)
+
+	msg, err := encodeTypedResponse(method.Output(), raw)
+	assert.NoError(t, err)
+
+	m := msg.ProtoReflect()
+	fields := method.Output().Fields()
+	assert.Equal(t, "Alice", m.Get(fields.ByName("name")).String())
+	assert.Equal(t, int64(1500), m.Get(fields.ByName("balance")).Int())
+	assert.True(t, m.Has(fields.ByName("created_at")))
+
+	bad, err := bson.Marshal(bson.M{"name": 42})
+	assert.NoError(t, err)
+	_, err = encodeTypedResponse(method.Output(), bad)
+	assert.Error(t, err)
+}
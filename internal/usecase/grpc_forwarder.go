@@ -5,39 +5,74 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"strings"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"github.com/namnv2496/mocktool/internal/domain"
 	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/pkg/observability"
+	"github.com/namnv2496/mocktool/pkg/utils"
 )
 
+// grpcStatsMethod is the method recorded in StatsStore for gRPC calls; the
+// path is the full method name (/package.Service/Method).
+const grpcStatsMethod = "GRPC"
+
+// ErrNoGRPCMock matches (errors.Is) the HandleCall errors of calls no mock
+// answered: no active scenario, no matching mock or a failed lookup. Errors
+// without it come from the status of a mock.
+var ErrNoGRPCMock = errors.New("no gRPC mock answered the call")
+
+// noGRPCMockError keeps the gRPC status of err while matching ErrNoGRPCMock.
+type noGRPCMockError struct{ err error }
+
+func (e noGRPCMockError) Error() string              { return e.err.Error() }
+func (e noGRPCMockError) GRPCStatus() *status.Status { return status.Convert(e.err) }
+func (e noGRPCMockError) Is(target error) bool       { return target == ErrNoGRPCMock }
+
 //go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
 type IGRPCForwardUC interface {
-	HandleCall(ctx context.Context, fullMethod string, reqBytes []byte, featureName string, scenario string) (*structpb.Struct, codes.Code, error)
+	HandleCall(ctx context.Context, fullMethod string, reqBytes []byte, featureName string, accountId *string, scenarioOverride string) (proto.Message, codes.Code, error)
+	HandleStream(ctx context.Context, fullMethod string, stream GRPCStream, featureName string, accountId *string, scenarioOverride string) (codes.Code, error)
 }
 
 type GRPCForwardUC struct {
 	grpcMockRepo        repository.IGRPCMockAPIRepository
 	scenarioRepo        repository.IScenarioRepository
 	accountScenarioRepo repository.IAccountScenarioRepository
+	descriptors         *GRPCDescriptorRegistry
+	serviceIndex        *GRPCServiceIndex
+	cacheRepo           repository.ICache
+	stats               *StatsStore
+	sfGroup             singleflight.Group
 }
 
 func NewGRPCForwardUC(
 	grpcMockRepo repository.IGRPCMockAPIRepository,
 	scenarioRepo repository.IScenarioRepository,
 	accountScenarioRepo repository.IAccountScenarioRepository,
+	descriptors *GRPCDescriptorRegistry,
+	serviceIndex *GRPCServiceIndex,
+	cacheRepo repository.ICache,
+	stats *StatsStore,
 ) IGRPCForwardUC {
 	return &GRPCForwardUC{
 		grpcMockRepo:        grpcMockRepo,
 		scenarioRepo:        scenarioRepo,
 		accountScenarioRepo: accountScenarioRepo,
+		descriptors:         descriptors,
+		serviceIndex:        serviceIndex,
+		cacheRepo:           cacheRepo,
+		stats:               stats,
 	}
 }
 
@@ -46,57 +81,291 @@ func (_self *GRPCForwardUC) HandleCall(
 	fullMethod string,
 	reqBytes []byte,
 	featureName string,
-	scenario string,
-) (*structpb.Struct, codes.Code, error) {
-	serviceName, methodName := splitFullMethod(fullMethod)
+	accountId *string,
+	scenarioOverride string,
+) (proto.Message, codes.Code, error) {
+	scenario, grpcCode, err := _self.resolveScenario(ctx, featureName, accountId, scenarioOverride)
+	if err != nil {
+		return nil, grpcCode, noGRPCMockError{err}
+	}
+	result, mock, grpcCode, err := _self.handleUnary(ctx, fullMethod, reqBytes, featureName, accountId, scenario)
+	if err != nil && mock == nil {
+		err = noGRPCMockError{err}
+	}
+	return result, grpcCode, err
+}
+
+func (_self *GRPCForwardUC) handleUnary(
+	ctx context.Context,
+	fullMethod string,
+	reqBytes []byte,
+	featureName string,
+	accountId *string,
+	scenario *domain.Scenario,
+) (proto.Message, *domain.GRPCMockAPI, codes.Code, error) {
+	serviceName, methodName := utils.SplitGRPCMethod(fullMethod)
 
 	// Hash request for body-based matching.
-	// Decode proto bytes as Struct → canonical JSON → sha256 so the hash
+	// With an uploaded descriptor the bytes are decoded as the real input type;
+	// otherwise they are decoded as Struct → canonical JSON → sha256 so the hash
 	// matches what the admin UI stores via utils.GenerateHashFromInput.
-	hashInput := hashStructProto(reqBytes)
+	// The decoded request is kept for field matchers.
+	method := _self.descriptors.FindMethod(fullMethod)
+	var hashInput string
+	var req map[string]any
+	if method != nil {
+		decoded, err := decodeTypedRequest(method.Input(), reqBytes)
+		if err != nil {
+			return nil, nil, codes.InvalidArgument, status.Errorf(codes.InvalidArgument, "decode %s: %v", method.Input().FullName(), err)
+		}
+		req = decoded
+		hashInput = hashTypedRequest(decoded)
+	} else {
+		req, _ = decodeRequest(nil, reqBytes)
+		hashInput = hashStructProto(reqBytes)
+	}
 
-	// Exact match first; fall back to match-all (empty hash) if needed
-	mock, err := _self.grpcMockRepo.FindByFeatureScenarioServiceMethodAndHash(
-		ctx, featureName, scenario, serviceName, methodName, hashInput,
-	)
-	if err == mongo.ErrNoDocuments && hashInput != "" {
-		mock, err = _self.grpcMockRepo.FindByFeatureScenarioServiceMethodAndHash(
-			ctx, featureName, scenario, serviceName, methodName, "",
-		)
+	start := time.Now()
+	mock, source, cacheHit, err := _self.findInheritedMock(ctx, featureName, scenario, serviceName, methodName, hashInput, req)
+	if cacheHit {
+		observability.MockAPICacheHits.WithLabelValues("hit").Inc()
+	} else {
+		observability.MockAPICacheHits.WithLabelValues("miss").Inc()
 	}
+	observability.MockAPILookupDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, codes.NotFound, status.Errorf(codes.NotFound, "mock not found for %s/%s", serviceName, methodName)
+			observability.SetGRPCMatch(ctx, observability.GRPCMatchNotFound)
+			return nil, nil, codes.NotFound, status.Errorf(codes.NotFound, "mock not found for %s/%s", serviceName, methodName)
+		}
+		return nil, nil, codes.Internal, status.Errorf(codes.Internal, "lookup: %v", err)
+	}
+	observability.SetGRPCMatch(ctx, observability.GRPCMatchMock)
+	defer func() {
+		_self.stats.Record(featureName, scenario.Name, fullMethod, grpcStatsMethod, cacheHit, float64(time.Since(start).Milliseconds()))
+	}()
+
+	if len(mock.Sequence) > 0 {
+		acc := ""
+		if accountId != nil {
+			acc = *accountId
 		}
-		return nil, codes.Internal, status.Errorf(codes.Internal, "lookup: %v", err)
+		seqKey := fmt.Sprintf(
+			repository.KeyGRPCSequenceTemplate,
+			featureName,
+			source,
+			acc,
+			serviceName,
+			methodName,
+			hashInput,
+		)
+		count, err := _self.cacheRepo.IncrWithTTL(ctx, seqKey, sequenceCounterTTL)
+		if err != nil {
+			return nil, nil, codes.Internal, status.Errorf(codes.Internal, "increment sequence counter: %v", err)
+		}
+		mock = applyGRPCSequence(mock, int(count))
 	}
 
-	if mock.Latency > 0 {
-		time.Sleep(time.Duration(mock.Latency) * time.Millisecond)
+	// The wait follows the call context: a client that gives up or whose
+	// deadline passes ends the call instead of the latency running out.
+	if err := waitMockLatency(ctx, mock); err != nil {
+		return nil, nil, status.Code(err), err
+	}
+
+	if grpcCode, err := _self.mockStatus(mock); err != nil {
+		return nil, mock, grpcCode, err
+	}
+
+	// Transcode bson.Raw → real output type when a descriptor is known,
+	// otherwise bson.Raw → map → structpb.Struct
+	var result proto.Message
+	if method != nil {
+		result, err = encodeTypedResponse(method.Output(), mock.Output)
+	} else {
+		result, err = bsonRawToStruct(mock.Output)
+	}
+	if err != nil {
+		return nil, mock, codes.Internal, status.Errorf(codes.Internal, "transcode output: %v", err)
 	}
 
-	// Transcode bson.Raw → map → structpb.Struct
-	result, err := bsonRawToStruct(mock.Output)
+	return result, mock, codes.OK, nil
+}
+
+// findInheritedMock looks the mock up in scenario, then in its parents until
+// one of them has a mock for the call. It also returns the name of the
+// scenario holding the mock.
+func (_self *GRPCForwardUC) findInheritedMock(
+	ctx context.Context,
+	featureName string,
+	scenario *domain.Scenario,
+	serviceName, methodName, hashInput string,
+	req map[string]any,
+) (*domain.GRPCMockAPI, string, bool, error) {
+	mock, cacheHit, err := _self.findMock(ctx, featureName, scenario.Name, serviceName, methodName, hashInput, req)
+	if err != mongo.ErrNoDocuments {
+		return mock, scenario.Name, cacheHit, err
+	}
+	current, err := _self.loadScenario(ctx, featureName, scenario)
+	for depth := 0; err == nil; depth++ {
+		if current, err = parentScenario(ctx, _self.scenarioRepo, current, depth); err != nil || current == nil {
+			break
+		}
+		mock, cacheHit, err = _self.findMock(ctx, featureName, current.Name, serviceName, methodName, hashInput, req)
+		if err != mongo.ErrNoDocuments {
+			return mock, current.Name, cacheHit, err
+		}
+		err = nil
+	}
 	if err != nil {
-		return nil, codes.Internal, status.Errorf(codes.Internal, "transcode output: %v", err)
+		return nil, "", false, err
 	}
+	return nil, "", cacheHit, mongo.ErrNoDocuments
+}
+
+// listInheritedMocks returns the active mocks of the method in the nearest
+// scenario of the chain that has any.
+func (_self *GRPCForwardUC) listInheritedMocks(
+	ctx context.Context,
+	featureName string,
+	scenario *domain.Scenario,
+	serviceName, methodName string,
+) ([]domain.GRPCMockAPI, error) {
+	mocks, err := _self.grpcMockRepo.ListActiveByMethod(ctx, featureName, scenario.Name, serviceName, methodName)
+	if err != nil || len(mocks) > 0 {
+		return mocks, err
+	}
+	current, err := _self.loadScenario(ctx, featureName, scenario)
+	for depth := 0; err == nil; depth++ {
+		if current, err = parentScenario(ctx, _self.scenarioRepo, current, depth); err != nil || current == nil {
+			break
+		}
+		if mocks, err = _self.grpcMockRepo.ListActiveByMethod(ctx, featureName, current.Name, serviceName, methodName); err != nil || len(mocks) > 0 {
+			return mocks, err
+		}
+	}
+	return nil, err
+}
 
-	grpcCode := codes.Code(mock.StatusCode)
-	if grpcCode != codes.OK {
-		return nil, grpcCode, status.Error(grpcCode, "mock status")
+// loadScenario returns scenario with its parent, loading it by name when
+// it only holds the name of an x-scenario override.
+func (_self *GRPCForwardUC) loadScenario(ctx context.Context, featureName string, scenario *domain.Scenario) (*domain.Scenario, error) {
+	if !scenario.ID.IsZero() {
+		return scenario, nil
+	}
+	return _self.scenarioRepo.FindByFeatureNameAndName(ctx, featureName, scenario.Name)
+}
+
+// findMock looks the mock up by exact hash, then by field matchers and
+// finally falls back to the match-all mock. Hits and misses are cached in
+// Redis, and concurrent lookups of the same key share one Mongo round trip.
+// The hash covers the whole request, so it also determines the matcher result.
+func (_self *GRPCForwardUC) findMock(
+	ctx context.Context,
+	featureName, scenario, serviceName, methodName, hashInput string,
+	req map[string]any,
+) (*domain.GRPCMockAPI, bool, error) {
+	cacheKey := fmt.Sprintf(
+		repository.KeyGRPCMockTemplate,
+		featureName,
+		scenario,
+		serviceName,
+		methodName,
+		hashInput,
+	)
+	if cached, err := _self.cacheRepo.Get(ctx, cacheKey); err == nil {
+		if data, ok := cached.(string); ok {
+			if data == notFoundSentinel {
+				return nil, true, mongo.ErrNoDocuments
+			}
+			var mock domain.GRPCMockAPI
+			if err := bson.UnmarshalExtJSON([]byte(data), true, &mock); err == nil {
+				return &mock, true, nil
+			}
+		}
+	}
+
+	// Detached context so a cancelled caller does not fail the other waiters.
+	fetchCtx := context.WithoutCancel(ctx)
+	v, err, _ := _self.sfGroup.Do(cacheKey, func() (any, error) {
+		// Exact hash is the fast path
+		var mock *domain.GRPCMockAPI
+		err := mongo.ErrNoDocuments
+		if hashInput != "" {
+			mock, err = _self.grpcMockRepo.FindByFeatureScenarioServiceMethodAndHash(
+				fetchCtx, featureName, scenario, serviceName, methodName, hashInput,
+			)
+		}
+		if err == mongo.ErrNoDocuments {
+			candidates, listErr := _self.grpcMockRepo.ListActiveByMethod(fetchCtx, featureName, scenario, serviceName, methodName)
+			if listErr != nil {
+				return nil, listErr
+			}
+			if picked := pickGRPCMock(candidates, "", req); picked != nil {
+				mock, err = picked, nil
+			}
+		}
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				_self.cacheRepo.SetWithTTL(fetchCtx, cacheKey, notFoundSentinel, notFoundCacheTTL)
+			}
+			return nil, err
+		}
+		if data, err := bson.MarshalExtJSON(mock, true, false); err == nil {
+			_self.cacheRepo.Set(fetchCtx, cacheKey, string(data))
+		}
+		return mock, nil
+	})
+	if err != nil {
+		return nil, false, err
 	}
+	return v.(*domain.GRPCMockAPI), false, nil
+}
 
-	return result, codes.OK, nil
+// resolveScenario returns the scenario to serve. An explicit x-scenario
+// override wins and only carries the name; otherwise the active scenario is
+// resolved exactly like the HTTP forwarder does (account-specific mapping
+// first, then global).
+func (_self *GRPCForwardUC) resolveScenario(
+	ctx context.Context,
+	featureName string,
+	accountId *string,
+	scenarioOverride string,
+) (*domain.Scenario, codes.Code, error) {
+	if scenarioOverride != "" {
+		return &domain.Scenario{FeatureName: featureName, Name: scenarioOverride}, codes.OK, nil
+	}
+	accountScenario, err := _self.accountScenarioRepo.GetActiveScenario(ctx, featureName, accountId)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, codes.FailedPrecondition, status.Errorf(codes.FailedPrecondition, "no active scenario for feature %s", featureName)
+		}
+		return nil, codes.Internal, status.Errorf(codes.Internal, "resolve scenario: %v", err)
+	}
+	scenario, err := _self.scenarioRepo.GetByObjectID(ctx, accountScenario.ScenarioID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, codes.FailedPrecondition, status.Errorf(codes.FailedPrecondition, "active scenario of feature %s no longer exists", featureName)
+		}
+		return nil, codes.Internal, status.Errorf(codes.Internal, "resolve scenario: %v", err)
+	}
+	return scenario, codes.OK, nil
 }
 
-func splitFullMethod(fullMethod string) (serviceName, methodName string) {
-	// fullMethod format: /package.ServiceName/MethodName
-	trimmed := strings.TrimPrefix(fullMethod, "/")
-	idx := strings.LastIndex(trimmed, "/")
-	if idx < 0 {
-		return trimmed, ""
+// applyGRPCSequence returns a copy of mock with the response of the sequence
+// step matching count, or mock itself when no step matches.
+func applyGRPCSequence(mock *domain.GRPCMockAPI, count int) *domain.GRPCMockAPI {
+	for i := range mock.Sequence {
+		step := &mock.Sequence[i]
+		if count >= step.From && (step.To == 0 || count < step.To) {
+			resolved := *mock
+			resolved.Output = step.Output
+			resolved.StatusCode = step.StatusCode
+			resolved.StatusMessage = step.StatusMessage
+			resolved.Latency = step.Latency
+			return &resolved
+		}
 	}
-	return trimmed[:idx], trimmed[idx+1:]
+	return mock
 }
 
 // hashStructProto decodes proto bytes as google.protobuf.Struct, serialises the
@@ -1,162 +1,422 @@
 package usecase
 
-// import (
-// 	"context"
-// 	"testing"
-
-// 	"github.com/stretchr/testify/assert"
-// 	"github.com/stretchr/testify/require"
-// 	"go.mongodb.org/mongo-driver/bson"
-// 	"go.mongodb.org/mongo-driver/bson/primitive"
-// 	"go.mongodb.org/mongo-driver/mongo"
-// 	"go.uber.org/mock/gomock"
-// 	"google.golang.org/grpc/codes"
-
-// 	"github.com/namnv2496/mocktool/internal/domain"
-// 	mockrepo "github.com/namnv2496/mocktool/mocks/repository"
-// )
-
-// func newGRPCForwardUC(t *testing.T) (
-// 	IGRPCForwardUC,
-// 	*mockrepo.MockIGRPCMockAPIRepository,
-// 	*mockrepo.MockIScenarioRepository,
-// 	*mockrepo.MockIAccountScenarioRepository,
-// ) {
-// 	ctrl := gomock.NewController(t)
-// 	grpcRepo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
-// 	scenarioRepo := mockrepo.NewMockIScenarioRepository(ctrl)
-// 	accountScenarioRepo := mockrepo.NewMockIAccountScenarioRepository(ctrl)
-// 	uc := NewGRPCForwardUC(grpcRepo, scenarioRepo, accountScenarioRepo)
-// 	return uc, grpcRepo, scenarioRepo, accountScenarioRepo
-// }
-
-// var (
-// 	testScenarioID  = primitive.NewObjectID()
-// 	testAccountID   = "acc-1"
-// 	testFeatureName = "my-feature"
-// 	testScenarioObj = &domain.Scenario{Name: "scenario-1"}
-// )
-
-// func setupScenarioMocks(
-// 	accountScenarioRepo *mockrepo.MockIAccountScenarioRepository,
-// 	scenarioRepo *mockrepo.MockIScenarioRepository,
-// ) {
-// 	accountScenarioRepo.EXPECT().
-// 		GetActiveScenario(gomock.Any(), testFeatureName, gomock.Any()).
-// 		Return(&domain.AccountScenario{ScenarioID: testScenarioID}, nil)
-// 	scenarioRepo.EXPECT().
-// 		GetByObjectID(gomock.Any(), testScenarioID).
-// 		Return(testScenarioObj, nil)
-// }
-
-// func outputBSON(t *testing.T, m map[string]any) bson.Raw {
-// 	t.Helper()
-// 	raw, err := bson.Marshal(m)
-// 	require.NoError(t, err)
-// 	return raw
-// }
-
-// func TestHandleCall_HitExactHash(t *testing.T) {
-// 	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
-// 	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
-
-// 	reqBytes := []byte(`{"id":1}`)
-// 	expectedHash := hashBytes(reqBytes)
-// 	mock := &domain.GRPCMockAPI{
-// 		ServiceName:  "com.example.UserService",
-// 		MethodName:   "GetUser",
-// 		Output:       outputBSON(t, map[string]any{"name": "Alice"}),
-// 		StatusCode:   0,
-// 		Latency:      0,
-// 	}
-// 	grpcRepo.EXPECT().
-// 		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), testFeatureName, "scenario-1", "com.example.UserService", "GetUser", expectedHash).
-// 		Return(mock, nil)
-
-// 	aid := testAccountID
-// 	result, code, err := uc.HandleCall(context.Background(), "/com.example.UserService/GetUser", reqBytes, testFeatureName, &aid)
-
-// 	require.NoError(t, err)
-// 	assert.Equal(t, codes.OK, code)
-// 	assert.NotNil(t, result)
-// 	assert.Equal(t, "Alice", result.Fields["name"].GetStringValue())
-// }
-
-// func TestHandleCall_FallbackToEmptyHash(t *testing.T) {
-// 	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
-// 	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
-
-// 	reqBytes := []byte(`{"id":99}`)
-// 	computedHash := hashBytes(reqBytes)
-// 	mock := &domain.GRPCMockAPI{
-// 		ServiceName: "svc",
-// 		MethodName:  "Method",
-// 		Output:      outputBSON(t, map[string]any{"ok": true}),
-// 	}
-// 	grpcRepo.EXPECT().
-// 		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), testFeatureName, "scenario-1", "svc", "Method", computedHash).
-// 		Return(nil, mongo.ErrNoDocuments)
-// 	grpcRepo.EXPECT().
-// 		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), testFeatureName, "scenario-1", "svc", "Method", "").
-// 		Return(mock, nil)
-
-// 	aid := testAccountID
-// 	result, code, err := uc.HandleCall(context.Background(), "/svc/Method", reqBytes, testFeatureName, &aid)
-
-// 	require.NoError(t, err)
-// 	assert.Equal(t, codes.OK, code)
-// 	assert.True(t, result.Fields["ok"].GetBoolValue())
-// }
-
-// func TestHandleCall_NotFound(t *testing.T) {
-// 	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
-// 	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
-
-// 	grpcRepo.EXPECT().
-// 		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-// 		Return(nil, mongo.ErrNoDocuments).Times(2)
-
-// 	aid := testAccountID
-// 	_, code, err := uc.HandleCall(context.Background(), "/svc/Missing", []byte(`{}`), testFeatureName, &aid)
-
-// 	assert.Error(t, err)
-// 	assert.Equal(t, codes.NotFound, code)
-// }
-
-// func TestHandleCall_StatusCodeOverride(t *testing.T) {
-// 	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
-// 	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
-
-// 	mock := &domain.GRPCMockAPI{
-// 		ServiceName: "svc",
-// 		MethodName:  "Fail",
-// 		Output:      outputBSON(t, map[string]any{}),
-// 		StatusCode:  int32(codes.PermissionDenied),
-// 	}
-// 	grpcRepo.EXPECT().
-// 		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-// 		Return(mock, nil)
-
-// 	aid := testAccountID
-// 	_, code, err := uc.HandleCall(context.Background(), "/svc/Fail", nil, testFeatureName, &aid)
-
-// 	assert.Error(t, err)
-// 	assert.Equal(t, codes.PermissionDenied, code)
-// }
-
-// func TestSplitFullMethod(t *testing.T) {
-// 	tests := []struct {
-// 		input   string
-// 		svc     string
-// 		method  string
-// 	}{
-// 		{"/com.example.UserService/GetUser", "com.example.UserService", "GetUser"},
-// 		{"/svc/Method", "svc", "Method"},
-// 		{"NoSlashAtAll", "NoSlashAtAll", ""},
-// 	}
-// 	for _, tt := range tests {
-// 		svc, method := splitFullMethod(tt.input)
-// 		assert.Equal(t, tt.svc, svc, "input: %s", tt.input)
-// 		assert.Equal(t, tt.method, method, "input: %s", tt.input)
-// 	}
-// }
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+	mockrepo "github.com/namnv2496/mocktool/mocks/repository"
+)
+
+func newGRPCForwardUC(t *testing.T) (
+	IGRPCForwardUC,
+	*mockrepo.MockIGRPCMockAPIRepository,
+	*mockrepo.MockIScenarioRepository,
+	*mockrepo.MockIAccountScenarioRepository,
+) {
+	ctrl := gomock.NewController(t)
+	grpcRepo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
+	scenarioRepo := mockrepo.NewMockIScenarioRepository(ctrl)
+	accountScenarioRepo := mockrepo.NewMockIAccountScenarioRepository(ctrl)
+	uc := NewGRPCForwardUC(grpcRepo, scenarioRepo, accountScenarioRepo, nil, nil, missingCache(ctrl), NewStatsStore())
+	return uc, grpcRepo, scenarioRepo, accountScenarioRepo
+}
+
+// missingCache returns a cache that never holds an entry.
+func missingCache(ctrl *gomock.Controller) *mockrepo.MockICache {
+	cacheRepo := mockrepo.NewMockICache(ctrl)
+	cacheRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, repository.ErrCacheMiss).AnyTimes()
+	cacheRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	cacheRepo.EXPECT().SetWithTTL(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return cacheRepo
+}
+
+var (
+	testScenarioID  = primitive.NewObjectID()
+	testAccountID   = "acc-1"
+	testFeatureName = "my-feature"
+	testScenarioObj = &domain.Scenario{ID: testScenarioID, Name: "scenario-1"}
+)
+
+func setupScenarioMocks(
+	accountScenarioRepo *mockrepo.MockIAccountScenarioRepository,
+	scenarioRepo *mockrepo.MockIScenarioRepository,
+) {
+	accountScenarioRepo.EXPECT().
+		GetActiveScenario(gomock.Any(), testFeatureName, gomock.Any()).
+		Return(&domain.AccountScenario{ScenarioID: testScenarioID}, nil)
+	scenarioRepo.EXPECT().
+		GetByObjectID(gomock.Any(), testScenarioID).
+		Return(testScenarioObj, nil)
+}
+
+func outputBSON(t *testing.T, m map[string]any) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(m)
+	require.NoError(t, err)
+	return raw
+}
+
+func structBytes(t *testing.T, m map[string]any) []byte {
+	t.Helper()
+	s, err := structpb.NewStruct(m)
+	require.NoError(t, err)
+	b, err := proto.Marshal(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestHandleCall_HitExactHash(t *testing.T) {
+	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
+	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
+
+	reqBytes := structBytes(t, map[string]any{"id": 1})
+	expectedHash := hashStructProto(reqBytes)
+	mock := &domain.GRPCMockAPI{
+		ServiceName: "com.example.UserService",
+		MethodName:  "GetUser",
+		Output:      outputBSON(t, map[string]any{"name": "Alice"}),
+	}
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), testFeatureName, "scenario-1", "com.example.UserService", "GetUser", expectedHash).
+		Return(mock, nil)
+
+	aid := testAccountID
+	result, code, err := uc.HandleCall(context.Background(), "/com.example.UserService/GetUser", reqBytes, testFeatureName, &aid, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, "Alice", result.(*structpb.Struct).Fields["name"].GetStringValue())
+}
+
+func TestHandleCall_FallbackToEmptyHash(t *testing.T) {
+	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
+	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
+
+	reqBytes := structBytes(t, map[string]any{"id": 99})
+	computedHash := hashStructProto(reqBytes)
+	mock := &domain.GRPCMockAPI{
+		ServiceName: "svc",
+		MethodName:  "Method",
+		Output:      outputBSON(t, map[string]any{"ok": true}),
+	}
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), testFeatureName, "scenario-1", "svc", "Method", computedHash).
+		Return(nil, mongo.ErrNoDocuments)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "scenario-1", "svc", "Method").
+		Return([]domain.GRPCMockAPI{*mock}, nil)
+
+	aid := testAccountID
+	result, code, err := uc.HandleCall(context.Background(), "/svc/Method", reqBytes, testFeatureName, &aid, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.True(t, result.(*structpb.Struct).Fields["ok"].GetBoolValue())
+}
+
+func TestHandleCall_NotFound(t *testing.T) {
+	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
+	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
+
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, mongo.ErrNoDocuments)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+
+	aid := testAccountID
+	_, code, err := uc.HandleCall(context.Background(), "/svc/Missing", structBytes(t, map[string]any{"id": 1}), testFeatureName, &aid, "")
+
+	assert.ErrorIs(t, err, ErrNoGRPCMock)
+	assert.Equal(t, codes.NotFound, code)
+}
+
+func TestHandleCall_StatusCodeOverride(t *testing.T) {
+	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
+	setupScenarioMocks(accountScenarioRepo, scenarioRepo)
+
+	mock := &domain.GRPCMockAPI{
+		ServiceName: "svc",
+		MethodName:  "Fail",
+		Output:      outputBSON(t, map[string]any{}),
+		StatusCode:  int32(codes.PermissionDenied),
+	}
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{*mock}, nil)
+
+	aid := testAccountID
+	_, code, err := uc.HandleCall(context.Background(), "/svc/Fail", nil, testFeatureName, &aid, "")
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNoGRPCMock, "a mocked status is not a miss")
+	assert.Equal(t, codes.PermissionDenied, code)
+}
+
+func TestHandleCall_GlobalScenarioWithoutAccount(t *testing.T) {
+	uc, grpcRepo, scenarioRepo, accountScenarioRepo := newGRPCForwardUC(t)
+
+	accountScenarioRepo.EXPECT().
+		GetActiveScenario(gomock.Any(), testFeatureName, (*string)(nil)).
+		Return(&domain.AccountScenario{ScenarioID: testScenarioID}, nil)
+	scenarioRepo.EXPECT().
+		GetByObjectID(gomock.Any(), testScenarioID).
+		Return(testScenarioObj, nil)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "scenario-1", "svc", "Method").
+		Return([]domain.GRPCMockAPI{{Output: outputBSON(t, map[string]any{"ok": true})}}, nil)
+
+	_, code, err := uc.HandleCall(context.Background(), "/svc/Method", nil, testFeatureName, nil, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+}
+
+func TestHandleCall_ScenarioOverrideSkipsActivation(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "pinned", "svc", "Method").
+		Return([]domain.GRPCMockAPI{{Output: outputBSON(t, map[string]any{"ok": true})}}, nil)
+
+	aid := testAccountID
+	_, code, err := uc.HandleCall(context.Background(), "/svc/Method", nil, testFeatureName, &aid, "pinned")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+}
+
+func TestHandleCall_NoActiveScenario(t *testing.T) {
+	uc, _, _, accountScenarioRepo := newGRPCForwardUC(t)
+
+	accountScenarioRepo.EXPECT().
+		GetActiveScenario(gomock.Any(), testFeatureName, gomock.Any()).
+		Return(nil, mongo.ErrNoDocuments)
+
+	aid := testAccountID
+	_, code, err := uc.HandleCall(context.Background(), "/svc/Method", nil, testFeatureName, &aid, "")
+
+	assert.ErrorIs(t, err, ErrNoGRPCMock)
+	assert.Equal(t, codes.FailedPrecondition, code)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestHandleCall_Sequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	grpcRepo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
+	cacheRepo := missingCache(ctrl)
+	uc := NewGRPCForwardUC(grpcRepo, nil, nil, nil, nil, cacheRepo, nil)
+
+	mock := &domain.GRPCMockAPI{
+		Output: outputBSON(t, map[string]any{"name": "final"}),
+		Sequence: []domain.GRPCSequenceResponse{
+			{From: 1, To: 3, StatusCode: int32(codes.Unavailable), StatusMessage: "try again"},
+			{From: 3, To: 4, Output: outputBSON(t, map[string]any{"name": "third"})},
+		},
+	}
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{*mock}, nil).Times(4)
+
+	aid := testAccountID
+	seqKey := "mocktool:grpcseq:" + testFeatureName + ":pinned:" + aid + ":svc:Method:"
+	for i, want := range []struct {
+		code codes.Code
+		name string
+	}{
+		{codes.Unavailable, ""},
+		{codes.Unavailable, ""},
+		{codes.OK, "third"},
+		{codes.OK, "final"},
+	} {
+		cacheRepo.EXPECT().IncrWithTTL(gomock.Any(), seqKey, sequenceCounterTTL).Return(int64(i+1), nil)
+
+		result, code, err := uc.HandleCall(context.Background(), "/svc/Method", nil, testFeatureName, &aid, "pinned")
+
+		assert.Equal(t, want.code, code, "call %d", i+1)
+		if want.code != codes.OK {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, want.name, result.(*structpb.Struct).Fields["name"].GetStringValue(), "call %d", i+1)
+	}
+}
+
+func TestHandleCall_CacheHitSkipsRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cacheRepo := mockrepo.NewMockICache(ctrl)
+	stats := NewStatsStore()
+	uc := NewGRPCForwardUC(mockrepo.NewMockIGRPCMockAPIRepository(ctrl), nil, nil, nil, nil, cacheRepo, stats)
+
+	cached, err := bson.MarshalExtJSON(&domain.GRPCMockAPI{Output: outputBSON(t, map[string]any{"name": "cached"})}, true, false)
+	require.NoError(t, err)
+	cacheRepo.EXPECT().
+		Get(gomock.Any(), "mocktool:"+testFeatureName+":pinned:grpc:svc:Method:").
+		Return(string(cached), nil)
+
+	result, code, err := uc.HandleCall(context.Background(), "/svc/Method", nil, testFeatureName, nil, "pinned")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, "cached", result.(*structpb.Struct).Fields["name"].GetStringValue())
+	snap := stats.Snapshot()
+	require.Len(t, snap, 1)
+	assert.Equal(t, APIStat{Feature: testFeatureName, Scenario: "pinned", Path: "/svc/Method", Method: grpcStatsMethod, Hits: 1, CacheHits: 1, TotalLatMs: snap[0].TotalLatMs}, snap[0])
+}
+
+func TestHandleCall_NegativeCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	grpcRepo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
+	cacheRepo := mockrepo.NewMockICache(ctrl)
+	scenarioRepo := mockrepo.NewMockIScenarioRepository(ctrl)
+	uc := NewGRPCForwardUC(grpcRepo, scenarioRepo, nil, nil, nil, cacheRepo, NewStatsStore())
+
+	// The pinned scenario has no parent to fall through to.
+	scenarioRepo.EXPECT().
+		FindByFeatureNameAndName(gomock.Any(), testFeatureName, "pinned").
+		Return(&domain.Scenario{ID: primitive.NewObjectID(), FeatureName: testFeatureName, Name: "pinned"}, nil).
+		Times(2)
+	cacheRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, repository.ErrCacheMiss)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	cacheRepo.EXPECT().SetWithTTL(gomock.Any(), gomock.Any(), notFoundSentinel, notFoundCacheTTL).Return(nil)
+
+	_, code, err := uc.HandleCall(context.Background(), "/svc/Missing", nil, testFeatureName, nil, "pinned")
+	assert.Error(t, err)
+	assert.Equal(t, codes.NotFound, code)
+
+	// Second call is answered from the sentinel without looking the mocks up.
+	cacheRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(notFoundSentinel, nil)
+
+	_, code, err = uc.HandleCall(context.Background(), "/svc/Missing", nil, testFeatureName, nil, "pinned")
+	assert.Error(t, err)
+	assert.Equal(t, codes.NotFound, code)
+}
+
+func TestHandleCall_FieldMatchers(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	min, max := 100.0, 200.0
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, mongo.ErrNoDocuments).AnyTimes()
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "pinned", "svc", "GetUser").
+		Return([]domain.GRPCMockAPI{
+			{Output: outputBSON(t, map[string]any{"name": "anyone"})},
+			{
+				Matchers: []domain.GRPCFieldMatcher{{Path: "user_id", Op: domain.GRPCMatchRange, Min: &min, Max: &max}},
+				Output:   outputBSON(t, map[string]any{"name": "range"}),
+			},
+			{
+				Matchers: []domain.GRPCFieldMatcher{{Path: "user_id", Op: domain.GRPCMatchEquals, Value: "150"}},
+				Output:   outputBSON(t, map[string]any{"name": "exact"}),
+			},
+			{
+				Matchers: []domain.GRPCFieldMatcher{{Path: "profile.email", Op: domain.GRPCMatchRegex, Value: `@example\.com$`}},
+				Output:   outputBSON(t, map[string]any{"name": "example"}),
+			},
+		}, nil).Times(4)
+
+	for _, tt := range []struct {
+		req  map[string]any
+		want string
+	}{
+		{map[string]any{"user_id": 150}, "exact"},
+		{map[string]any{"user_id": 120}, "range"},
+		{map[string]any{"user_id": 1, "profile": map[string]any{"email": "bob@example.com"}}, "example"},
+		{map[string]any{"user_id": 1}, "anyone"},
+	} {
+		result, code, err := uc.HandleCall(context.Background(), "/svc/GetUser", structBytes(t, tt.req), testFeatureName, nil, "pinned")
+
+		require.NoError(t, err)
+		assert.Equal(t, codes.OK, code)
+		assert.Equal(t, tt.want, result.(*structpb.Struct).Fields["name"].GetStringValue(), "request %v", tt.req)
+	}
+}
+
+func TestHandleCall_LatencyRespectsDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		mock domain.GRPCMockAPI
+		ctx  func() (context.Context, context.CancelFunc)
+		want codes.Code
+	}{
+		{
+			name: "latency beyond deadline",
+			mock: domain.GRPCMockAPI{Latency: 10_000},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			want: codes.DeadlineExceeded,
+		},
+		{
+			name: "hang until deadline",
+			mock: domain.GRPCMockAPI{Hang: true},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			want: codes.DeadlineExceeded,
+		},
+		{
+			name: "hang until cancelled",
+			mock: domain.GRPCMockAPI{Hang: true},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			want: codes.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+			tt.mock.Output = outputBSON(t, map[string]any{"ok": true})
+			grpcRepo.EXPECT().
+				ListActiveByMethod(gomock.Any(), testFeatureName, "pinned", "svc", "Method").
+				Return([]domain.GRPCMockAPI{tt.mock}, nil)
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			start := time.Now()
+			result, code, err := uc.HandleCall(ctx, "/svc/Method", nil, testFeatureName, nil, "pinned")
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.want, code)
+			assert.Equal(t, tt.want, status.Code(err))
+			assert.Less(t, time.Since(start), time.Second, "the call ends with the client, not the latency")
+		})
+	}
+}
+
+func TestHandleCall_LatencyWithinDeadline(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "pinned", "svc", "Method").
+		Return([]domain.GRPCMockAPI{{Latency: 10, Output: outputBSON(t, map[string]any{"ok": true})}}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, code, err := uc.HandleCall(ctx, "/svc/Method", nil, testFeatureName, nil, "pinned")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+}
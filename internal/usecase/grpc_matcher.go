@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+// Weight of each operator when ordering matcher mocks by specificity: an
+// exact value says more about the request than a range, a pattern or the
+// mere presence of a field.
+var grpcMatcherWeight = map[string]int{
+	domain.GRPCMatchEquals: 4,
+	domain.GRPCMatchRange:  3,
+	domain.GRPCMatchRegex:  2,
+	domain.GRPCMatchExists: 1,
+	domain.GRPCMatchAbsent: 1,
+}
+
+// compiled regexps of matchers, keyed by pattern
+var grpcMatcherRegexps sync.Map
+
+// ValidateGRPCMatchers checks operators, paths, regexps and range bounds so
+// broken matchers are rejected when the mock is saved.
+func ValidateGRPCMatchers(matchers []domain.GRPCFieldMatcher) error {
+	for i, m := range matchers {
+		if strings.TrimSpace(m.Path) == "" {
+			return fmt.Errorf("matchers[%d].path is required", i)
+		}
+		switch m.Op {
+		case domain.GRPCMatchEquals, domain.GRPCMatchExists, domain.GRPCMatchAbsent:
+		case domain.GRPCMatchRegex:
+			if _, err := matcherRegexp(m.Value); err != nil {
+				return fmt.Errorf("matchers[%d].value: %w", i, err)
+			}
+		case domain.GRPCMatchRange:
+			if m.Min == nil && m.Max == nil {
+				return fmt.Errorf("matchers[%d]: range needs min or max", i)
+			}
+			if m.Min != nil && m.Max != nil && *m.Min > *m.Max {
+				return fmt.Errorf("matchers[%d]: min is greater than max", i)
+			}
+		default:
+			return fmt.Errorf("matchers[%d].op must be one of equals, regex, exists, absent, range", i)
+		}
+	}
+	return nil
+}
+
+// pickGRPCMock returns the mock for a request: the mock whose input hash
+// equals hash, then the most specific mock whose matchers all hold for req,
+// then the match-all mock.
+func pickGRPCMock(mocks []domain.GRPCMockAPI, hash string, req map[string]any) *domain.GRPCMockAPI {
+	var matched []*domain.GRPCMockAPI
+	for i := range mocks {
+		m := &mocks[i]
+		if hash != "" && m.HashInput == hash {
+			return m
+		}
+		if len(m.Matchers) > 0 && matchGRPCFields(m.Matchers, req) {
+			matched = append(matched, m)
+		}
+	}
+	if m := mostSpecificGRPCMock(matched); m != nil {
+		return m
+	}
+	return matchAllGRPCMock(mocks)
+}
+
+// mostSpecificGRPCMock orders matched mocks by the summed weight of their
+// matchers; ties keep the original (creation) order.
+func mostSpecificGRPCMock(matched []*domain.GRPCMockAPI) *domain.GRPCMockAPI {
+	if len(matched) == 0 {
+		return nil
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matcherSpecificity(matched[i].Matchers) > matcherSpecificity(matched[j].Matchers)
+	})
+	return matched[0]
+}
+
+// matchAllGRPCMock returns the first mock without input and matchers.
+func matchAllGRPCMock(mocks []domain.GRPCMockAPI) *domain.GRPCMockAPI {
+	for i := range mocks {
+		if mocks[i].HashInput == "" && len(mocks[i].Matchers) == 0 {
+			return &mocks[i]
+		}
+	}
+	return nil
+}
+
+func matcherSpecificity(matchers []domain.GRPCFieldMatcher) int {
+	total := 0
+	for _, m := range matchers {
+		total += grpcMatcherWeight[m.Op]
+	}
+	return total
+}
+
+// matchGRPCFields reports whether every matcher holds for req.
+func matchGRPCFields(matchers []domain.GRPCFieldMatcher, req map[string]any) bool {
+	for _, m := range matchers {
+		value, ok := lookupField(req, m.Path)
+		switch m.Op {
+		case domain.GRPCMatchExists:
+			if !ok {
+				return false
+			}
+		case domain.GRPCMatchAbsent:
+			if ok {
+				return false
+			}
+		case domain.GRPCMatchEquals:
+			if !ok || fieldString(value) != m.Value {
+				return false
+			}
+		case domain.GRPCMatchRegex:
+			re, err := matcherRegexp(m.Value)
+			if !ok || err != nil || !re.MatchString(fieldString(value)) {
+				return false
+			}
+		case domain.GRPCMatchRange:
+			n, isNumber := fieldNumber(value)
+			if !ok || !isNumber || (m.Min != nil && n < *m.Min) || (m.Max != nil && n > *m.Max) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// lookupField walks a dot-separated path through nested objects and lists.
+func lookupField(req map[string]any, path string) (any, bool) {
+	var cur any = req
+	for _, part := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []any:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			cur = node[idx]
+		default:
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+// fieldString is the form compared by equals and regex: strings as is,
+// numbers without trailing zeros, objects and lists as JSON.
+func fieldString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		b, _ := json.Marshal(x)
+		return string(b)
+	}
+}
+
+// fieldNumber accepts JSON numbers and numeric strings, which is how the
+// protobuf JSON mapping encodes 64-bit integers.
+func fieldNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case string:
+		n, err := strconv.ParseFloat(x, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func matcherRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := grpcMatcherRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	grpcMatcherRegexps.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+func TestMatchGRPCFields(t *testing.T) {
+	req := map[string]any{
+		"user_id": "9007199254740993", // int64 in protobuf JSON
+		"items":   []any{map[string]any{"sku": "A-1", "qty": 2.0}},
+		"vip":     true,
+	}
+	one := 1.0
+
+	tests := []struct {
+		matcher domain.GRPCFieldMatcher
+		want    bool
+	}{
+		{domain.GRPCFieldMatcher{Path: "user_id", Op: domain.GRPCMatchEquals, Value: "9007199254740993"}, true},
+		{domain.GRPCFieldMatcher{Path: "items.0.sku", Op: domain.GRPCMatchRegex, Value: "^A-"}, true},
+		{domain.GRPCFieldMatcher{Path: "items.0.qty", Op: domain.GRPCMatchRange, Min: &one}, true},
+		{domain.GRPCFieldMatcher{Path: "items.0.qty", Op: domain.GRPCMatchRange, Max: &one}, false},
+		{domain.GRPCFieldMatcher{Path: "items.1.sku", Op: domain.GRPCMatchExists}, false},
+		{domain.GRPCFieldMatcher{Path: "coupon", Op: domain.GRPCMatchAbsent}, true},
+		{domain.GRPCFieldMatcher{Path: "vip", Op: domain.GRPCMatchEquals, Value: "true"}, true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchGRPCFields([]domain.GRPCFieldMatcher{tt.matcher}, req), "%+v", tt.matcher)
+	}
+}
+
+func TestValidateGRPCMatchers(t *testing.T) {
+	assert.NoError(t, ValidateGRPCMatchers([]domain.GRPCFieldMatcher{{Path: "id", Op: domain.GRPCMatchExists}}))
+	assert.Error(t, ValidateGRPCMatchers([]domain.GRPCFieldMatcher{{Path: "id", Op: "contains"}}))
+	assert.Error(t, ValidateGRPCMatchers([]domain.GRPCFieldMatcher{{Path: "id", Op: domain.GRPCMatchRegex, Value: "("}}))
+	assert.Error(t, ValidateGRPCMatchers([]domain.GRPCFieldMatcher{{Path: "id", Op: domain.GRPCMatchRange}}))
+}
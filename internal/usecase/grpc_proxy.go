@@ -0,0 +1,542 @@
+package usecase
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	grpc_reflection_v1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+// upstreamReflectionRetry is how long a failed reflection lookup of an
+// upstream service is remembered before it is tried again.
+const upstreamReflectionRetry = time.Minute
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type IGRPCProxyUC interface {
+	Find(serviceName string) *domain.GRPCProxy
+	List(ctx context.Context) ([]domain.GRPCProxy, error)
+	Save(ctx context.Context, p *domain.GRPCProxy) error
+	Delete(ctx context.Context, serviceName string) error
+	Forward(ctx context.Context, fullMethod string, reqBytes []byte, md metadata.MD, stream GRPCStream) (codes.Code, error)
+	ForwardStream(ctx context.Context, fullMethod string, md metadata.MD, stream GRPCStream) (codes.Code, error)
+}
+
+// GRPCProxyUC forwards calls that no mock matches to the upstream configured
+// for their service and optionally records unary ones as mocks.
+//
+// Proxy configs are loaded lazily and replaced after every save or delete
+// made through this instance.
+type GRPCProxyUC struct {
+	proxyRepo    repository.IGRPCProxyRepository
+	grpcMockRepo repository.IGRPCMockAPIRepository
+	descriptors  *GRPCDescriptorRegistry
+	serviceIndex *GRPCServiceIndex
+	cacheRepo    repository.ICache
+
+	mu      sync.RWMutex
+	loaded  bool
+	proxies map[string]*domain.GRPCProxy // service name → proxy
+	conns   map[string]*grpc.ClientConn  // upstream → connection
+
+	reflectionFailures sync.Map // service name → time of the failed lookup
+	dialOptions        []grpc.DialOption
+}
+
+func NewGRPCProxyUC(
+	proxyRepo repository.IGRPCProxyRepository,
+	grpcMockRepo repository.IGRPCMockAPIRepository,
+	descriptors *GRPCDescriptorRegistry,
+	serviceIndex *GRPCServiceIndex,
+	cacheRepo repository.ICache,
+) IGRPCProxyUC {
+	return &GRPCProxyUC{
+		proxyRepo:    proxyRepo,
+		grpcMockRepo: grpcMockRepo,
+		descriptors:  descriptors,
+		serviceIndex: serviceIndex,
+		cacheRepo:    cacheRepo,
+		proxies:      make(map[string]*domain.GRPCProxy),
+		conns:        make(map[string]*grpc.ClientConn),
+	}
+}
+
+// Find returns the active proxy of a service, or nil.
+func (_self *GRPCProxyUC) Find(serviceName string) *domain.GRPCProxy {
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	p := _self.proxies[serviceName]
+	if p == nil || !p.IsActive {
+		return nil
+	}
+	return p
+}
+
+func (_self *GRPCProxyUC) List(ctx context.Context) ([]domain.GRPCProxy, error) {
+	return _self.proxyRepo.ListAll(ctx)
+}
+
+func (_self *GRPCProxyUC) Save(ctx context.Context, p *domain.GRPCProxy) error {
+	if err := _self.proxyRepo.Upsert(ctx, p); err != nil {
+		return err
+	}
+	_self.ensureLoaded()
+	_self.mu.Lock()
+	defer _self.mu.Unlock()
+	old := _self.proxies[p.ServiceName]
+	saved := *p
+	_self.proxies[p.ServiceName] = &saved
+	if old != nil && connKey(old) != connKey(p) {
+		_self.reflectionFailures.Delete(p.ServiceName)
+		_self.releaseConnLocked(old)
+	}
+	return nil
+}
+
+func (_self *GRPCProxyUC) Delete(ctx context.Context, serviceName string) error {
+	if err := _self.proxyRepo.DeleteByServiceName(ctx, serviceName); err != nil {
+		return err
+	}
+	_self.mu.Lock()
+	defer _self.mu.Unlock()
+	old := _self.proxies[serviceName]
+	delete(_self.proxies, serviceName)
+	_self.reflectionFailures.Delete(serviceName)
+	if old != nil {
+		_self.releaseConnLocked(old)
+	}
+	return nil
+}
+
+// releaseConnLocked closes the connection of a removed or retargeted proxy
+// unless another proxy still uses the same upstream. Calls in flight on it
+// fail with CANCELED.
+func (_self *GRPCProxyUC) releaseConnLocked(old *domain.GRPCProxy) {
+	key := connKey(old)
+	for _, p := range _self.proxies {
+		if connKey(p) == key {
+			return
+		}
+	}
+	if conn := _self.conns[key]; conn != nil {
+		conn.Close()
+		delete(_self.conns, key)
+	}
+}
+
+func connKey(p *domain.GRPCProxy) string {
+	return fmt.Sprintf("%s|%t", p.Upstream, p.TLS)
+}
+
+func (_self *GRPCProxyUC) ensureLoaded() {
+	_self.mu.RLock()
+	loaded := _self.loaded
+	_self.mu.RUnlock()
+	if loaded {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	all, err := _self.proxyRepo.ListAll(ctx)
+	if err != nil {
+		slog.Warn("load gRPC proxies", "error", err)
+		return
+	}
+	proxies := make(map[string]*domain.GRPCProxy, len(all))
+	for i := range all {
+		proxies[all[i].ServiceName] = &all[i]
+	}
+	_self.mu.Lock()
+	_self.proxies = proxies
+	_self.loaded = true
+	_self.mu.Unlock()
+}
+
+// Forward sends one unary request to the upstream of its service and relays
+// the response, headers, trailers and status to stream. md is the incoming
+// call metadata; mocktool's own keys are not forwarded.
+func (_self *GRPCProxyUC) Forward(
+	ctx context.Context,
+	fullMethod string,
+	reqBytes []byte,
+	md metadata.MD,
+	stream GRPCStream,
+) (codes.Code, error) {
+	serviceName, _ := utils.SplitGRPCMethod(fullMethod)
+	proxy := _self.Find(serviceName)
+	if proxy == nil {
+		return codes.NotFound, status.Errorf(codes.NotFound, "no proxy for %s", serviceName)
+	}
+	conn, err := _self.conn(proxy)
+	if err != nil {
+		return codes.Unavailable, status.Errorf(codes.Unavailable, "dial %s: %v", proxy.Upstream, err)
+	}
+
+	var respBytes []byte
+	var header, trailer metadata.MD
+	callErr := conn.Invoke(
+		metadata.NewOutgoingContext(ctx, forwardableMetadata(md)),
+		fullMethod,
+		reqBytes,
+		&respBytes,
+		grpc.ForceCodec(passthroughCodec{}),
+		grpc.Header(&header),
+		grpc.Trailer(&trailer),
+	)
+	if err := stream.SetHeader(forwardableMetadata(header)); err != nil {
+		return codes.Internal, err
+	}
+	stream.SetTrailer(forwardableMetadata(trailer))
+
+	if proxy.Record {
+		recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		if err := _self.record(recordCtx, proxy, conn, fullMethod, reqBytes, respBytes, callErr); err != nil {
+			slog.Warn("record gRPC call", "method", fullMethod, "error", err)
+		}
+		cancel()
+	}
+
+	if callErr != nil {
+		return status.Code(callErr), callErr
+	}
+	if err := _self.relay(fullMethod, respBytes, stream); err != nil {
+		return status.Code(err), err
+	}
+	return codes.OK, nil
+}
+
+// ForwardStream passes a streaming call through to the upstream of its
+// service as a raw bidirectional stream: every message read from stream is
+// sent upstream until the client closes its side, and the upstream messages,
+// headers, trailers and status are relayed back. Streaming calls are never
+// recorded.
+func (_self *GRPCProxyUC) ForwardStream(
+	ctx context.Context,
+	fullMethod string,
+	md metadata.MD,
+	stream GRPCStream,
+) (codes.Code, error) {
+	serviceName, _ := utils.SplitGRPCMethod(fullMethod)
+	proxy := _self.Find(serviceName)
+	if proxy == nil {
+		return codes.NotFound, status.Errorf(codes.NotFound, "no proxy for %s", serviceName)
+	}
+	conn, err := _self.conn(proxy)
+	if err != nil {
+		return codes.Unavailable, status.Errorf(codes.Unavailable, "dial %s: %v", proxy.Upstream, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	upstream, err := conn.NewStream(
+		metadata.NewOutgoingContext(ctx, forwardableMetadata(md)),
+		&grpc.StreamDesc{ServerStreams: true, ClientStreams: true},
+		fullMethod,
+		grpc.ForceCodec(passthroughCodec{}),
+	)
+	if err != nil {
+		return status.Code(err), err
+	}
+
+	// A client that fails mid-stream cancels the upstream call.
+	go func() {
+		for {
+			b, err := stream.Recv()
+			if err == io.EOF {
+				upstream.CloseSend()
+				return
+			}
+			if err != nil || upstream.SendMsg(b) != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	if header, err := upstream.Header(); err == nil {
+		if err := stream.SetHeader(forwardableMetadata(header)); err != nil {
+			return codes.Internal, err
+		}
+	}
+	for {
+		var b []byte
+		err := upstream.RecvMsg(&b)
+		if err == io.EOF {
+			stream.SetTrailer(forwardableMetadata(upstream.Trailer()))
+			return codes.OK, nil
+		}
+		if err != nil {
+			stream.SetTrailer(forwardableMetadata(upstream.Trailer()))
+			return status.Code(err), err
+		}
+		if err := _self.relay(fullMethod, b, stream); err != nil {
+			return status.Code(err), err
+		}
+	}
+}
+
+// relay sends one upstream response message to stream. It is typed when a
+// descriptor is known so JSON clients get real fields; otherwise the bytes
+// are relayed untouched as unknown fields.
+func (_self *GRPCProxyUC) relay(fullMethod string, b []byte, stream GRPCStream) error {
+	var resp proto.Message = &emptypb.Empty{}
+	if method := _self.descriptors.FindMethod(fullMethod); method != nil {
+		resp = dynamicpb.NewMessage(method.Output())
+	}
+	if err := proto.Unmarshal(b, resp); err != nil {
+		return status.Errorf(codes.Internal, "decode upstream response: %v", err)
+	}
+	if err := stream.Send(resp); err != nil {
+		return status.Errorf(codes.Unavailable, "send response: %v", err)
+	}
+	return nil
+}
+
+func (_self *GRPCProxyUC) conn(proxy *domain.GRPCProxy) (*grpc.ClientConn, error) {
+	key := connKey(proxy)
+	_self.mu.RLock()
+	conn := _self.conns[key]
+	_self.mu.RUnlock()
+	if conn != nil {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if proxy.TLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, _self.dialOptions...)
+	conn, err := grpc.NewClient(proxy.Upstream, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_self.mu.Lock()
+	defer _self.mu.Unlock()
+	if existing := _self.conns[key]; existing != nil {
+		conn.Close()
+		return existing, nil
+	}
+	_self.conns[key] = conn
+	return conn, nil
+}
+
+// record saves a forwarded call as a mock of the proxy's record target.
+// Requests that already have a mock there are skipped.
+func (_self *GRPCProxyUC) record(
+	ctx context.Context,
+	proxy *domain.GRPCProxy,
+	conn *grpc.ClientConn,
+	fullMethod string,
+	reqBytes, respBytes []byte,
+	callErr error,
+) error {
+	method, err := _self.methodDescriptor(ctx, conn, fullMethod)
+	if err != nil {
+		return err
+	}
+	req, err := decodeTypedRequest(method.Input(), reqBytes)
+	if err != nil {
+		return fmt.Errorf("decode request: %w", err)
+	}
+	serviceName, methodName := utils.SplitGRPCMethod(fullMethod)
+	hash := hashTypedRequest(req)
+
+	_, err = _self.grpcMockRepo.FindByFeatureScenarioServiceMethodAndHash(
+		ctx, proxy.RecordFeature, proxy.RecordScenario, serviceName, methodName, hash,
+	)
+	if err == nil {
+		return nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	mock := &domain.GRPCMockAPI{
+		FeatureName:  proxy.RecordFeature,
+		ScenarioName: proxy.RecordScenario,
+		ServiceName:  serviceName,
+		MethodName:   methodName,
+		HashInput:    hash,
+	}
+	if len(req) > 0 {
+		if mock.Input, err = bson.Marshal(req); err != nil {
+			return err
+		}
+	}
+	output := map[string]any{}
+	if callErr != nil {
+		st := status.Convert(callErr)
+		mock.StatusCode = int32(st.Code())
+		mock.StatusMessage = st.Message()
+	} else if output, err = decodeTypedRequest(method.Output(), respBytes); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if mock.Output, err = bson.Marshal(output); err != nil {
+		return err
+	}
+
+	if err := _self.grpcMockRepo.Create(ctx, mock); err != nil {
+		return err
+	}
+	_self.serviceIndex.Put(mock)
+	_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyGRPCScenarioTemplate, proxy.RecordFeature, proxy.RecordScenario))
+	return nil
+}
+
+// methodDescriptor returns the registered descriptor of a method. Services
+// without one are fetched through the upstream's reflection service and
+// registered like an upload, so the recorded mocks are decoded and hashed
+// the same way when they are replayed.
+func (_self *GRPCProxyUC) methodDescriptor(ctx context.Context, conn *grpc.ClientConn, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	if method := _self.descriptors.FindMethod(fullMethod); method != nil {
+		return method, nil
+	}
+	serviceName, _ := utils.SplitGRPCMethod(fullMethod)
+	if _self.descriptors.FindService(serviceName) == nil {
+		if failedAt, ok := _self.reflectionFailures.Load(serviceName); ok && time.Since(failedAt.(time.Time)) < upstreamReflectionRetry {
+			return nil, fmt.Errorf("no descriptor for %s", serviceName)
+		}
+		set, err := fetchUpstreamDescriptors(ctx, conn, serviceName)
+		if err == nil {
+			_, err = _self.descriptors.Register(ctx, set, domain.GRPCDescriptorSourceReflection)
+		}
+		if err != nil {
+			_self.reflectionFailures.Store(serviceName, time.Now())
+			return nil, fmt.Errorf("no descriptor for %s: %w", serviceName, err)
+		}
+		if method := _self.descriptors.FindMethod(fullMethod); method != nil {
+			return method, nil
+		}
+	}
+	return nil, fmt.Errorf("method %s not declared by descriptor of %s", fullMethod, serviceName)
+}
+
+// fetchUpstreamDescriptors asks the upstream reflection service for the file
+// declaring serviceName and all of its imports.
+func fetchUpstreamDescriptors(ctx context.Context, conn *grpc.ClientConn, serviceName string) (*descriptorpb.FileDescriptorSet, error) {
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+	var names []string
+	fetch := func(req *grpc_reflection_v1.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return errors.New(e.GetErrorMessage())
+		}
+		for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fdp := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, fdp); err != nil {
+				return err
+			}
+			if files[fdp.GetName()] == nil {
+				files[fdp.GetName()] = fdp
+				names = append(names, fdp.GetName())
+			}
+		}
+		return nil
+	}
+
+	if err := fetch(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: serviceName},
+	}); err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(names); i++ {
+		for _, dep := range files[names[i]].GetDependency() {
+			if files[dep] != nil {
+				continue
+			}
+			if fd, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				files[dep] = protodesc.ToFileDescriptorProto(fd)
+				names = append(names, dep)
+				continue
+			}
+			if err := fetch(&grpc_reflection_v1.ServerReflectionRequest{
+				MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			}); err != nil {
+				return nil, fmt.Errorf("fetch %s: %w", dep, err)
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, name := range names {
+		set.File = append(set.File, files[name])
+	}
+	return set, nil
+}
+
+// forwardableMetadata drops mocktool routing keys and the keys owned by the
+// transport.
+func forwardableMetadata(md metadata.MD) metadata.MD {
+	out := metadata.MD{}
+	for k, vals := range md {
+		switch {
+		case strings.HasPrefix(k, ":"), strings.HasPrefix(k, "grpc-"),
+			k == "content-type", k == "user-agent", k == "te",
+			k == "x-feature-name", k == "x-account-id", k == "x-scenario":
+			continue
+		}
+		out[k] = vals
+	}
+	return out
+}
+
+// passthroughCodec sends and receives raw protobuf bytes.
+type passthroughCodec struct{}
+
+func (passthroughCodec) Name() string { return "proto" }
+
+func (passthroughCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("passthroughCodec: unsupported type %T", v)
+	}
+	return b, nil
+}
+
+func (passthroughCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("passthroughCodec: unsupported type %T", v)
+	}
+	*b = append([]byte(nil), data...)
+	return nil
+}
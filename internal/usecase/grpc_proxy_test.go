@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	grpc_reflection_v1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	mockrepo "github.com/namnv2496/mocktool/mocks/repository"
+)
+
+// startUpstream serves demo.v1.UserService with reflection on an in-memory
+// listener. GetUser answers with the user id as name, or NotFound for "none";
+// the undeclared WatchUsers stream answers every GetUserRequest the same way.
+func startUpstream(t *testing.T) *bufconn.Listener {
+	t.Helper()
+	set, err := CompileProtoFiles(context.Background(), map[string]string{"demo/v1/user.proto": testUserProto})
+	require.NoError(t, err)
+	files, err := protodesc.NewFiles(set)
+	require.NoError(t, err)
+	desc, err := files.FindDescriptorByName("demo.v1.UserService")
+	require.NoError(t, err)
+	method := desc.(protoreflect.ServiceDescriptor).Methods().ByName("GetUser")
+
+	srv := grpc.NewServer()
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "demo.v1.UserService",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "GetUser",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				req := dynamicpb.NewMessage(method.Input())
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				userID := req.Get(method.Input().Fields().ByName("user_id")).String()
+				if userID == "none" {
+					return nil, status.Error(codes.NotFound, "no such user")
+				}
+				md, _ := metadata.FromIncomingContext(ctx)
+				if len(md.Get("x-feature-name")) > 0 {
+					return nil, status.Error(codes.InvalidArgument, "routing metadata leaked")
+				}
+				grpc.SetHeader(ctx, metadata.Pairs("x-upstream", "real"))
+				resp := dynamicpb.NewMessage(method.Output())
+				resp.Set(method.Output().Fields().ByName("name"), protoreflect.ValueOfString("user "+userID))
+				return resp, nil
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "WatchUsers",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				stream.SetTrailer(metadata.Pairs("x-watched", "done"))
+				for {
+					req := dynamicpb.NewMessage(method.Input())
+					if err := stream.RecvMsg(req); err == io.EOF {
+						return nil
+					} else if err != nil {
+						return err
+					}
+					userID := req.Get(method.Input().Fields().ByName("user_id")).String()
+					if userID == "none" {
+						return status.Error(codes.NotFound, "no such user")
+					}
+					resp := dynamicpb.NewMessage(method.Output())
+					resp.Set(method.Output().Fields().ByName("name"), protoreflect.ValueOfString("user "+userID))
+					if err := stream.SendMsg(resp); err != nil {
+						return err
+					}
+				}
+			},
+		}},
+	}, struct{}{})
+	grpc_reflection_v1.RegisterServerReflectionServer(srv, reflection.NewServerV1(reflection.ServerOptions{
+		Services:           srv,
+		DescriptorResolver: files,
+	}))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis
+}
+
+func newTestProxyUC(t *testing.T, record bool) (*GRPCProxyUC, *mockrepo.MockIGRPCMockAPIRepository, *mockrepo.MockIGRPCDescriptorRepository) {
+	ctrl := gomock.NewController(t)
+	proxyRepo := mockrepo.NewMockIGRPCProxyRepository(ctrl)
+	grpcRepo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
+	descRepo := mockrepo.NewMockIGRPCDescriptorRepository(ctrl)
+	cache := mockrepo.NewMockICache(ctrl)
+
+	proxyRepo.EXPECT().ListAll(gomock.Any()).Return([]domain.GRPCProxy{{
+		ServiceName:    "demo.v1.UserService",
+		Upstream:       "passthrough:///upstream",
+		Record:         record,
+		RecordFeature:  "checkout",
+		RecordScenario: "recorded",
+		IsActive:       true,
+	}}, nil).AnyTimes()
+	var stored []domain.GRPCDescriptor
+	descRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d *domain.GRPCDescriptor) error {
+		stored = append(stored, *d)
+		return nil
+	}).AnyTimes()
+	descRepo.EXPECT().ListAll(gomock.Any()).DoAndReturn(func(context.Context) ([]domain.GRPCDescriptor, error) {
+		return stored, nil
+	}).AnyTimes()
+	cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	lis := startUpstream(t)
+	uc := NewGRPCProxyUC(proxyRepo, grpcRepo, NewGRPCDescriptorRegistry(descRepo), NewGRPCServiceIndex(grpcRepo), cache).(*GRPCProxyUC)
+	uc.dialOptions = []grpc.DialOption{grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})}
+	return uc, grpcRepo, descRepo
+}
+
+func getUserRequest(t *testing.T, userID string) []byte {
+	t.Helper()
+	method := compileTestService(t).Methods().ByName("GetUser")
+	req := dynamicpb.NewMessage(method.Input())
+	req.Set(method.Input().Fields().ByName("user_id"), protoreflect.ValueOfString(userID))
+	b, err := proto.Marshal(req)
+	require.NoError(t, err)
+	return b
+}
+
+func TestGRPCProxy_ForwardAndRecord(t *testing.T) {
+	uc, grpcRepo, descRepo := newTestProxyUC(t, true)
+
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), "checkout", "recorded", "demo.v1.UserService", "GetUser", gomock.Any()).
+		Return(nil, mongo.ErrNoDocuments)
+	var recorded *domain.GRPCMockAPI
+	grpcRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *domain.GRPCMockAPI) error {
+		recorded = m
+		return nil
+	})
+
+	stream := &fakeStream{}
+	code, err := uc.Forward(context.Background(), "/demo.v1.UserService/GetUser", getUserRequest(t, "u-1"),
+		metadata.Pairs("x-feature-name", "checkout", "authorization", "Bearer t"), stream)
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, []string{"real"}, stream.header.Get("x-upstream"))
+
+	// The reflected descriptor is stored like an upload.
+	method := uc.descriptors.FindMethod("/demo.v1.UserService/GetUser")
+	require.NotNil(t, method)
+	docs, err := descRepo.ListAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, domain.GRPCDescriptorSourceReflection, docs[0].Source)
+
+	require.Len(t, stream.out, 1)
+	assert.Equal(t, "user u-1", stream.out[0].ProtoReflect().Get(method.Output().Fields().ByName("name")).String())
+
+	require.NotNil(t, recorded)
+	assert.Equal(t, "checkout", recorded.FeatureName)
+	assert.Equal(t, "recorded", recorded.ScenarioName)
+	assert.Equal(t, hashTypedRequest(map[string]any{"user_id": "u-1"}), recorded.HashInput)
+	var output map[string]any
+	require.NoError(t, bson.Unmarshal(recorded.Output, &output))
+	assert.Equal(t, map[string]any{"name": "user u-1"}, output)
+}
+
+func TestGRPCProxy_RecordedCallReplays(t *testing.T) {
+	uc, grpcRepo, _ := newTestProxyUC(t, true)
+
+	var recorded *domain.GRPCMockAPI
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), "checkout", "recorded", "demo.v1.UserService", "GetUser", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _, _, hash string) (*domain.GRPCMockAPI, error) {
+			if recorded == nil || recorded.HashInput != hash {
+				return nil, mongo.ErrNoDocuments
+			}
+			return recorded, nil
+		}).AnyTimes()
+	grpcRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *domain.GRPCMockAPI) error {
+		recorded = m
+		return nil
+	})
+
+	reqBytes := getUserRequest(t, "u-1")
+	_, err := uc.Forward(context.Background(), "/demo.v1.UserService/GetUser", reqBytes, nil, &fakeStream{})
+	require.NoError(t, err)
+	require.NotNil(t, recorded)
+
+	forward := NewGRPCForwardUC(grpcRepo, nil, nil, uc.descriptors, nil, missingCache(gomock.NewController(t)), NewStatsStore())
+
+	result, code, err := forward.HandleCall(context.Background(), "/demo.v1.UserService/GetUser", reqBytes, "checkout", nil, "recorded")
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	method := uc.descriptors.FindMethod("/demo.v1.UserService/GetUser")
+	assert.Equal(t, "user u-1", result.ProtoReflect().Get(method.Output().Fields().ByName("name")).String())
+}
+
+func TestGRPCProxy_ForwardStream(t *testing.T) {
+	uc, _, _ := newTestProxyUC(t, true)
+
+	stream := &fakeStream{in: [][]byte{getUserRequest(t, "u-1"), getUserRequest(t, "u-2")}}
+	code, err := uc.ForwardStream(context.Background(), "/demo.v1.UserService/WatchUsers",
+		metadata.Pairs("x-feature-name", "checkout"), stream)
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, []string{"done"}, stream.trailer.Get("x-watched"))
+
+	// The method has no descriptor, so the bytes come back untouched.
+	method := compileTestService(t).Methods().ByName("GetUser")
+	var names []string
+	for _, out := range stream.out {
+		b, err := proto.Marshal(out)
+		require.NoError(t, err)
+		resp := dynamicpb.NewMessage(method.Output())
+		require.NoError(t, proto.Unmarshal(b, resp))
+		names = append(names, resp.Get(method.Output().Fields().ByName("name")).String())
+	}
+	assert.Equal(t, []string{"user u-1", "user u-2"}, names)
+
+	stream = &fakeStream{in: [][]byte{getUserRequest(t, "u-1"), getUserRequest(t, "none")}}
+	code, err = uc.ForwardStream(context.Background(), "/demo.v1.UserService/WatchUsers", nil, stream)
+	assert.Equal(t, codes.NotFound, code)
+	assert.Equal(t, "no such user", status.Convert(err).Message())
+	assert.Len(t, stream.out, 1)
+}
+
+func TestGRPCProxy_UpstreamError(t *testing.T) {
+	uc, _, _ := newTestProxyUC(t, false)
+
+	method := compileTestService(t).Methods().ByName("GetUser")
+	msg := dynamicpb.NewMessage(method.Input())
+	msg.Set(method.Input().Fields().ByName("user_id"), protoreflect.ValueOfString("none"))
+	reqBytes, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	stream := &fakeStream{}
+	code, err := uc.Forward(context.Background(), "/demo.v1.UserService/GetUser", reqBytes, nil, stream)
+	assert.Equal(t, codes.NotFound, code)
+	assert.Equal(t, "no such user", status.Convert(err).Message())
+	assert.Empty(t, stream.out)
+}
+
+func TestGRPCProxy_InactiveProxy(t *testing.T) {
+	uc, _, _ := newTestProxyUC(t, false)
+	assert.NotNil(t, uc.Find("demo.v1.UserService"))
+	assert.Nil(t, uc.Find("demo.v1.Other"))
+
+	uc.proxies["demo.v1.UserService"].IsActive = false
+	assert.Nil(t, uc.Find("demo.v1.UserService"))
+}
+
+func TestGRPCProxy_RetargetAndDeleteCloseConnections(t *testing.T) {
+	uc, _, _ := newTestProxyUC(t, false)
+	uc.ensureLoaded()
+	proxyRepo := mockrepo.NewMockIGRPCProxyRepository(gomock.NewController(t))
+	uc.proxyRepo = proxyRepo
+	proxyRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	proxyRepo.EXPECT().DeleteByServiceName(gomock.Any(), "demo.v1.UserService").Return(nil)
+
+	first, err := uc.conn(uc.Find("demo.v1.UserService"))
+	require.NoError(t, err)
+	require.Len(t, uc.conns, 1)
+
+	// Saving the same upstream keeps the connection.
+	same := *uc.Find("demo.v1.UserService")
+	same.Record = true
+	require.NoError(t, uc.Save(context.Background(), &same))
+	assert.Len(t, uc.conns, 1)
+
+	moved := same
+	moved.Upstream = "passthrough:///other"
+	require.NoError(t, uc.Save(context.Background(), &moved))
+	assert.Empty(t, uc.conns, "the old upstream is closed")
+	assert.Equal(t, connectivity.Shutdown, first.GetState())
+
+	second, err := uc.conn(uc.Find("demo.v1.UserService"))
+	require.NoError(t, err)
+	require.NoError(t, uc.Delete(context.Background(), "demo.v1.UserService"))
+	assert.Empty(t, uc.conns)
+	assert.Equal(t, connectivity.Shutdown, second.GetState())
+}
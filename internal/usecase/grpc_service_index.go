@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+// GRPCIndexedMethod is one method advertised through reflection.
+type GRPCIndexedMethod struct {
+	Feature    string
+	Service    string
+	Method     string
+	StreamType string
+}
+
+// GRPCServiceIndex keeps the service and method names of the gRPC mocks in
+// memory so reflection requests do not scan the mock collection.
+//
+// The index is loaded lazily, updated by the admin handlers of this instance
+// and fully reloaded by the refresh worker so edits made through other
+// instances show up as well.
+type GRPCServiceIndex struct {
+	repo repository.IGRPCMockAPIRepository
+
+	mu     sync.RWMutex
+	loaded bool
+	mocks  map[primitive.ObjectID]GRPCIndexedMethod
+	cancel context.CancelFunc
+}
+
+func NewGRPCServiceIndex(repo repository.IGRPCMockAPIRepository) *GRPCServiceIndex {
+	return &GRPCServiceIndex{
+		repo:  repo,
+		mocks: make(map[primitive.ObjectID]GRPCIndexedMethod),
+	}
+}
+
+// Refresh replaces the index with the content of the database.
+func (_self *GRPCServiceIndex) Refresh(ctx context.Context) error {
+	all, err := _self.repo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	mocks := make(map[primitive.ObjectID]GRPCIndexedMethod, len(all))
+	for i := range all {
+		mocks[all[i].ID] = indexedMethod(&all[i])
+	}
+	_self.mu.Lock()
+	_self.mocks = mocks
+	_self.loaded = true
+	_self.mu.Unlock()
+	return nil
+}
+
+// Put adds or replaces the entry of a created or updated mock. Inactive
+// mocks are dropped, matching what Refresh loads.
+func (_self *GRPCServiceIndex) Put(m *domain.GRPCMockAPI) {
+	if _self == nil || m == nil {
+		return
+	}
+	_self.mu.Lock()
+	if m.IsActive {
+		_self.mocks[m.ID] = indexedMethod(m)
+	} else {
+		delete(_self.mocks, m.ID)
+	}
+	_self.mu.Unlock()
+}
+
+// Remove drops the entry of a deleted mock.
+func (_self *GRPCServiceIndex) Remove(id primitive.ObjectID) {
+	if _self == nil {
+		return
+	}
+	_self.mu.Lock()
+	delete(_self.mocks, id)
+	_self.mu.Unlock()
+}
+
+// ServiceNames returns the sorted names of the services that have mocks.
+func (_self *GRPCServiceIndex) ServiceNames() []string {
+	if _self == nil {
+		return nil
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	seen := make(map[string]bool)
+	for _, m := range _self.mocks {
+		seen[m.Service] = true
+	}
+	_self.mu.RUnlock()
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Methods returns the methods of a service sorted by name. When several mocks
+// share a method, the streaming type of the oldest one wins.
+func (_self *GRPCServiceIndex) Methods(service string) []GRPCIndexedMethod {
+	if _self == nil {
+		return nil
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	byMethod := make(map[string]GRPCIndexedMethod)
+	oldest := make(map[string]primitive.ObjectID)
+	for id, m := range _self.mocks {
+		if m.Service != service {
+			continue
+		}
+		if prev, ok := oldest[m.Method]; ok && prev.Hex() < id.Hex() {
+			continue
+		}
+		oldest[m.Method] = id
+		byMethod[m.Method] = m
+	}
+	_self.mu.RUnlock()
+	out := make([]GRPCIndexedMethod, 0, len(byMethod))
+	for _, m := range byMethod {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Method < out[j].Method })
+	return out
+}
+
+// HasMethod reports whether an active mock serves the method.
+func (_self *GRPCServiceIndex) HasMethod(service, method string) bool {
+	if _self == nil {
+		return false
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	for _, m := range _self.mocks {
+		if m.Service == service && m.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFeature reports whether a feature has an active gRPC mock.
+func (_self *GRPCServiceIndex) HasFeature(feature string) bool {
+	if _self == nil || feature == "" {
+		return false
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	for _, m := range _self.mocks {
+		if m.Feature == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// MayStream reports whether a method may have stream mocks, so calls of
+// other methods are served as unary without listing their mocks. Without an
+// index every method may stream.
+func (_self *GRPCServiceIndex) MayStream(service, method string) bool {
+	if _self == nil {
+		return true
+	}
+	_self.ensureLoaded()
+	_self.mu.RLock()
+	defer _self.mu.RUnlock()
+	for _, m := range _self.mocks {
+		if m.Service == service && m.Method == method && m.StreamType != "" && m.StreamType != domain.GRPCStreamUnary {
+			return true
+		}
+	}
+	return false
+}
+
+func (_self *GRPCServiceIndex) ensureLoaded() {
+	_self.mu.RLock()
+	loaded := _self.loaded
+	_self.mu.RUnlock()
+	if loaded {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := _self.Refresh(ctx); err != nil {
+		slog.Warn("load gRPC service index", "error", err)
+	}
+}
+
+// StartRefreshWorker reloads the index every interval until ctx is done or
+// StopRefreshWorker is called. A non-positive interval disables the worker.
+func (_self *GRPCServiceIndex) StartRefreshWorker(ctx context.Context, interval time.Duration) {
+	if _self == nil || interval <= 0 {
+		return
+	}
+	workerCtx, cancel := context.WithCancel(ctx)
+	_self.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		slog.Info("gRPC service index refresh worker started", "interval", interval)
+
+		for {
+			select {
+			case <-ticker.C:
+				refreshCtx, cancel := context.WithTimeout(workerCtx, 5*time.Second)
+				if err := _self.Refresh(refreshCtx); err != nil {
+					slog.Warn("refresh gRPC service index", "error", err)
+				}
+				cancel()
+			case <-workerCtx.Done():
+				slog.Info("gRPC service index refresh worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// StopRefreshWorker stops the refresh worker.
+func (_self *GRPCServiceIndex) StopRefreshWorker() {
+	if _self == nil || _self.cancel == nil {
+		return
+	}
+	_self.cancel()
+}
+
+func indexedMethod(m *domain.GRPCMockAPI) GRPCIndexedMethod {
+	return GRPCIndexedMethod{
+		Feature:    m.FeatureName,
+		Service:    m.ServiceName,
+		Method:     m.MethodName,
+		StreamType: m.StreamType,
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	mockrepo "github.com/namnv2496/mocktool/mocks/repository"
+)
+
+func TestGRPCServiceIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
+	index := NewGRPCServiceIndex(repo)
+
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	repo.EXPECT().ListAll(gomock.Any()).Return([]domain.GRPCMockAPI{
+		{ID: second, ServiceName: "demo.Svc", MethodName: "Get"},
+		{ID: first, ServiceName: "demo.Svc", MethodName: "Get", StreamType: domain.GRPCStreamServer},
+		{ID: primitive.NewObjectID(), FeatureName: "checkout", ServiceName: "demo.Other", MethodName: "Ping"},
+	}, nil).Times(1)
+
+	assert.Equal(t, []string{"demo.Other", "demo.Svc"}, index.ServiceNames())
+	assert.True(t, index.HasFeature("checkout"))
+	assert.False(t, index.HasFeature("payments"))
+	assert.Equal(t, []GRPCIndexedMethod{
+		{Service: "demo.Svc", Method: "Get", StreamType: domain.GRPCStreamServer},
+	}, index.Methods("demo.Svc"), "the oldest mock decides the streaming type")
+
+	// Admin edits are applied without going back to the database.
+	added := &domain.GRPCMockAPI{ID: primitive.NewObjectID(), ServiceName: "demo.Svc", MethodName: "Chat", StreamType: domain.GRPCStreamBidi, IsActive: true}
+	index.Put(added)
+	index.Put(&domain.GRPCMockAPI{ID: primitive.NewObjectID(), ServiceName: "demo.Svc", MethodName: "Disabled"})
+	index.Remove(first)
+	index.Remove(second)
+
+	assert.Equal(t, []GRPCIndexedMethod{
+		{Service: "demo.Svc", Method: "Chat", StreamType: domain.GRPCStreamBidi},
+	}, index.Methods("demo.Svc"))
+
+	added.IsActive = false
+	index.Put(added)
+	assert.Equal(t, []string{"demo.Other"}, index.ServiceNames())
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails" // google.rpc.BadRequest, ErrorInfo, RetryInfo, ...
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	_ "github.com/namnv2496/mocktool/pkg/generated/github.com/namnv/mockTool/pkg/errorcustome" // error.ErrorDetail
+)
+
+const defaultMockStatusMessage = "mock status"
+
+// BuildGRPCStatus builds the status returned by a mock: code, message and
+// detail messages. Detail types are looked up among the types linked into the
+// binary (google.rpc.* and error.ErrorDetail) and the uploaded descriptors.
+func BuildGRPCStatus(
+	descriptors *GRPCDescriptorRegistry,
+	code codes.Code,
+	message string,
+	details []domain.GRPCStatusDetail,
+) (*status.Status, error) {
+	if message == "" {
+		message = defaultMockStatusMessage
+	}
+	st := status.New(code, message)
+	if len(details) == 0 {
+		return st, nil
+	}
+	if code == codes.OK {
+		return nil, fmt.Errorf("details require a non-OK status code")
+	}
+
+	anys := make([]*anypb.Any, 0, len(details))
+	for i, d := range details {
+		msg, err := newDetailMessage(descriptors, d.Type)
+		if err != nil {
+			return nil, fmt.Errorf("details[%d]: %w", i, err)
+		}
+		if len(d.Value) > 0 {
+			jsonBytes, err := bson.MarshalExtJSON(d.Value, false, false)
+			if err != nil {
+				return nil, fmt.Errorf("details[%d]: %w", i, err)
+			}
+			if err := protojson.Unmarshal(jsonBytes, msg); err != nil {
+				return nil, fmt.Errorf("details[%d] does not match %s: %w", i, d.Type, err)
+			}
+		}
+		a, err := anypb.New(msg)
+		if err != nil {
+			return nil, fmt.Errorf("details[%d]: %w", i, err)
+		}
+		anys = append(anys, a)
+	}
+
+	// status.WithDetails only accepts protoadapt messages; build the proto
+	// directly so dynamic messages from uploaded descriptors work too.
+	p := st.Proto()
+	p.Details = anys
+	return status.FromProto(p), nil
+}
+
+func newDetailMessage(descriptors *GRPCDescriptorRegistry, typeName string) (proto.Message, error) {
+	name := protoreflect.FullName(strings.TrimPrefix(typeName, "type.googleapis.com/"))
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(name); err == nil {
+		return mt.New().Interface(), nil
+	}
+	if desc, err := descriptors.FindDescriptorByName(name); err == nil {
+		if md, ok := desc.(protoreflect.MessageDescriptor); ok {
+			return dynamicpb.NewMessage(md), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown detail type %q", typeName)
+}
+
+// mockStatus returns the status error of a failing mock, or OK.
+func (_self *GRPCForwardUC) mockStatus(mock *domain.GRPCMockAPI) (codes.Code, error) {
+	grpcCode := codes.Code(mock.StatusCode)
+	if grpcCode == codes.OK {
+		return codes.OK, nil
+	}
+	st, err := BuildGRPCStatus(_self.descriptors, grpcCode, mock.StatusMessage, mock.Details)
+	if err != nil {
+		// Keep the configured code; the broken details are reported in the message.
+		return grpcCode, status.Errorf(grpcCode, "%s (invalid details: %v)", mock.StatusMessage, err)
+	}
+	return grpcCode, st.Err()
+}
+
+// applyResponseMetadata sets the mock headers and trailers on the stream.
+func applyResponseMetadata(stream GRPCStream, mock *domain.GRPCMockAPI) error {
+	if mock == nil {
+		return nil
+	}
+	if len(mock.Headers) > 0 {
+		if err := stream.SetHeader(toMetadata(mock.Headers)); err != nil {
+			return err
+		}
+	}
+	if len(mock.Trailers) > 0 {
+		stream.SetTrailer(toMetadata(mock.Trailers))
+	}
+	return nil
+}
+
+// toMetadata converts stored key/values to gRPC metadata. Values of binary
+// keys (ending in -bin) are stored base64 encoded.
+func toMetadata(m map[string]string) metadata.MD {
+	md := metadata.MD{}
+	for k, v := range m {
+		if strings.HasSuffix(k, "-bin") {
+			if b, err := base64.StdEncoding.DecodeString(v); err == nil {
+				v = string(b)
+			}
+		}
+		md.Append(k, v)
+	}
+	return md
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	pb "github.com/namnv2496/mocktool/pkg/generated/github.com/namnv/mockTool/pkg/errorcustome"
+)
+
+func TestBuildGRPCStatus_Details(t *testing.T) {
+	st, err := BuildGRPCStatus(nil, codes.ResourceExhausted, "slow down", []domain.GRPCStatusDetail{
+		{Type: "google.rpc.RetryInfo", Value: outputBSON(t, map[string]any{"retry_delay": "5s"})},
+		{Type: "google.rpc.ErrorInfo", Value: outputBSON(t, map[string]any{"reason": "QUOTA", "domain": "mock"})},
+		{Type: "error.ErrorDetail", Value: outputBSON(t, map[string]any{"error_code": "ERR.429", "metadata": map[string]any{"k": "v"}})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, "slow down", st.Message())
+
+	details := st.Details()
+	require.Len(t, details, 3)
+	assert.Equal(t, int64(5), details[0].(*errdetails.RetryInfo).GetRetryDelay().GetSeconds())
+	assert.Equal(t, "QUOTA", details[1].(*errdetails.ErrorInfo).GetReason())
+	assert.Equal(t, "ERR.429", details[2].(*pb.ErrorDetail).GetErrorCode())
+}
+
+func TestBuildGRPCStatus_Invalid(t *testing.T) {
+	_, err := BuildGRPCStatus(nil, codes.Internal, "", []domain.GRPCStatusDetail{{Type: "does.not.Exist"}})
+	assert.Error(t, err)
+
+	_, err = BuildGRPCStatus(nil, codes.Internal, "", []domain.GRPCStatusDetail{
+		{Type: "google.rpc.ErrorInfo", Value: outputBSON(t, map[string]any{"unknown_field": 1})},
+	})
+	assert.Error(t, err)
+
+	_, err = BuildGRPCStatus(nil, codes.OK, "", []domain.GRPCStatusDetail{{Type: "google.rpc.ErrorInfo"}})
+	assert.Error(t, err)
+}
+
+func TestHandleStream_MetadataAndRichStatus(t *testing.T) {
+	uc, grpcRepo, scenarioRepo, _ := newGRPCForwardUC(t)
+	scenarioRepo.EXPECT().
+		FindByFeatureNameAndName(gomock.Any(), testFeatureName, "s1").
+		Return(&domain.Scenario{ID: primitive.NewObjectID(), FeatureName: testFeatureName, Name: "s1"}, nil)
+
+	mock := &domain.GRPCMockAPI{
+		StatusCode:    int32(codes.InvalidArgument),
+		StatusMessage: "bad email",
+		Headers:       map[string]string{"x-request-id": "r-1"},
+		Trailers:      map[string]string{"x-ratelimit-remaining": "0"},
+		Details: []domain.GRPCStatusDetail{{
+			Type: "google.rpc.BadRequest",
+			Value: outputBSON(t, map[string]any{"field_violations": []any{
+				map[string]any{"field": "email", "description": "invalid"},
+			}}),
+		}},
+	}
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{*mock}, nil)
+
+	stream := &fakeStream{in: [][]byte{nil}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Create", stream, testFeatureName, nil, "s1")
+
+	assert.Equal(t, codes.InvalidArgument, code)
+	st := status.Convert(err)
+	assert.Equal(t, "bad email", st.Message())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "email", st.Details()[0].(*errdetails.BadRequest).GetFieldViolations()[0].GetField())
+	assert.Equal(t, []string{"r-1"}, stream.header.Get("x-request-id"))
+	assert.Equal(t, []string{"0"}, stream.trailer.Get("x-ratelimit-remaining"))
+	assert.Empty(t, stream.out)
+}
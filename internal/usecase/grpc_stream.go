@@ -0,0 +1,426 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/pkg/observability"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+// GRPCStream is the part of grpc.ServerStream used by the mock server, with
+// requests still in wire format and responses as messages.
+type GRPCStream interface {
+	Recv() ([]byte, error)
+	Send(proto.Message) error
+	SetHeader(metadata.MD) error
+	SetTrailer(metadata.MD)
+}
+
+// HandleStream serves any kind of RPC. The kind comes from the uploaded
+// descriptor when there is one, otherwise from the stream_type of the
+// method's mocks; methods without streaming mocks in the service index are
+// served as unary through the cached lookup.
+func (_self *GRPCForwardUC) HandleStream(
+	ctx context.Context,
+	fullMethod string,
+	stream GRPCStream,
+	featureName string,
+	accountId *string,
+	scenarioOverride string,
+) (codes.Code, error) {
+	scenario, grpcCode, err := _self.resolveScenario(ctx, featureName, accountId, scenarioOverride)
+	if err != nil {
+		return grpcCode, err
+	}
+	serviceName, methodName := utils.SplitGRPCMethod(fullMethod)
+	method := _self.descriptors.FindMethod(fullMethod)
+
+	var mocks []domain.GRPCMockAPI
+	kind := descriptorStreamType(method)
+	mayStream := kind != domain.GRPCStreamUnary || (method == nil && _self.serviceIndex.MayStream(serviceName, methodName))
+	if mayStream {
+		mocks, err = _self.listInheritedMocks(ctx, featureName, scenario, serviceName, methodName)
+		if err != nil {
+			return codes.Internal, status.Errorf(codes.Internal, "lookup: %v", err)
+		}
+		if method == nil {
+			kind = mockStreamType(mocks)
+		}
+	}
+
+	switch kind {
+	case domain.GRPCStreamServer:
+		return _self.serveServerStream(ctx, method, stream, filterStreamType(mocks, kind))
+	case domain.GRPCStreamClient:
+		return _self.serveClientStream(ctx, method, stream, filterStreamType(mocks, kind))
+	case domain.GRPCStreamBidi:
+		return _self.serveBidiStream(ctx, method, stream, filterStreamType(mocks, kind))
+	}
+
+	reqBytes, err := stream.Recv()
+	if err != nil {
+		return codes.Internal, status.Errorf(codes.Internal, "receive request: %v", err)
+	}
+	result, mock, grpcCode, err := _self.handleUnary(ctx, fullMethod, reqBytes, featureName, accountId, scenario)
+	if err := applyResponseMetadata(stream, mock); err != nil {
+		return codes.Internal, err
+	}
+	if err != nil {
+		return grpcCode, err
+	}
+	if err := stream.Send(result); err != nil {
+		return codes.Unavailable, err
+	}
+	return codes.OK, nil
+}
+
+// serveServerStream reads the single request, picks the matching mock and
+// sends its scripted responses, then ends with the mock status code.
+func (_self *GRPCForwardUC) serveServerStream(
+	ctx context.Context,
+	method protoreflect.MethodDescriptor,
+	stream GRPCStream,
+	mocks []domain.GRPCMockAPI,
+) (codes.Code, error) {
+	reqBytes, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		return codes.Internal, status.Errorf(codes.Internal, "receive request: %v", err)
+	}
+	req, err := decodeRequest(method, reqBytes)
+	if err != nil {
+		return codes.InvalidArgument, status.Errorf(codes.InvalidArgument, "decode request: %v", err)
+	}
+	mock := pickGRPCMock(mocks, hashTypedRequest(req), req)
+	if mock == nil {
+		observability.SetGRPCMatch(ctx, observability.GRPCMatchNotFound)
+		return codes.NotFound, status.Error(codes.NotFound, "stream mock not found")
+	}
+	observability.SetGRPCMatch(ctx, observability.GRPCMatchMock)
+	if err := waitMockLatency(ctx, mock); err != nil {
+		return status.Code(err), err
+	}
+	if err := applyResponseMetadata(stream, mock); err != nil {
+		return codes.Internal, err
+	}
+	if code, err := sendScripted(ctx, method, stream, mock.Responses); err != nil {
+		return code, err
+	}
+	return _self.mockStatus(mock)
+}
+
+// serveClientStream reads every request message, aggregates them with the
+// rule of each candidate mock and replies once with the first mock whose
+// input matches the aggregate (or the match-all mock).
+func (_self *GRPCForwardUC) serveClientStream(
+	ctx context.Context,
+	method protoreflect.MethodDescriptor,
+	stream GRPCStream,
+	mocks []domain.GRPCMockAPI,
+) (codes.Code, error) {
+	var received []map[string]any
+	for {
+		b, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Code(err), err
+		}
+		req, err := decodeRequest(method, b)
+		if err != nil {
+			return codes.InvalidArgument, status.Errorf(codes.InvalidArgument, "decode request: %v", err)
+		}
+		received = append(received, req)
+	}
+
+	var mock *domain.GRPCMockAPI
+	for i := range mocks {
+		if mocks[i].HashInput != "" && mocks[i].HashInput == hashTypedRequest(aggregateMessages(mocks[i].Aggregate, received)) {
+			mock = &mocks[i]
+			break
+		}
+	}
+	if mock == nil {
+		// Matchers are evaluated against the aggregate of each mock
+		var matched []*domain.GRPCMockAPI
+		for i := range mocks {
+			if len(mocks[i].Matchers) > 0 && matchGRPCFields(mocks[i].Matchers, aggregateMessages(mocks[i].Aggregate, received)) {
+				matched = append(matched, &mocks[i])
+			}
+		}
+		mock = mostSpecificGRPCMock(matched)
+	}
+	if mock == nil {
+		mock = matchAllGRPCMock(mocks)
+	}
+	if mock == nil {
+		observability.SetGRPCMatch(ctx, observability.GRPCMatchNotFound)
+		return codes.NotFound, status.Error(codes.NotFound, "stream mock not found")
+	}
+	observability.SetGRPCMatch(ctx, observability.GRPCMatchMock)
+	if err := waitMockLatency(ctx, mock); err != nil {
+		return status.Code(err), err
+	}
+	if err := applyResponseMetadata(stream, mock); err != nil {
+		return codes.Internal, err
+	}
+	if code, err := _self.mockStatus(mock); err != nil {
+		return code, err
+	}
+	result, err := encodeOutput(method, mock.Output)
+	if err != nil {
+		return codes.Internal, status.Errorf(codes.Internal, "transcode output: %v", err)
+	}
+	if err := stream.Send(result); err != nil {
+		return codes.Unavailable, err
+	}
+	return codes.OK, nil
+}
+
+// serveBidiStream answers every incoming message with the responses of the
+// rule it matches, across all bidi mocks of the method, until the client
+// closes its side. Headers and latency come from the mock answering the first
+// message; the stream ends with the status of the last mock that answered.
+func (_self *GRPCForwardUC) serveBidiStream(
+	ctx context.Context,
+	method protoreflect.MethodDescriptor,
+	stream GRPCStream,
+	mocks []domain.GRPCMockAPI,
+) (codes.Code, error) {
+	if len(mocks) == 0 {
+		observability.SetGRPCMatch(ctx, observability.GRPCMatchNotFound)
+		return codes.NotFound, status.Error(codes.NotFound, "stream mock not found")
+	}
+	var answered *domain.GRPCMockAPI
+	for {
+		b, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Code(err), err
+		}
+		req, err := decodeRequest(method, b)
+		if err != nil {
+			return codes.InvalidArgument, status.Errorf(codes.InvalidArgument, "decode request: %v", err)
+		}
+		mock, rule := pickBidiRule(mocks, req)
+		if rule == nil {
+			if answered == nil {
+				observability.SetGRPCMatch(ctx, observability.GRPCMatchNotFound)
+			}
+			return codes.NotFound, status.Error(codes.NotFound, "no bidi rule matches the message")
+		}
+		if answered == nil {
+			observability.SetGRPCMatch(ctx, observability.GRPCMatchMock)
+			if err := waitMockLatency(ctx, mock); err != nil {
+				return status.Code(err), err
+			}
+			if err := applyResponseMetadata(stream, mock); err != nil {
+				return codes.Internal, err
+			}
+		}
+		answered = mock
+		if code, err := sendScripted(ctx, method, stream, rule.Responses); err != nil {
+			return code, err
+		}
+	}
+	if answered == nil {
+		observability.SetGRPCMatch(ctx, observability.GRPCMatchMock)
+		answered = &mocks[0]
+		if err := applyResponseMetadata(stream, answered); err != nil {
+			return codes.Internal, err
+		}
+	}
+	return _self.mockStatus(answered)
+}
+
+func sendScripted(ctx context.Context, method protoreflect.MethodDescriptor, stream GRPCStream, msgs []domain.GRPCStreamMessage) (codes.Code, error) {
+	for _, m := range msgs {
+		if err := sleepCtx(ctx, m.Delay); err != nil {
+			return status.Code(err), err
+		}
+		out, err := encodeOutput(method, m.Output)
+		if err != nil {
+			return codes.Internal, status.Errorf(codes.Internal, "transcode output: %v", err)
+		}
+		if err := stream.Send(out); err != nil {
+			return codes.Unavailable, err
+		}
+	}
+	return codes.OK, nil
+}
+
+// waitMockLatency applies the latency of a mock. A hanging mock waits until
+// the call deadline passes or the client cancels; a latency longer than the
+// deadline likewise ends with DEADLINE_EXCEEDED when the deadline passes.
+func waitMockLatency(ctx context.Context, mock *domain.GRPCMockAPI) error {
+	if mock.Hang {
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return sleepCtx(ctx, mock.Latency)
+}
+
+// sleepCtx waits ms milliseconds unless the call is cancelled first.
+func sleepCtx(ctx context.Context, ms int64) error {
+	if ms <= 0 {
+		return nil
+	}
+	t := time.NewTimer(time.Duration(ms) * time.Millisecond)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func descriptorStreamType(method protoreflect.MethodDescriptor) string {
+	if method == nil {
+		return domain.GRPCStreamUnary
+	}
+	switch {
+	case method.IsStreamingClient() && method.IsStreamingServer():
+		return domain.GRPCStreamBidi
+	case method.IsStreamingClient():
+		return domain.GRPCStreamClient
+	case method.IsStreamingServer():
+		return domain.GRPCStreamServer
+	}
+	return domain.GRPCStreamUnary
+}
+
+func mockStreamType(mocks []domain.GRPCMockAPI) string {
+	for _, m := range mocks {
+		if m.StreamType != domain.GRPCStreamUnary {
+			return m.StreamType
+		}
+	}
+	return domain.GRPCStreamUnary
+}
+
+func filterStreamType(mocks []domain.GRPCMockAPI, kind string) []domain.GRPCMockAPI {
+	out := make([]domain.GRPCMockAPI, 0, len(mocks))
+	for _, m := range mocks {
+		if m.StreamType == kind {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// pickBidiRule finds the rule answering one message of a bidi stream: a rule
+// whose input equals the message in any mock, else the match-all rule of the
+// most specific mock whose matchers accept the message, else the match-all
+// rule of a mock without matchers.
+func pickBidiRule(mocks []domain.GRPCMockAPI, req map[string]any) (*domain.GRPCMockAPI, *domain.GRPCBidiRule) {
+	if hash := hashTypedRequest(req); hash != "" {
+		for i := range mocks {
+			for j := range mocks[i].BidiRules {
+				if mocks[i].BidiRules[j].HashInput == hash {
+					return &mocks[i], &mocks[i].BidiRules[j]
+				}
+			}
+		}
+	}
+	var matched []*domain.GRPCMockAPI
+	for i := range mocks {
+		if len(mocks[i].Matchers) > 0 && matchGRPCFields(mocks[i].Matchers, req) && bidiFallback(&mocks[i]) != nil {
+			matched = append(matched, &mocks[i])
+		}
+	}
+	if m := mostSpecificGRPCMock(matched); m != nil {
+		return m, bidiFallback(m)
+	}
+	for i := range mocks {
+		if len(mocks[i].Matchers) == 0 {
+			if rule := bidiFallback(&mocks[i]); rule != nil {
+				return &mocks[i], rule
+			}
+		}
+	}
+	return nil, nil
+}
+
+// bidiFallback returns the first rule of a mock without input.
+func bidiFallback(mock *domain.GRPCMockAPI) *domain.GRPCBidiRule {
+	for i := range mock.BidiRules {
+		if mock.BidiRules[i].HashInput == "" {
+			return &mock.BidiRules[i]
+		}
+	}
+	return nil
+}
+
+// aggregateMessages folds the messages of a client stream into the value
+// matched against the mock input.
+func aggregateMessages(rule string, msgs []map[string]any) map[string]any {
+	switch rule {
+	case domain.GRPCAggregateFirst:
+		if len(msgs) == 0 {
+			return nil
+		}
+		return msgs[0]
+	case domain.GRPCAggregateMerge:
+		merged := map[string]any{}
+		for _, m := range msgs {
+			for k, v := range m {
+				merged[k] = v
+			}
+		}
+		return merged
+	case domain.GRPCAggregateAll:
+		all := make([]any, 0, len(msgs))
+		for _, m := range msgs {
+			all = append(all, m)
+		}
+		return map[string]any{"messages": all}
+	case domain.GRPCAggregateCount:
+		return map[string]any{"count": len(msgs)}
+	default:
+		if len(msgs) == 0 {
+			return nil
+		}
+		return msgs[len(msgs)-1]
+	}
+}
+
+// decodeRequest decodes one request message as the uploaded input type, or as
+// google.protobuf.Struct when the method has no descriptor.
+func decodeRequest(method protoreflect.MethodDescriptor, b []byte) (map[string]any, error) {
+	if method != nil {
+		return decodeTypedRequest(method.Input(), b)
+	}
+	if len(b) == 0 {
+		return map[string]any{}, nil
+	}
+	var s structpb.Struct
+	if err := proto.Unmarshal(b, &s); err != nil {
+		return nil, errors.New("request is not a google.protobuf.Struct")
+	}
+	return s.AsMap(), nil
+}
+
+func encodeOutput(method protoreflect.MethodDescriptor, raw bson.Raw) (proto.Message, error) {
+	if method != nil {
+		return encodeTypedResponse(method.Output(), raw)
+	}
+	if len(raw) == 0 {
+		return &structpb.Struct{}, nil
+	}
+	return bsonRawToStruct(raw)
+}
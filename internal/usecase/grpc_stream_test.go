@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+type fakeStream struct {
+	in      [][]byte
+	out     []proto.Message
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+func (s *fakeStream) Recv() ([]byte, error) {
+	if len(s.in) == 0 {
+		return nil, io.EOF
+	}
+	b := s.in[0]
+	s.in = s.in[1:]
+	return b, nil
+}
+
+func (s *fakeStream) Send(m proto.Message) error {
+	s.out = append(s.out, m)
+	return nil
+}
+
+func (s *fakeStream) names() []string {
+	var names []string
+	for _, m := range s.out {
+		names = append(names, m.(*structpb.Struct).Fields["name"].GetStringValue())
+	}
+	return names
+}
+
+func TestHandleStream_Unary(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "s1", "svc", "Get").
+		Return([]domain.GRPCMockAPI{{Output: outputBSON(t, map[string]any{"name": "Alice"})}}, nil).
+		Times(2)
+
+	stream := &fakeStream{in: [][]byte{nil}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Get", stream, testFeatureName, nil, "s1")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, []string{"Alice"}, stream.names())
+}
+
+func TestHandleStream_UnaryWithoutStreamMocksSkipsListing(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+	index := NewGRPCServiceIndex(grpcRepo)
+	uc.(*GRPCForwardUC).serviceIndex = index
+
+	grpcRepo.EXPECT().ListAll(gomock.Any()).
+		Return([]domain.GRPCMockAPI{{ServiceName: "svc", MethodName: "Get", StreamType: domain.GRPCStreamUnary, IsActive: true}}, nil)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "s1", "svc", "Get").
+		Return([]domain.GRPCMockAPI{{Output: outputBSON(t, map[string]any{"name": "Alice"})}}, nil).
+		Times(1)
+
+	stream := &fakeStream{in: [][]byte{nil}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Get", stream, testFeatureName, nil, "s1")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, []string{"Alice"}, stream.names())
+}
+
+func TestHandleStream_ServerStreaming(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), testFeatureName, "s1", "svc", "Watch").
+		Return([]domain.GRPCMockAPI{{
+			StreamType: domain.GRPCStreamServer,
+			Responses: []domain.GRPCStreamMessage{
+				{Output: outputBSON(t, map[string]any{"name": "a"})},
+				{Output: outputBSON(t, map[string]any{"name": "b"}), Delay: 1},
+			},
+			StatusCode: int32(codes.Aborted),
+		}}, nil)
+
+	stream := &fakeStream{in: [][]byte{structBytes(t, map[string]any{"id": 1})}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Watch", stream, testFeatureName, nil, "s1")
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.Aborted, code, "status is returned after the scripted messages")
+	assert.Equal(t, []string{"a", "b"}, stream.names())
+}
+
+func TestHandleStream_ServerStreamingStopsOnCancel(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{{
+			StreamType: domain.GRPCStreamServer,
+			Responses:  []domain.GRPCStreamMessage{{Output: outputBSON(t, map[string]any{"name": "a"}), Delay: 10_000}},
+		}}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stream := &fakeStream{in: [][]byte{nil}}
+	code, err := uc.HandleStream(ctx, "/svc/Watch", stream, testFeatureName, nil, "s1")
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.DeadlineExceeded, code)
+	assert.Empty(t, stream.out)
+}
+
+func TestHandleStream_ClientStreamingAggregate(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	merged := outputBSON(t, map[string]any{"a": 1, "b": 2})
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{
+			{
+				StreamType: domain.GRPCStreamClient,
+				Output:     outputBSON(t, map[string]any{"name": "fallback"}),
+			},
+			{
+				StreamType: domain.GRPCStreamClient,
+				Aggregate:  domain.GRPCAggregateMerge,
+				HashInput:  utils.GenerateHashFromInput(merged),
+				Output:     outputBSON(t, map[string]any{"name": "merged"}),
+			},
+		}, nil)
+
+	stream := &fakeStream{in: [][]byte{
+		structBytes(t, map[string]any{"a": 1}),
+		structBytes(t, map[string]any{"b": 2}),
+	}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Upload", stream, testFeatureName, nil, "s1")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, []string{"merged"}, stream.names())
+}
+
+func TestHandleStream_Bidi(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	ping := outputBSON(t, map[string]any{"msg": "ping"})
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{{
+			StreamType: domain.GRPCStreamBidi,
+			BidiRules: []domain.GRPCBidiRule{
+				{
+					HashInput: utils.GenerateHashFromInput(ping),
+					Responses: []domain.GRPCStreamMessage{{Output: outputBSON(t, map[string]any{"name": "pong"})}},
+				},
+				{
+					Responses: []domain.GRPCStreamMessage{
+						{Output: outputBSON(t, map[string]any{"name": "echo-1"})},
+						{Output: outputBSON(t, map[string]any{"name": "echo-2"})},
+					},
+				},
+			},
+		}}, nil)
+
+	stream := &fakeStream{in: [][]byte{
+		structBytes(t, map[string]any{"msg": "ping"}),
+		structBytes(t, map[string]any{"msg": "other"}),
+	}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Chat", stream, testFeatureName, nil, "s1")
+
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, []string{"pong", "echo-1", "echo-2"}, stream.names())
+}
+
+func TestHandleStream_BidiMatchesEveryMessageAcrossMocks(t *testing.T) {
+	uc, grpcRepo, _, _ := newGRPCForwardUC(t)
+
+	reply := func(name string) []domain.GRPCStreamMessage {
+		return []domain.GRPCStreamMessage{{Output: outputBSON(t, map[string]any{"name": name})}}
+	}
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]domain.GRPCMockAPI{
+			{
+				StreamType: domain.GRPCStreamBidi,
+				BidiRules:  []domain.GRPCBidiRule{{Responses: reply("default")}},
+			},
+			{
+				StreamType: domain.GRPCStreamBidi,
+				Matchers:   []domain.GRPCFieldMatcher{{Path: "msg", Op: domain.GRPCMatchEquals, Value: "bye"}},
+				BidiRules:  []domain.GRPCBidiRule{{Responses: reply("goodbye")}},
+				StatusCode: int32(codes.Aborted),
+			},
+			{
+				StreamType: domain.GRPCStreamBidi,
+				BidiRules: []domain.GRPCBidiRule{{
+					HashInput: utils.GenerateHashFromInput(outputBSON(t, map[string]any{"msg": "ping"})),
+					Responses: reply("pong"),
+				}},
+			},
+		}, nil)
+
+	stream := &fakeStream{in: [][]byte{
+		structBytes(t, map[string]any{"msg": "hello"}),
+		structBytes(t, map[string]any{"msg": "ping"}),
+		structBytes(t, map[string]any{"msg": "bye"}),
+	}}
+	code, err := uc.HandleStream(context.Background(), "/svc/Chat", stream, testFeatureName, nil, "s1")
+
+	assert.Error(t, err)
+	assert.Equal(t, codes.Aborted, code, "the stream ends with the status of the last mock that answered")
+	assert.Equal(t, []string{"default", "pong", "goodbye"}, stream.names())
+}
+
+func TestAggregateMessages(t *testing.T) {
+	msgs := []map[string]any{{"a": 1.0, "b": 1.0}, {"b": 2.0}}
+
+	assert.Equal(t, map[string]any{"b": 2.0}, aggregateMessages("", msgs))
+	assert.Equal(t, msgs[0], aggregateMessages(domain.GRPCAggregateFirst, msgs))
+	assert.Equal(t, map[string]any{"a": 1.0, "b": 2.0}, aggregateMessages(domain.GRPCAggregateMerge, msgs))
+	assert.Equal(t, map[string]any{"count": 2}, aggregateMessages(domain.GRPCAggregateCount, msgs))
+	assert.Len(t, aggregateMessages(domain.GRPCAggregateAll, msgs)["messages"], 2)
+}
@@ -0,0 +1,540 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCHTTPRoute is one REST binding of a unary method, declared with a
+// google.api.http option.
+type GRPCHTTPRoute struct {
+	HTTPMethod   string
+	Pattern      string
+	FullMethod   string
+	Body         string // "", "*" or the request field receiving the body
+	ResponseBody string // "" or the response field sent as the body
+
+	method   protoreflect.MethodDescriptor
+	segments []routeSegment
+	verb     string
+	literals int
+}
+
+// routeSegment is one path segment of a route template.
+type routeSegment struct {
+	literal  string // empty for wildcards
+	wildcard string // "*" (one segment) or "**" (the rest of the path)
+	variable string // request field captured by the segment
+}
+
+// GRPCTranscoder serves the gRPC mocks over HTTP/JSON like grpc-gateway: the
+// request is built from the path variables, query parameters and body, then
+// answered by the same lookup as a gRPC call.
+//
+// Routes are rebuilt whenever the descriptor registry changes.
+type GRPCTranscoder struct {
+	descriptors *GRPCDescriptorRegistry
+	grpcForward IGRPCForwardUC
+
+	mu         sync.Mutex
+	built      bool
+	generation uint64
+	routes     []*GRPCHTTPRoute
+}
+
+func NewGRPCTranscoder(descriptors *GRPCDescriptorRegistry, grpcForward IGRPCForwardUC) *GRPCTranscoder {
+	return &GRPCTranscoder{
+		descriptors: descriptors,
+		grpcForward: grpcForward,
+	}
+}
+
+// Routes returns the REST routes of the uploaded descriptors, most literal
+// segments first.
+func (_self *GRPCTranscoder) Routes() []*GRPCHTTPRoute {
+	gen := _self.descriptors.Generation()
+	_self.mu.Lock()
+	defer _self.mu.Unlock()
+	if !_self.built || gen != _self.generation {
+		_self.routes = buildHTTPRoutes(_self.descriptors)
+		_self.generation = gen
+		_self.built = true
+	}
+	return _self.routes
+}
+
+// Matches reports whether req is bound to a route, so other paths can be left
+// to the router's plain 404.
+func (_self *GRPCTranscoder) Matches(req *http.Request) bool {
+	route, _ := _self.match(req)
+	return route != nil
+}
+
+// Transcode answers req from the gRPC mocks when it matches a route and
+// returns the HTTP status and JSON body. ok is false when no route matches.
+func (_self *GRPCTranscoder) Transcode(
+	ctx context.Context,
+	req *http.Request,
+	featureName string,
+	accountId *string,
+	scenario string,
+) (statusCode int, body []byte, ok bool) {
+	route, vars := _self.match(req)
+	if route == nil {
+		return 0, nil, false
+	}
+	reqBytes, err := route.buildRequest(req, vars)
+	if err != nil {
+		statusCode, body = transcodeError(status.Error(codes.InvalidArgument, err.Error()))
+		return statusCode, body, true
+	}
+	result, _, err := _self.grpcForward.HandleCall(ctx, route.FullMethod, reqBytes, featureName, accountId, scenario)
+	if err != nil {
+		statusCode, body = transcodeError(err)
+		return statusCode, body, true
+	}
+	if body, err = route.encodeResponse(result); err != nil {
+		statusCode, body = transcodeError(status.Errorf(codes.Internal, "marshal response: %v", err))
+		return statusCode, body, true
+	}
+	return http.StatusOK, body, true
+}
+
+func (_self *GRPCTranscoder) match(req *http.Request) (*GRPCHTTPRoute, map[string]string) {
+	parts := strings.Split(strings.TrimPrefix(req.URL.EscapedPath(), "/"), "/")
+	for i, p := range parts {
+		if unescaped, err := url.PathUnescape(p); err == nil {
+			parts[i] = unescaped
+		}
+	}
+	for _, route := range _self.Routes() {
+		if route.HTTPMethod != req.Method {
+			continue
+		}
+		if vars, ok := route.match(parts); ok {
+			return route, vars
+		}
+	}
+	return nil, nil
+}
+
+func buildHTTPRoutes(descriptors *GRPCDescriptorRegistry) []*GRPCHTTPRoute {
+	var routes []*GRPCHTTPRoute
+	for _, name := range descriptors.ServiceNames() {
+		svc := descriptors.FindService(name)
+		if svc == nil {
+			continue
+		}
+		for i := 0; i < svc.Methods().Len(); i++ {
+			method := svc.Methods().Get(i)
+			rule := methodHTTPRule(method)
+			if rule == nil || method.IsStreamingClient() || method.IsStreamingServer() {
+				continue
+			}
+			fullMethod := fmt.Sprintf("/%s/%s", svc.FullName(), method.Name())
+			for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+				route, err := newHTTPRoute(method, fullMethod, r)
+				if err != nil {
+					slog.Warn("skip google.api.http rule", "method", fullMethod, "error", err)
+					continue
+				}
+				routes = append(routes, route)
+			}
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].literals != routes[j].literals {
+			return routes[i].literals > routes[j].literals
+		}
+		return len(routes[i].segments) > len(routes[j].segments)
+	})
+	return routes
+}
+
+// methodHTTPRule returns the google.api.http option of a method. Options are
+// re-parsed so the extension resolves whatever way the descriptor was loaded.
+func methodHTTPRule(method protoreflect.MethodDescriptor) *annotations.HttpRule {
+	opts, ok := method.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+	raw, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+	parsed := &descriptorpb.MethodOptions{}
+	if err := proto.Unmarshal(raw, parsed); err != nil || !proto.HasExtension(parsed, annotations.E_Http) {
+		return nil
+	}
+	rule, _ := proto.GetExtension(parsed, annotations.E_Http).(*annotations.HttpRule)
+	return rule
+}
+
+func newHTTPRoute(method protoreflect.MethodDescriptor, fullMethod string, rule *annotations.HttpRule) (*GRPCHTTPRoute, error) {
+	var httpMethod, pattern string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		httpMethod, pattern = http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		httpMethod, pattern = http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		httpMethod, pattern = http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		httpMethod, pattern = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		httpMethod, pattern = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		httpMethod, pattern = p.Custom.GetKind(), p.Custom.GetPath()
+	default:
+		return nil, fmt.Errorf("rule has no pattern")
+	}
+
+	segments, verb, err := parseHTTPTemplate(pattern)
+	if err != nil {
+		return nil, err
+	}
+	route := &GRPCHTTPRoute{
+		HTTPMethod:   httpMethod,
+		Pattern:      pattern,
+		FullMethod:   fullMethod,
+		Body:         rule.GetBody(),
+		ResponseBody: rule.GetResponseBody(),
+		method:       method,
+		segments:     segments,
+		verb:         verb,
+	}
+	for _, seg := range segments {
+		if seg.literal != "" {
+			route.literals++
+		}
+		if seg.variable != "" && findFieldPath(method.Input(), seg.variable) == nil {
+			return nil, fmt.Errorf("unknown path variable %q", seg.variable)
+		}
+	}
+	if route.Body != "" && route.Body != "*" && findFieldPath(method.Input(), route.Body) == nil {
+		return nil, fmt.Errorf("unknown body field %q", route.Body)
+	}
+	if route.ResponseBody != "" && method.Output().Fields().ByName(protoreflect.Name(route.ResponseBody)) == nil {
+		return nil, fmt.Errorf("unknown response_body field %q", route.ResponseBody)
+	}
+	return route, nil
+}
+
+// parseHTTPTemplate parses a path template such as
+// /v1/{name=shelves/*}/books/{book_id}:publish.
+func parseHTTPTemplate(pattern string) ([]routeSegment, string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, "", fmt.Errorf("template %q must start with /", pattern)
+	}
+	s := pattern[1:]
+	verb := ""
+	if idx := strings.LastIndex(s, ":"); idx >= 0 && idx > strings.LastIndex(s, "}") && idx > strings.LastIndex(s, "/") {
+		s, verb = s[:idx], s[idx+1:]
+	}
+
+	var segments []routeSegment
+	addSegment := func(part, variable string) error {
+		switch {
+		case part == "*" || part == "**":
+			segments = append(segments, routeSegment{wildcard: part, variable: variable})
+		case part == "" || strings.ContainsAny(part, "{}=*"):
+			return fmt.Errorf("invalid segment %q in %q", part, pattern)
+		default:
+			segments = append(segments, routeSegment{literal: part, variable: variable})
+		}
+		return nil
+	}
+	for len(s) > 0 {
+		if s[0] == '{' {
+			end := strings.IndexByte(s, '}')
+			if end < 0 {
+				return nil, "", fmt.Errorf("unclosed variable in %q", pattern)
+			}
+			variable, sub, _ := strings.Cut(s[1:end], "=")
+			if sub == "" {
+				sub = "*"
+			}
+			for _, part := range strings.Split(sub, "/") {
+				if err := addSegment(part, variable); err != nil {
+					return nil, "", err
+				}
+			}
+			s = s[end+1:]
+		} else {
+			part := s
+			if end := strings.IndexByte(s, '/'); end >= 0 {
+				part = s[:end]
+			}
+			if err := addSegment(part, ""); err != nil {
+				return nil, "", err
+			}
+			s = s[len(part):]
+		}
+		if len(s) > 0 {
+			if s[0] != '/' {
+				return nil, "", fmt.Errorf("invalid template %q", pattern)
+			}
+			s = s[1:]
+		}
+	}
+	for i, seg := range segments {
+		if seg.wildcard == "**" && i != len(segments)-1 {
+			return nil, "", fmt.Errorf("** must be the last segment of %q", pattern)
+		}
+	}
+	return segments, verb, nil
+}
+
+// match binds the path segments to the route and returns the variables.
+func (r *GRPCHTTPRoute) match(parts []string) (map[string]string, bool) {
+	if r.verb != "" {
+		last := len(parts) - 1
+		if last < 0 || !strings.HasSuffix(parts[last], ":"+r.verb) {
+			return nil, false
+		}
+		parts = append(parts[:last:last], strings.TrimSuffix(parts[last], ":"+r.verb))
+	}
+
+	captured := map[string][]string{}
+	j := 0
+	for _, seg := range r.segments {
+		if seg.wildcard == "**" {
+			if seg.variable != "" {
+				captured[seg.variable] = append(captured[seg.variable], parts[j:]...)
+			}
+			j = len(parts)
+			break
+		}
+		if j >= len(parts) || (seg.literal != "" && parts[j] != seg.literal) {
+			return nil, false
+		}
+		if seg.variable != "" {
+			captured[seg.variable] = append(captured[seg.variable], parts[j])
+		}
+		j++
+	}
+	if j != len(parts) {
+		return nil, false
+	}
+	vars := make(map[string]string, len(captured))
+	for name, values := range captured {
+		vars[name] = strings.Join(values, "/")
+	}
+	return vars, true
+}
+
+// buildRequest encodes the request message from the body, the path variables
+// and, unless the whole body is the request, the query parameters.
+func (r *GRPCHTTPRoute) buildRequest(req *http.Request, vars map[string]string) ([]byte, error) {
+	input := r.method.Input()
+	fields := map[string]any{}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if r.Body != "" && len(bytes.TrimSpace(body)) > 0 {
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+		if r.Body == "*" {
+			obj, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("body must be a JSON object")
+			}
+			fields = obj
+		} else {
+			setFieldPath(fields, input, r.Body, value)
+		}
+	}
+
+	for name, value := range vars {
+		setFieldPath(fields, input, name, paramValue(findFieldPath(input, name), value))
+	}
+	if r.Body != "*" {
+		for key, values := range req.URL.Query() {
+			if _, bound := vars[key]; bound {
+				continue
+			}
+			// Unknown parameters are ignored rather than rejected.
+			fd := findFieldPath(input, key)
+			if fd == nil || len(values) == 0 {
+				continue
+			}
+			if fd.IsList() {
+				list := make([]any, 0, len(values))
+				for _, v := range values {
+					list = append(list, paramValue(fd, v))
+				}
+				setFieldPath(fields, input, key, list)
+			} else {
+				setFieldPath(fields, input, key, paramValue(fd, values[0]))
+			}
+		}
+	}
+
+	jsonBytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(input)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(jsonBytes, msg); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", input.FullName(), err)
+	}
+	return proto.Marshal(msg)
+}
+
+// encodeResponse marshals the response like grpc-gateway: JSON field names
+// with unpopulated fields included, or only the response_body field.
+func (r *GRPCHTTPRoute) encodeResponse(result proto.Message) ([]byte, error) {
+	out, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+	if err != nil || r.ResponseBody == "" {
+		return out, err
+	}
+	fd := result.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(r.ResponseBody))
+	if fd == nil {
+		return out, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(out, &obj); err != nil {
+		return nil, err
+	}
+	return obj[fd.JSONName()], nil
+}
+
+// findFieldPath resolves a dot-separated path of proto or JSON field names.
+func findFieldPath(desc protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+	for i, name := range strings.Split(path, ".") {
+		if i > 0 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+				return nil
+			}
+			desc = fd.Message()
+		}
+		fd = desc.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = desc.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return nil
+		}
+	}
+	return fd
+}
+
+// setFieldPath stores value at path in the JSON form of the request, under
+// the proto field names. A JSON-name key of the same field is dropped so a
+// path variable overrides the body.
+func setFieldPath(fields map[string]any, desc protoreflect.MessageDescriptor, path string, value any) {
+	names := strings.Split(path, ".")
+	node := fields
+	for i, name := range names {
+		fd := desc.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = desc.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return
+		}
+		key := string(fd.Name())
+		if fd.JSONName() != key {
+			if i == len(names)-1 {
+				delete(node, fd.JSONName())
+			} else if v, ok := node[fd.JSONName()]; ok {
+				node[key] = v
+				delete(node, fd.JSONName())
+			}
+		}
+		if i == len(names)-1 {
+			node[key] = value
+			return
+		}
+		child, ok := node[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[key] = child
+		}
+		node = child
+		desc = fd.Message()
+	}
+}
+
+// paramValue converts a path or query value to the JSON accepted by protojson,
+// which already takes numbers, timestamps and durations as strings.
+func paramValue(fd protoreflect.FieldDescriptor, s string) any {
+	if fd == nil {
+		return s
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case protoreflect.EnumKind:
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	}
+	return s
+}
+
+// transcodeError encodes a gRPC error as grpc-gateway does: the HTTP status
+// of the code and the google.rpc.Status as JSON.
+func transcodeError(err error) (int, []byte) {
+	st := status.Convert(err)
+	body, mErr := protojson.Marshal(st.Proto())
+	if mErr != nil {
+		body, _ = json.Marshal(map[string]any{"code": int32(st.Code()), "message": st.Message()})
+	}
+	return httpStatusFromCode(st.Code()), body
+}
+
+// httpStatusFromCode follows the mapping of grpc-gateway.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
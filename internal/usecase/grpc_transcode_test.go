@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	mockrepo "github.com/namnv2496/mocktool/mocks/repository"
+)
+
+const testLibraryProto = `
+syntax = "proto3";
+package demo.v1;
+
+import "google/api/annotations.proto";
+
+message Book {
+  string shelf = 1;
+  string book_id = 2;
+  string title = 3;
+  int32 page = 4;
+  bool draft = 5;
+}
+
+message GetBookRequest {
+  string shelf = 1;
+  string book_id = 2;
+  int32 page = 3;
+  bool draft = 4;
+}
+
+message CreateBookRequest {
+  string shelf = 1;
+  Book book = 2;
+}
+
+message CreateBookResponse {
+  Book book = 1;
+}
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = {
+      get: "/v1/{shelf=shelves/*}/books/{book_id}"
+      additional_bindings { get: "/v1/books/{book_id}:latest" }
+    };
+  }
+  rpc CreateBook(CreateBookRequest) returns (CreateBookResponse) {
+    option (google.api.http) = {
+      post: "/v1/{shelf=shelves/*}/books"
+      body: "book"
+      response_body: "book"
+    };
+  }
+}
+`
+
+func newTestTranscoder(t *testing.T) (*GRPCTranscoder, *mockrepo.MockIGRPCMockAPIRepository) {
+	ctrl := gomock.NewController(t)
+	descRepo := mockrepo.NewMockIGRPCDescriptorRepository(ctrl)
+	var stored []domain.GRPCDescriptor
+	descRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d *domain.GRPCDescriptor) error {
+		stored = append(stored, *d)
+		return nil
+	}).AnyTimes()
+	descRepo.EXPECT().ListAll(gomock.Any()).DoAndReturn(func(context.Context) ([]domain.GRPCDescriptor, error) {
+		return stored, nil
+	}).AnyTimes()
+
+	registry := NewGRPCDescriptorRegistry(descRepo)
+	set, err := CompileProtoFiles(context.Background(), map[string]string{"demo/v1/library.proto": testLibraryProto})
+	require.NoError(t, err)
+	_, err = registry.Register(context.Background(), set, domain.GRPCDescriptorSourceProto)
+	require.NoError(t, err)
+
+	grpcRepo := mockrepo.NewMockIGRPCMockAPIRepository(ctrl)
+	// Scenarios of these tests have no parent.
+	scenarioRepo := mockrepo.NewMockIScenarioRepository(ctrl)
+	scenarioRepo.EXPECT().FindByFeatureNameAndName(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, featureName, name string) (*domain.Scenario, error) {
+			return &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: featureName, Name: name}, nil
+		}).AnyTimes()
+	forward := NewGRPCForwardUC(grpcRepo, scenarioRepo, nil, registry, nil, missingCache(ctrl), NewStatsStore())
+	return NewGRPCTranscoder(registry, forward), grpcRepo
+}
+
+func TestGRPCTranscoder_Routes(t *testing.T) {
+	transcoder, _ := newTestTranscoder(t)
+
+	var got []string
+	for _, r := range transcoder.Routes() {
+		got = append(got, r.HTTPMethod+" "+r.Pattern+" "+r.FullMethod)
+	}
+	assert.ElementsMatch(t, []string{
+		"GET /v1/{shelf=shelves/*}/books/{book_id} /demo.v1.Library/GetBook",
+		"GET /v1/books/{book_id}:latest /demo.v1.Library/GetBook",
+		"POST /v1/{shelf=shelves/*}/books /demo.v1.Library/CreateBook",
+	}, got)
+}
+
+func TestGRPCTranscoder_PathAndQueryBinding(t *testing.T) {
+	transcoder, grpcRepo := newTestTranscoder(t)
+
+	// The gRPC mock created for {"shelf": "shelves/s1", "book_id": "b1",
+	// "page": 2, "draft": true} answers the REST call.
+	hash := hashTypedRequest(map[string]any{"shelf": "shelves/s1", "book_id": "b1", "page": float64(2), "draft": true})
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), "feat", "pinned", "demo.v1.Library", "GetBook", hash).
+		Return(&domain.GRPCMockAPI{Output: outputBSON(t, map[string]any{"title": "Dune", "book_id": "b1"})}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/shelves/s1/books/b1?page=2&draft=true&unknown=x", nil)
+	code, body, ok := transcoder.Transcode(context.Background(), req, "feat", nil, "pinned")
+
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"shelf": "", "bookId": "b1", "title": "Dune", "page": 0, "draft": false}`, string(body))
+}
+
+func TestGRPCTranscoder_BodyFieldAndResponseBody(t *testing.T) {
+	transcoder, grpcRepo := newTestTranscoder(t)
+
+	hash := hashTypedRequest(map[string]any{"shelf": "shelves/s1", "book": map[string]any{"title": "Dune"}})
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), "feat", "pinned", "demo.v1.Library", "CreateBook", hash).
+		Return(&domain.GRPCMockAPI{Output: outputBSON(t, map[string]any{"book": map[string]any{"title": "Dune", "book_id": "new"}})}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/shelves/s1/books", strings.NewReader(`{"title": "Dune"}`))
+	code, body, ok := transcoder.Transcode(context.Background(), req, "feat", nil, "pinned")
+
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"shelf": "", "bookId": "new", "title": "Dune", "page": 0, "draft": false}`, string(body))
+}
+
+func TestGRPCTranscoder_CustomVerbAndErrors(t *testing.T) {
+	transcoder, grpcRepo := newTestTranscoder(t)
+
+	grpcRepo.EXPECT().
+		FindByFeatureScenarioServiceMethodAndHash(gomock.Any(), "feat", "pinned", "demo.v1.Library", "GetBook", gomock.Any()).
+		Return(nil, mongo.ErrNoDocuments)
+	grpcRepo.EXPECT().
+		ListActiveByMethod(gomock.Any(), "feat", "pinned", "demo.v1.Library", "GetBook").
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/books/b9:latest", nil)
+	code, body, ok := transcoder.Transcode(context.Background(), req, "feat", nil, "pinned")
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Contains(t, string(body), `"code":5`)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/shelves/s1/books/b1?page=abc", nil)
+	code, _, ok = transcoder.Transcode(context.Background(), req, "feat", nil, "pinned")
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	_, _, ok = transcoder.Transcode(context.Background(), httptest.NewRequest(http.MethodDelete, "/v1/books/b1:latest", nil), "feat", nil, "pinned")
+	assert.False(t, ok, "no route for DELETE")
+}
+
+func TestGRPCTranscoder_Matches(t *testing.T) {
+	transcoder, _ := newTestTranscoder(t)
+
+	tests := []struct {
+		method, target string
+		want           bool
+	}{
+		{http.MethodGet, "/v1/shelves/s1/books/b1", true},
+		{http.MethodGet, "/v1/books/b1:latest", true},
+		{http.MethodDelete, "/v1/books/b1:latest", false},
+		{http.MethodGet, "/healthz", false},
+		{http.MethodGet, "/", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, transcoder.Matches(httptest.NewRequest(tt.method, tt.target, nil)), "%s %s", tt.method, tt.target)
+	}
+}
+
+func TestParseHTTPTemplate(t *testing.T) {
+	segments, verb, err := parseHTTPTemplate("/v1/{name=shelves/*/books/**}:undelete")
+	require.NoError(t, err)
+	assert.Equal(t, "undelete", verb)
+	assert.Equal(t, []routeSegment{
+		{literal: "v1"},
+		{literal: "shelves", variable: "name"},
+		{wildcard: "*", variable: "name"},
+		{literal: "books", variable: "name"},
+		{wildcard: "**", variable: "name"},
+	}, segments)
+
+	for _, bad := range []string{"v1/books", "/v1/{id", "/v1/**/books", "/v1//books"} {
+		_, _, err := parseHTTPTemplate(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+const readinessTimeout = 2 * time.Second
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type IReadinessUC interface {
+	// Check returns an error when Mongo or Redis cannot be reached.
+	Check(ctx context.Context) error
+}
+
+type ReadinessUC struct {
+	mongoClient *mongo.Client
+	cacheRepo   repository.ICache
+}
+
+func NewReadinessUC(
+	mongoClient *mongo.Client,
+	cacheRepo repository.ICache,
+) IReadinessUC {
+	return &ReadinessUC{
+		mongoClient: mongoClient,
+		cacheRepo:   cacheRepo,
+	}
+}
+
+func (_self *ReadinessUC) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	if err := _self.mongoClient.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("mongo: %w", err)
+	}
+	if err := _self.cacheRepo.Ping(ctx); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
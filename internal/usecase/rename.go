@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+var (
+	ErrRenameNotFound = errors.New("rename source not found")
+	ErrRenameConflict = errors.New("rename target already exists")
+	ErrInvalidRename  = errors.New("invalid rename")
+)
+
+// RenameResult tells what a rename rewrote.
+type RenameResult struct {
+	Feature  string `json:"feature"`
+	Scenario string `json:"scenario,omitempty"`
+	OldName  string `json:"old_name"`
+	NewName  string `json:"new_name"`
+	// Updated counts the documents rewritten per collection.
+	Updated map[string]int64 `json:"updated"`
+}
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type IRenameUC interface {
+	// RenameFeature renames a feature along with the feature_name of its
+	// scenarios, mocks, activations, proxies and session profiles.
+	RenameFeature(ctx context.Context, oldName, newName string) (*RenameResult, error)
+	// RenameScenario renames a scenario of featureName along with the
+	// scenario_name of its mocks, proxies and session profiles.
+	RenameScenario(ctx context.Context, featureName, oldName, newName string) (*RenameResult, error)
+}
+
+// RenameUC renames in one transaction, then drops the cached responses under
+// both the old and the new name.
+type RenameUC struct {
+	featureRepo  repository.IFeatureRepository
+	scenarioRepo repository.IScenarioRepository
+	renameRepo   repository.IRenameRepository
+	cacheRepo    repository.ICache
+}
+
+func NewRenameUC(
+	featureRepo repository.IFeatureRepository,
+	scenarioRepo repository.IScenarioRepository,
+	renameRepo repository.IRenameRepository,
+	cacheRepo repository.ICache,
+) IRenameUC {
+	return &RenameUC{
+		featureRepo:  featureRepo,
+		scenarioRepo: scenarioRepo,
+		renameRepo:   renameRepo,
+		cacheRepo:    cacheRepo,
+	}
+}
+
+func (_self *RenameUC) RenameFeature(ctx context.Context, oldName, newName string) (*RenameResult, error) {
+	if err := validateRename(oldName, newName); err != nil {
+		return nil, err
+	}
+	existing, err := _self.featureRepo.FindByName(ctx, newName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Name != "" {
+		return nil, fmt.Errorf("%w: feature %q", ErrRenameConflict, newName)
+	}
+	updated, err := _self.renameRepo.RenameFeature(ctx, oldName, newName)
+	if err != nil {
+		return nil, renameError(err, fmt.Sprintf("feature %q", oldName))
+	}
+	for _, name := range []string{oldName, newName} {
+		_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, name))
+	}
+	return &RenameResult{Feature: newName, OldName: oldName, NewName: newName, Updated: updated}, nil
+}
+
+func (_self *RenameUC) RenameScenario(ctx context.Context, featureName, oldName, newName string) (*RenameResult, error) {
+	if featureName == "" {
+		return nil, fmt.Errorf("%w: feature is required", ErrInvalidRename)
+	}
+	if err := validateRename(oldName, newName); err != nil {
+		return nil, err
+	}
+	existing, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, featureName, newName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Name != "" {
+		return nil, fmt.Errorf("%w: scenario %q of feature %q", ErrRenameConflict, newName, featureName)
+	}
+	updated, err := _self.renameRepo.RenameScenario(ctx, featureName, oldName, newName)
+	if err != nil {
+		return nil, renameError(err, fmt.Sprintf("scenario %q of feature %q", oldName, featureName))
+	}
+	for _, name := range []string{oldName, newName} {
+		_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, featureName, name))
+	}
+	return &RenameResult{
+		Feature:  featureName,
+		Scenario: newName,
+		OldName:  oldName,
+		NewName:  newName,
+		Updated:  updated,
+	}, nil
+}
+
+func validateRename(oldName, newName string) error {
+	switch {
+	case oldName == "" || newName == "":
+		return fmt.Errorf("%w: old and new names are required", ErrInvalidRename)
+	case strings.ContainsAny(newName, " \t\n"):
+		return fmt.Errorf("%w: name cannot contain spaces", ErrInvalidRename)
+	case oldName == newName:
+		return fmt.Errorf("%w: the new name is the current one", ErrInvalidRename)
+	}
+	return nil
+}
+
+func renameError(err error, source string) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %s", ErrRenameNotFound, source)
+	}
+	return err
+}
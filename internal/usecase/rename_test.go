@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+func newTestRenameUC(t *testing.T) (IRenameUC, *repoMocks) {
+	r := newRepoMocks(t)
+	return NewRenameUC(r.features, r.scenarios, r.renames, r.cache), r
+}
+
+func TestRenameUC_RenameFeature(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalidates both names", func(t *testing.T) {
+		uc, r := newTestRenameUC(t)
+		r.features.EXPECT().FindByName(gomock.Any(), "postAd").Return(&domain.Feature{}, nil)
+		r.renames.EXPECT().RenameFeature(gomock.Any(), "insertAd", "postAd").
+			Return(map[string]int64{"features": 1, "mock_apis": 4}, nil)
+		r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:insertAd:*").Return(nil)
+		r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:postAd:*").Return(nil)
+
+		result, err := uc.RenameFeature(ctx, "insertAd", "postAd")
+		require.NoError(t, err)
+		assert.Equal(t, "postAd", result.Feature)
+		assert.Equal(t, int64(4), result.Updated["mock_apis"])
+	})
+
+	t.Run("name taken", func(t *testing.T) {
+		uc, r := newTestRenameUC(t)
+		r.features.EXPECT().FindByName(gomock.Any(), "postAd").Return(&domain.Feature{Name: "postAd"}, nil)
+
+		_, err := uc.RenameFeature(ctx, "insertAd", "postAd")
+		assert.True(t, errors.Is(err, ErrRenameConflict))
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		uc, r := newTestRenameUC(t)
+		r.features.EXPECT().FindByName(gomock.Any(), "postAd").Return(&domain.Feature{}, nil)
+		r.renames.EXPECT().RenameFeature(gomock.Any(), "insertAd", "postAd").Return(nil, mongo.ErrNoDocuments)
+
+		_, err := uc.RenameFeature(ctx, "insertAd", "postAd")
+		assert.True(t, errors.Is(err, ErrRenameNotFound))
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		uc, _ := newTestRenameUC(t)
+		_, err := uc.RenameFeature(ctx, "insertAd", "post ad")
+		assert.True(t, errors.Is(err, ErrInvalidRename))
+	})
+}
+
+func TestRenameUC_RenameScenario(t *testing.T) {
+	uc, r := newTestRenameUC(t)
+	ctx := context.Background()
+	r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "insertAd", "declined").Return(&domain.Scenario{}, nil)
+	r.renames.EXPECT().RenameScenario(gomock.Any(), "insertAd", "failed", "declined").
+		Return(nil, repository.ErrReplicaSetRequired)
+
+	_, err := uc.RenameScenario(ctx, "insertAd", "failed", "declined")
+	assert.True(t, errors.Is(err, repository.ErrReplicaSetRequired))
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	repositoryMocks "github.com/namnv2496/mocktool/mocks/repository"
+)
+
+// repoMocks holds one gomock repository of each kind on a shared controller.
+// Tests build the use case under test from the ones it needs.
+type repoMocks struct {
+	features    *repositoryMocks.MockIFeatureRepository
+	scenarios   *repositoryMocks.MockIScenarioRepository
+	activations *repositoryMocks.MockIAccountScenarioRepository
+	mockAPIs    *repositoryMocks.MockIMockAPIRepository
+	grpcMocks   *repositoryMocks.MockIGRPCMockAPIRepository
+	revisions   *repositoryMocks.MockIRevisionRepository
+	sessions    *repositoryMocks.MockISessionRepository
+	profiles    *repositoryMocks.MockISessionProfileRepository
+	renames     *repositoryMocks.MockIRenameRepository
+	trash       *repositoryMocks.MockITrashRepository
+	bundles     *repositoryMocks.MockIBundleRepository
+	cache       *repositoryMocks.MockICache
+}
+
+func newRepoMocks(t *testing.T) *repoMocks {
+	ctrl := gomock.NewController(t)
+	return &repoMocks{
+		features:    repositoryMocks.NewMockIFeatureRepository(ctrl),
+		scenarios:   repositoryMocks.NewMockIScenarioRepository(ctrl),
+		activations: repositoryMocks.NewMockIAccountScenarioRepository(ctrl),
+		mockAPIs:    repositoryMocks.NewMockIMockAPIRepository(ctrl),
+		grpcMocks:   repositoryMocks.NewMockIGRPCMockAPIRepository(ctrl),
+		revisions:   repositoryMocks.NewMockIRevisionRepository(ctrl),
+		sessions:    repositoryMocks.NewMockISessionRepository(ctrl),
+		profiles:    repositoryMocks.NewMockISessionProfileRepository(ctrl),
+		renames:     repositoryMocks.NewMockIRenameRepository(ctrl),
+		trash:       repositoryMocks.NewMockITrashRepository(ctrl),
+		bundles:     repositoryMocks.NewMockIBundleRepository(ctrl),
+		cache:       repositoryMocks.NewMockICache(ctrl),
+	}
+}
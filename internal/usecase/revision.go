@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+var (
+	ErrUnknownRevisionKind = errors.New("unknown revision kind")
+	ErrRevisionNotFound    = errors.New("revision not found")
+	ErrRevisionConflict    = errors.New("revision conflicts with the current data")
+	errRevisionNoDocument  = errors.New("document not found")
+)
+
+// revisionIgnoredFields change on every write and are left out of diffs.
+var revisionIgnoredFields = map[string]bool{
+	"_id":        true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// RevisionDiff lists the fields changed from revision From to revision To.
+type RevisionDiff struct {
+	From    int                  `json:"from"`
+	To      int                  `json:"to"`
+	Changes []domain.FieldChange `json:"changes"`
+}
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type IRevisionUC interface {
+	// Record snapshots the document as it is now. Call it after a create or
+	// an update and before a delete.
+	Record(ctx context.Context, kind string, id primitive.ObjectID, action, author string) error
+	// RecordDeleted snapshots doc, the document a delete just removed, so a
+	// delete that can fail is only recorded once it succeeded.
+	RecordDeleted(ctx context.Context, kind string, id primitive.ObjectID, doc any, author string) error
+	List(ctx context.Context, kind string, id primitive.ObjectID, params domain.PaginationParams) ([]domain.Revision, int64, error)
+	// Diff compares two revisions. to defaults to the latest one and from to
+	// the one before to.
+	Diff(ctx context.Context, kind string, id primitive.ObjectID, from, to int) (*RevisionDiff, error)
+	// Restore writes the snapshot of version back, recreating the document
+	// if it was deleted, and records it as a new revision.
+	Restore(ctx context.Context, kind string, id primitive.ObjectID, version int, author string) (*domain.Revision, error)
+}
+
+type RevisionUC struct {
+	revisionRepo    repository.IRevisionRepository
+	mockAPIRepo     repository.IMockAPIRepository
+	grpcMockAPIRepo repository.IGRPCMockAPIRepository
+	scenarioRepo    repository.IScenarioRepository
+	cacheRepo       repository.ICache
+}
+
+func NewRevisionUC(
+	revisionRepo repository.IRevisionRepository,
+	mockAPIRepo repository.IMockAPIRepository,
+	grpcMockAPIRepo repository.IGRPCMockAPIRepository,
+	scenarioRepo repository.IScenarioRepository,
+	cacheRepo repository.ICache,
+) IRevisionUC {
+	return &RevisionUC{
+		revisionRepo:    revisionRepo,
+		mockAPIRepo:     mockAPIRepo,
+		grpcMockAPIRepo: grpcMockAPIRepo,
+		scenarioRepo:    scenarioRepo,
+		cacheRepo:       cacheRepo,
+	}
+}
+
+func (_self *RevisionUC) Record(ctx context.Context, kind string, id primitive.ObjectID, action, author string) error {
+	doc, err := _self.load(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	snapshot, err := toSnapshot(doc)
+	if err != nil {
+		return err
+	}
+	_, err = _self.append(ctx, kind, id, action, author, 0, snapshot)
+	return err
+}
+
+func (_self *RevisionUC) RecordDeleted(ctx context.Context, kind string, id primitive.ObjectID, doc any, author string) error {
+	if !isRevisionKind(kind) {
+		return ErrUnknownRevisionKind
+	}
+	snapshot, err := toSnapshot(doc)
+	if err != nil {
+		return err
+	}
+	_, err = _self.append(ctx, kind, id, domain.RevisionActionDelete, author, 0, snapshot)
+	return err
+}
+
+func (_self *RevisionUC) List(
+	ctx context.Context,
+	kind string,
+	id primitive.ObjectID,
+	params domain.PaginationParams,
+) ([]domain.Revision, int64, error) {
+	if !isRevisionKind(kind) {
+		return nil, 0, ErrUnknownRevisionKind
+	}
+	return _self.revisionRepo.ListByResource(ctx, kind, id, params)
+}
+
+func (_self *RevisionUC) Diff(ctx context.Context, kind string, id primitive.ObjectID, from, to int) (*RevisionDiff, error) {
+	if !isRevisionKind(kind) {
+		return nil, ErrUnknownRevisionKind
+	}
+	var target *domain.Revision
+	var err error
+	if to > 0 {
+		target, err = _self.revisionRepo.FindByVersion(ctx, kind, id, to)
+	} else {
+		target, err = _self.revisionRepo.FindLatest(ctx, kind, id)
+	}
+	if err != nil {
+		return nil, revisionLookupError(err)
+	}
+	if from <= 0 {
+		from = target.Version - 1
+	}
+
+	var before bson.M
+	if from > 0 {
+		base, err := _self.revisionRepo.FindByVersion(ctx, kind, id, from)
+		if err != nil {
+			return nil, revisionLookupError(err)
+		}
+		before = base.Snapshot
+	}
+	return &RevisionDiff{
+		From:    from,
+		To:      target.Version,
+		Changes: diffSnapshots(before, target.Snapshot),
+	}, nil
+}
+
+func (_self *RevisionUC) Restore(
+	ctx context.Context,
+	kind string,
+	id primitive.ObjectID,
+	version int,
+	author string,
+) (*domain.Revision, error) {
+	if !isRevisionKind(kind) {
+		return nil, ErrUnknownRevisionKind
+	}
+	rev, err := _self.revisionRepo.FindByVersion(ctx, kind, id, version)
+	if err != nil {
+		return nil, revisionLookupError(err)
+	}
+	current, err := _self.load(ctx, kind, id)
+	if err != nil && err != errRevisionNoDocument {
+		return nil, err
+	}
+
+	raw, err := bson.Marshal(rev.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	var restored any
+	switch kind {
+	case domain.RevisionKindMockAPI:
+		var m domain.MockAPI
+		if err := bson.Unmarshal(raw, &m); err != nil {
+			return nil, err
+		}
+		other, err := _self.mockAPIRepo.FindByFeatureScenarioPathMethodAndHash(ctx, m.FeatureName, m.ScenarioName, m.Path, m.Method, m.HashInput)
+		if err == nil && other != nil && other.ID != m.ID {
+			return nil, fmt.Errorf("%w: mock API %q serves the same request", ErrRevisionConflict, other.Name)
+		}
+		m.UpdatedAt = now
+		if err := _self.mockAPIRepo.Replace(ctx, &m); err != nil {
+			return nil, err
+		}
+		restored = &m
+	case domain.RevisionKindGRPCMockAPI:
+		var m domain.GRPCMockAPI
+		if err := bson.Unmarshal(raw, &m); err != nil {
+			return nil, err
+		}
+		other, err := _self.grpcMockAPIRepo.FindByFeatureScenarioServiceMethodAndHash(ctx, m.FeatureName, m.ScenarioName, m.ServiceName, m.MethodName, m.HashInput)
+		if err == nil && other != nil && other.ID != m.ID {
+			return nil, fmt.Errorf("%w: another gRPC mock serves the same request", ErrRevisionConflict)
+		}
+		m.UpdatedAt = now
+		if err := _self.grpcMockAPIRepo.Replace(ctx, &m); err != nil {
+			return nil, err
+		}
+		restored = &m
+	case domain.RevisionKindScenario:
+		var s domain.Scenario
+		if err := bson.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		other, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, s.FeatureName, s.Name)
+		if err == nil && other != nil && other.Name != "" && other.ID != s.ID {
+			return nil, fmt.Errorf("%w: scenario %q already exists", ErrRevisionConflict, s.Name)
+		}
+		if s.ParentID != nil {
+			if _, err := ValidateScenarioParent(ctx, _self.scenarioRepo, &s, *s.ParentID); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrRevisionConflict, err)
+			}
+		}
+		s.UpdatedAt = now
+		if err := _self.scenarioRepo.Replace(ctx, &s); err != nil {
+			return nil, err
+		}
+		restored = &s
+	}
+
+	// Drop the cached lookups of the scenario the document left and of the
+	// one it is back in.
+	for _, doc := range []any{current, restored} {
+		if feature, scenario := revisionScope(doc); feature != "" {
+			_self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, feature, scenario))
+		}
+	}
+
+	snapshot, err := toSnapshot(restored)
+	if err != nil {
+		return nil, err
+	}
+	return _self.append(ctx, kind, id, domain.RevisionActionRestore, author, version, snapshot)
+}
+
+// append stores snapshot as the next revision of the document.
+func (_self *RevisionUC) append(
+	ctx context.Context,
+	kind string,
+	id primitive.ObjectID,
+	action, author string,
+	restoredFrom int,
+	snapshot bson.M,
+) (*domain.Revision, error) {
+	rev := &domain.Revision{
+		Kind:         kind,
+		ResourceID:   id,
+		Version:      1,
+		Action:       action,
+		Author:       author,
+		RestoredFrom: restoredFrom,
+		Snapshot:     snapshot,
+	}
+	latest, err := _self.revisionRepo.FindLatest(ctx, kind, id)
+	switch {
+	case err == nil:
+		rev.Version = latest.Version + 1
+		rev.Changes = diffSnapshots(latest.Snapshot, snapshot)
+	case err == mongo.ErrNoDocuments:
+		rev.Changes = diffSnapshots(nil, snapshot)
+	default:
+		return nil, err
+	}
+	if err := _self.revisionRepo.Create(ctx, rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// load returns the current document, errRevisionNoDocument when it does not
+// exist.
+func (_self *RevisionUC) load(ctx context.Context, kind string, id primitive.ObjectID) (any, error) {
+	switch kind {
+	case domain.RevisionKindMockAPI:
+		m, err := _self.mockAPIRepo.FindByObjectID(ctx, id)
+		if err != nil || m == nil || m.ID.IsZero() {
+			return nil, errRevisionNoDocument
+		}
+		return m, nil
+	case domain.RevisionKindGRPCMockAPI:
+		m, err := _self.grpcMockAPIRepo.FindByID(ctx, id)
+		if err == mongo.ErrNoDocuments {
+			return nil, errRevisionNoDocument
+		}
+		return m, err
+	case domain.RevisionKindScenario:
+		s, err := _self.scenarioRepo.GetByObjectID(ctx, id)
+		if err == mongo.ErrNoDocuments {
+			return nil, errRevisionNoDocument
+		}
+		return s, err
+	}
+	return nil, ErrUnknownRevisionKind
+}
+
+func isRevisionKind(kind string) bool {
+	switch kind {
+	case domain.RevisionKindMockAPI, domain.RevisionKindGRPCMockAPI, domain.RevisionKindScenario:
+		return true
+	}
+	return false
+}
+
+func revisionLookupError(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return ErrRevisionNotFound
+	}
+	return err
+}
+
+// revisionScope returns the feature and scenario whose cache doc lives in.
+func revisionScope(doc any) (string, string) {
+	switch d := doc.(type) {
+	case *domain.MockAPI:
+		return d.FeatureName, d.ScenarioName
+	case *domain.GRPCMockAPI:
+		return d.FeatureName, d.ScenarioName
+	case *domain.Scenario:
+		return d.FeatureName, d.Name
+	}
+	return "", ""
+}
+
+// toSnapshot converts doc to the generic form stored in a revision, so a
+// fresh snapshot compares equal to one read back from Mongo.
+func toSnapshot(doc any) (bson.M, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot bson.M
+	if err := bson.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// diffSnapshots lists the top-level fields that differ, sorted by name.
+func diffSnapshots(before, after bson.M) []domain.FieldChange {
+	fields := make(map[string]bool, len(after))
+	for k := range before {
+		fields[k] = true
+	}
+	for k := range after {
+		fields[k] = true
+	}
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		if !revisionIgnoredFields[k] {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []domain.FieldChange{}
+	for _, k := range names {
+		if reflect.DeepEqual(before[k], after[k]) {
+			continue
+		}
+		changes = append(changes, domain.FieldChange{Field: k, Before: before[k], After: after[k]})
+	}
+	return changes
+}
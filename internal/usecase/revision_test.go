@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+// keepRevisions makes the revision repository keep the created revisions in
+// the returned slice.
+func keepRevisions(r *repoMocks) *[]*domain.Revision {
+	stored := &[]*domain.Revision{}
+	r.revisions.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, rev *domain.Revision) error {
+			*stored = append(*stored, rev)
+			return nil
+		},
+	).AnyTimes()
+	r.revisions.EXPECT().FindLatest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, string, primitive.ObjectID) (*domain.Revision, error) {
+			if len(*stored) == 0 {
+				return nil, mongo.ErrNoDocuments
+			}
+			return (*stored)[len(*stored)-1], nil
+		},
+	).AnyTimes()
+	r.revisions.EXPECT().FindByVersion(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ primitive.ObjectID, version int) (*domain.Revision, error) {
+			if version < 1 || version > len(*stored) {
+				return nil, mongo.ErrNoDocuments
+			}
+			return (*stored)[version-1], nil
+		},
+	).AnyTimes()
+	return stored
+}
+
+func newTestRevisionUC(t *testing.T) (IRevisionUC, *repoMocks, *[]*domain.Revision) {
+	r := newRepoMocks(t)
+	uc := NewRevisionUC(r.revisions, r.mockAPIs, r.grpcMocks, r.scenarios, r.cache)
+	return uc, r, keepRevisions(r)
+}
+
+func TestRevisionUC_RecordAndDiff(t *testing.T) {
+	uc, r, stored := newTestRevisionUC(t)
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	api := &domain.MockAPI{ID: id, FeatureName: "f", ScenarioName: "s", Name: "login", Path: "/login", Method: "POST", StatusCode: 200}
+
+	r.mockAPIs.EXPECT().FindByObjectID(gomock.Any(), id).DoAndReturn(
+		func(context.Context, primitive.ObjectID) (*domain.MockAPI, error) {
+			copied := *api
+			return &copied, nil
+		},
+	).Times(2)
+
+	require.NoError(t, uc.Record(ctx, domain.RevisionKindMockAPI, id, domain.RevisionActionCreate, "alice"))
+	api.StatusCode = 500
+	require.NoError(t, uc.Record(ctx, domain.RevisionKindMockAPI, id, domain.RevisionActionUpdate, "bob"))
+
+	require.Len(t, *stored, 2)
+	assert.Equal(t, 2, (*stored)[1].Version)
+	assert.Equal(t, "bob", (*stored)[1].Author)
+	require.Len(t, (*stored)[1].Changes, 1)
+	assert.Equal(t, "status_code", (*stored)[1].Changes[0].Field)
+
+	diff, err := uc.Diff(ctx, domain.RevisionKindMockAPI, id, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+	assert.Equal(t, (*stored)[1].Changes, diff.Changes)
+
+	_, err = uc.Diff(ctx, domain.RevisionKindMockAPI, id, 0, 7)
+	assert.True(t, errors.Is(err, ErrRevisionNotFound))
+	_, err = uc.Diff(ctx, "feature", id, 0, 0)
+	assert.True(t, errors.Is(err, ErrUnknownRevisionKind))
+}
+
+func TestRevisionUC_RestoreDeletedMockAPI(t *testing.T) {
+	uc, r, _ := newTestRevisionUC(t)
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	api := &domain.MockAPI{ID: id, FeatureName: "f", ScenarioName: "s", Name: "login", Path: "/login", Method: "POST", HashInput: "h"}
+
+	r.mockAPIs.EXPECT().FindByObjectID(gomock.Any(), id).Return(api, nil)
+	require.NoError(t, uc.Record(ctx, domain.RevisionKindMockAPI, id, domain.RevisionActionDelete, "alice"))
+
+	// Deleted: FindByObjectID returns an empty document.
+	r.mockAPIs.EXPECT().FindByObjectID(gomock.Any(), id).Return(&domain.MockAPI{}, nil).Times(2)
+	r.mockAPIs.EXPECT().
+		FindByFeatureScenarioPathMethodAndHash(gomock.Any(), "f", "s", "/login", "POST", "h").
+		Return(nil, mongo.ErrNoDocuments)
+	r.mockAPIs.EXPECT().Replace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, m *domain.MockAPI) error {
+			assert.Equal(t, id, m.ID)
+			assert.Equal(t, "login", m.Name)
+			return nil
+		},
+	)
+	r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:f:s:*").Return(nil)
+
+	rev, err := uc.Restore(ctx, domain.RevisionKindMockAPI, id, 1, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rev.Version)
+	assert.Equal(t, 1, rev.RestoredFrom)
+	assert.Equal(t, domain.RevisionActionRestore, rev.Action)
+
+	// Another mock now answers the same request.
+	r.mockAPIs.EXPECT().
+		FindByFeatureScenarioPathMethodAndHash(gomock.Any(), "f", "s", "/login", "POST", "h").
+		Return(&domain.MockAPI{ID: primitive.NewObjectID(), Name: "login-v2"}, nil)
+	_, err = uc.Restore(ctx, domain.RevisionKindMockAPI, id, 1, "bob")
+	assert.True(t, errors.Is(err, ErrRevisionConflict))
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+// MaxScenarioDepth bounds a scenario chain, the scenario itself included.
+// Lookups stop there, which also ends a cycle written around the validation.
+const MaxScenarioDepth = 8
+
+// parentScenario returns the parent of s, where s is at depth in the chain
+// being walked. It returns nil when s has no parent, the parent was deleted
+// or the chain is at MaxScenarioDepth.
+func parentScenario(
+	ctx context.Context,
+	repo repository.IScenarioRepository,
+	s *domain.Scenario,
+	depth int,
+) (*domain.Scenario, error) {
+	if s == nil || s.ParentID == nil || depth+1 >= MaxScenarioDepth {
+		return nil, nil
+	}
+	parent, err := repo.GetByObjectID(ctx, *s.ParentID)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return parent, err
+}
+
+// ScenarioChain returns scenario followed by its parents, nearest first.
+func ScenarioChain(
+	ctx context.Context,
+	repo repository.IScenarioRepository,
+	scenario *domain.Scenario,
+) ([]*domain.Scenario, error) {
+	chain := []*domain.Scenario{scenario}
+	for depth := 0; ; depth++ {
+		parent, err := parentScenario(ctx, repo, chain[depth], depth)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return chain, nil
+		}
+		chain = append(chain, parent)
+	}
+}
+
+// ValidateScenarioParent loads the scenario parentID and checks it can be
+// the parent of scenario: same feature, no cycle and a chain no longer than
+// MaxScenarioDepth. scenario.ID is zero for a scenario not created yet.
+func ValidateScenarioParent(
+	ctx context.Context,
+	repo repository.IScenarioRepository,
+	scenario *domain.Scenario,
+	parentID primitive.ObjectID,
+) (*domain.Scenario, error) {
+	if parentID == scenario.ID {
+		return nil, errors.New("a scenario cannot be its own parent")
+	}
+	parent, err := repo.GetByObjectID(ctx, parentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("parent scenario not found")
+		}
+		return nil, err
+	}
+	if parent.FeatureName != scenario.FeatureName {
+		return nil, fmt.Errorf("parent scenario %q belongs to feature %q", parent.Name, parent.FeatureName)
+	}
+	chain, err := ScenarioChain(ctx, repo, parent)
+	if err != nil {
+		return nil, err
+	}
+	for _, ancestor := range chain {
+		if ancestor.ID == scenario.ID {
+			return nil, fmt.Errorf("scenario %q already inherits from %q", parent.Name, scenario.Name)
+		}
+	}
+	if len(chain) >= MaxScenarioDepth {
+		return nil, fmt.Errorf("scenario chains are limited to %d levels", MaxScenarioDepth)
+	}
+	return parent, nil
+}
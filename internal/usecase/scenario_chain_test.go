@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/namnv2496/mocktool/internal/domain"
+	repositoryMocks "github.com/namnv2496/mocktool/mocks/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+)
+
+// scenarioStore serves GetByObjectID from a fixed set of scenarios.
+func scenarioStore(ctrl *gomock.Controller, scenarios ...*domain.Scenario) *repositoryMocks.MockIScenarioRepository {
+	byID := make(map[primitive.ObjectID]*domain.Scenario, len(scenarios))
+	for _, s := range scenarios {
+		byID[s.ID] = s
+	}
+	repo := repositoryMocks.NewMockIScenarioRepository(ctrl)
+	repo.EXPECT().GetByObjectID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id primitive.ObjectID) (*domain.Scenario, error) {
+			if s, ok := byID[id]; ok {
+				return s, nil
+			}
+			return nil, mongo.ErrNoDocuments
+		},
+	).AnyTimes()
+	return repo
+}
+
+func TestScenarioChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	root := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "root"}
+	mid := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "mid", ParentID: &root.ID}
+	deleted := primitive.NewObjectID()
+	leaf := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "leaf", ParentID: &mid.ID}
+	orphan := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "orphan", ParentID: &deleted}
+	repo := scenarioStore(ctrl, root, mid, leaf, orphan)
+
+	chain, err := ScenarioChain(context.Background(), repo, leaf)
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+	assert.Equal(t, []string{"leaf", "mid", "root"}, []string{chain[0].Name, chain[1].Name, chain[2].Name})
+
+	chain, err = ScenarioChain(context.Background(), repo, orphan)
+	require.NoError(t, err)
+	assert.Len(t, chain, 1, "a deleted parent ends the chain")
+}
+
+func TestValidateScenarioParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	root := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "root"}
+	child := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "child", ParentID: &root.ID}
+	other := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "g", Name: "other"}
+
+	// A straight chain already at the maximum depth.
+	deep := []*domain.Scenario{{ID: primitive.NewObjectID(), FeatureName: "f", Name: "d0"}}
+	for i := 1; i < MaxScenarioDepth; i++ {
+		deep = append(deep, &domain.Scenario{
+			ID:          primitive.NewObjectID(),
+			FeatureName: "f",
+			Name:        fmt.Sprintf("d%d", i),
+			ParentID:    &deep[i-1].ID,
+		})
+	}
+	repo := scenarioStore(ctrl, append(deep, root, child, other)...)
+	ctx := context.Background()
+	fresh := &domain.Scenario{FeatureName: "f", Name: "new"}
+
+	parent, err := ValidateScenarioParent(ctx, repo, fresh, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "child", parent.Name)
+
+	_, err = ValidateScenarioParent(ctx, repo, root, root.ID)
+	assert.ErrorContains(t, err, "own parent")
+
+	_, err = ValidateScenarioParent(ctx, repo, fresh, primitive.NewObjectID())
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = ValidateScenarioParent(ctx, repo, fresh, other.ID)
+	assert.ErrorContains(t, err, "belongs to feature")
+
+	_, err = ValidateScenarioParent(ctx, repo, root, child.ID)
+	assert.ErrorContains(t, err, "already inherits")
+
+	_, err = ValidateScenarioParent(ctx, repo, fresh, deep[len(deep)-1].ID)
+	assert.ErrorContains(t, err, "limited to")
+}
+
+func TestForwardUC_FallsThroughToParentScenario(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	base := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "base"}
+	child := &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "f", Name: "child", ParentID: &base.ID}
+
+	mockAPIRepo := repositoryMocks.NewMockIMockAPIRepository(ctrl)
+	accountScenarioRepo := repositoryMocks.NewMockIAccountScenarioRepository(ctrl)
+	cacheRepo := repositoryMocks.NewMockICache(ctrl)
+	uc := NewForwardUC(mockAPIRepo, scenarioStore(ctrl, base, child), accountScenarioRepo, cacheRepo, NewStatsStore())
+
+	account := "acc-1"
+	accountScenarioRepo.EXPECT().
+		GetActiveScenario(gomock.Any(), "f", &account).
+		Return(&domain.AccountScenario{ScenarioID: child.ID}, nil)
+	cacheRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("miss")).Times(2)
+
+	output, _ := bson.Marshal(map[string]any{"from": "base"})
+	gomock.InOrder(
+		mockAPIRepo.EXPECT().
+			FindByFeatureScenarioPathMethodAndHash(gomock.Any(), "f", "child", "/api/v1/users", "POST", gomock.Any()).
+			Return(nil, mongo.ErrNoDocuments),
+		mockAPIRepo.EXPECT().
+			FindByFeatureScenarioPathMethodAndHash(gomock.Any(), "f", "base", "/api/v1/users", "POST", gomock.Any()).
+			Return(&domain.MockAPI{FeatureName: "f", ScenarioName: "base", Output: output}, nil),
+	)
+	// The child remembers the miss, the parent caches its own mock.
+	cacheRepo.EXPECT().SetWithTTL(gomock.Any(), gomock.Any(), notFoundSentinel, notFoundCacheTTL).Return(nil)
+	cacheRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	body, _ := json.Marshal(map[string]any{"name": "x"})
+	req := httptest.NewRequest(http.MethodPost, "/forward/api/v1/users", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Account-Id", account)
+	req.Header.Set("X-Feature-Name", "f")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, uc.ResponseMockData(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"from":"base"`)
+}
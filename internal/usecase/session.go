@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+// ephemeralAccountPrefix starts the account IDs generated for sessions
+// applied without one.
+const ephemeralAccountPrefix = "session-"
+
+var (
+	ErrInvalidSessionProfile  = errors.New("invalid session profile")
+	ErrSessionProfileNotFound = errors.New("session profile not found")
+	ErrSessionNotFound        = errors.New("session not found")
+	ErrSessionConflict        = errors.New("a session is already applied to the account")
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type ISessionUC interface {
+	// SaveProfile creates or replaces a profile after checking every
+	// scenario exists.
+	SaveProfile(ctx context.Context, p *domain.SessionProfile) error
+	// Apply activates every scenario of the profile for accountId, or for a
+	// generated account when it is empty. A positive duration ends the
+	// activations on their own.
+	Apply(ctx context.Context, profileName, accountId string, duration time.Duration) (*domain.Session, error)
+	// Revert removes the activations of a session, giving the account its
+	// previous scenarios back.
+	Revert(ctx context.Context, id primitive.ObjectID) (*domain.Session, error)
+}
+
+// SessionUC applies all the activations of a profile or none: a failure
+// part way deletes the ones already created. Nothing is deactivated, so the
+// account's own activations need no backup.
+type SessionUC struct {
+	profileRepo         repository.ISessionProfileRepository
+	sessionRepo         repository.ISessionRepository
+	scenarioRepo        repository.IScenarioRepository
+	accountScenarioRepo repository.IAccountScenarioRepository
+	cacheRepo           repository.ICache
+}
+
+func NewSessionUC(
+	profileRepo repository.ISessionProfileRepository,
+	sessionRepo repository.ISessionRepository,
+	scenarioRepo repository.IScenarioRepository,
+	accountScenarioRepo repository.IAccountScenarioRepository,
+	cacheRepo repository.ICache,
+) ISessionUC {
+	return &SessionUC{
+		profileRepo:         profileRepo,
+		sessionRepo:         sessionRepo,
+		scenarioRepo:        scenarioRepo,
+		accountScenarioRepo: accountScenarioRepo,
+		cacheRepo:           cacheRepo,
+	}
+}
+
+func (_self *SessionUC) SaveProfile(ctx context.Context, p *domain.SessionProfile) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSessionProfile)
+	}
+	if len(p.Entries) == 0 {
+		return fmt.Errorf("%w: at least one feature is required", ErrInvalidSessionProfile)
+	}
+	if _, err := _self.resolve(ctx, p.Entries); err != nil {
+		return err
+	}
+	return _self.profileRepo.Upsert(ctx, p)
+}
+
+// resolve loads the scenario of every entry.
+func (_self *SessionUC) resolve(ctx context.Context, entries []domain.SessionProfileEntry) ([]*domain.Scenario, error) {
+	seen := make(map[string]bool, len(entries))
+	scenarios := make([]*domain.Scenario, 0, len(entries))
+	for _, e := range entries {
+		if e.FeatureName == "" || e.ScenarioName == "" {
+			return nil, fmt.Errorf("%w: feature_name and scenario_name are required", ErrInvalidSessionProfile)
+		}
+		if seen[e.FeatureName] {
+			return nil, fmt.Errorf("%w: feature %q is listed twice", ErrInvalidSessionProfile, e.FeatureName)
+		}
+		seen[e.FeatureName] = true
+		scenario, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, e.FeatureName, e.ScenarioName)
+		if err != nil {
+			return nil, err
+		}
+		if scenario == nil || scenario.Name == "" {
+			return nil, fmt.Errorf("%w: scenario %q of feature %q not found", ErrInvalidSessionProfile, e.ScenarioName, e.FeatureName)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+func (_self *SessionUC) Apply(ctx context.Context, profileName, accountId string, duration time.Duration) (*domain.Session, error) {
+	profile, err := _self.profileRepo.FindByName(ctx, profileName)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w: %q", ErrSessionProfileNotFound, profileName)
+		}
+		return nil, err
+	}
+	scenarios, err := _self.resolve(ctx, profile.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		ProfileName: profile.Name,
+		AccountId:   strings.TrimSpace(accountId),
+		Features:    make([]string, 0, len(scenarios)),
+		Activations: make([]primitive.ObjectID, 0, len(scenarios)),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if session.AccountId == "" {
+		session.AccountId = ephemeralAccountPrefix + primitive.NewObjectID().Hex()
+		session.Ephemeral = true
+	} else if existing, err := _self.sessionRepo.FindByAccount(ctx, session.AccountId); err == nil {
+		if existing.ExpiresAt == nil || existing.ExpiresAt.After(session.CreatedAt) {
+			return nil, fmt.Errorf("%w: revert session %s of profile %q first", ErrSessionConflict, existing.ID.Hex(), existing.ProfileName)
+		}
+		// An expired session only leaves its record behind.
+		if _, err := _self.Revert(ctx, existing.ID); err != nil {
+			return nil, err
+		}
+	} else if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	for _, scenario := range scenarios {
+		mapping := &domain.AccountScenario{
+			FeatureName: scenario.FeatureName,
+			ScenarioID:  scenario.ID,
+			AccountId:   &session.AccountId,
+			CreatedAt:   session.CreatedAt,
+			UpdatedAt:   session.CreatedAt,
+		}
+		if err := mapping.SetWindow(session.CreatedAt, nil, nil, duration); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSessionProfile, err)
+		}
+		session.ExpiresAt = mapping.ExpiresAt
+		// The newest activation of the account wins, so this one overrides
+		// the account's own until it is deleted.
+		if err := _self.accountScenarioRepo.Create(ctx, mapping); err != nil {
+			_ = _self.deleteActivations(context.WithoutCancel(ctx), session)
+			return nil, err
+		}
+		session.Features = append(session.Features, scenario.FeatureName)
+		session.Activations = append(session.Activations, mapping.ID)
+	}
+	if err := _self.sessionRepo.Create(ctx, session); err != nil {
+		_ = _self.deleteActivations(context.WithoutCancel(ctx), session)
+		return nil, err
+	}
+	_self.invalidate(ctx, session)
+	return session, nil
+}
+
+func (_self *SessionUC) Revert(ctx context.Context, id primitive.ObjectID) (*domain.Session, error) {
+	session, err := _self.sessionRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	// Expired activations were already removed by the reaper.
+	if err := _self.deleteActivations(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := _self.sessionRepo.Delete(ctx, session.ID); err != nil {
+		return nil, err
+	}
+	_self.invalidate(ctx, session)
+	return session, nil
+}
+
+func (_self *SessionUC) deleteActivations(ctx context.Context, session *domain.Session) error {
+	for _, id := range session.Activations {
+		if err := _self.accountScenarioRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invalidate drops the cached mocks of the session's account in every
+// feature it touches.
+func (_self *SessionUC) invalidate(ctx context.Context, session *domain.Session) {
+	for _, feature := range session.Features {
+		_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplateAccount, feature, "*", session.AccountId))
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/domain"
+)
+
+func newTestSessionUC(t *testing.T) (ISessionUC, *repoMocks) {
+	r := newRepoMocks(t)
+	return NewSessionUC(r.profiles, r.sessions, r.scenarios, r.activations, r.cache), r
+}
+
+// expectE2EProfile sets up the "e2e" profile pinning checkout to declined and
+// refund to slow.
+func expectE2EProfile(r *repoMocks) (checkout, refund *domain.Scenario) {
+	checkout = &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "checkout", Name: "declined"}
+	refund = &domain.Scenario{ID: primitive.NewObjectID(), FeatureName: "refund", Name: "slow"}
+	r.profiles.EXPECT().FindByName(gomock.Any(), "e2e").Return(&domain.SessionProfile{
+		Name: "e2e",
+		Entries: []domain.SessionProfileEntry{
+			{FeatureName: "checkout", ScenarioName: "declined"},
+			{FeatureName: "refund", ScenarioName: "slow"},
+		},
+	}, nil)
+	r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "checkout", "declined").Return(checkout, nil)
+	r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "refund", "slow").Return(refund, nil)
+	return checkout, refund
+}
+
+func TestSessionUC_Apply(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ephemeral account", func(t *testing.T) {
+		uc, r := newTestSessionUC(t)
+		checkout, refund := expectE2EProfile(r)
+		var account string
+		r.activations.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, as *domain.AccountScenario) error {
+				require.NotNil(t, as.AccountId)
+				account = *as.AccountId
+				require.NotNil(t, as.ExpiresAt)
+				as.ID = primitive.NewObjectID()
+				return nil
+			},
+		).Times(2)
+		r.sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		r.cache.EXPECT().InvalidAllKey(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, key string) error {
+				assert.Contains(t, key, account)
+				return nil
+			},
+		).Times(2)
+
+		session, err := uc.Apply(ctx, "e2e", "", time.Hour)
+		require.NoError(t, err)
+		assert.True(t, session.Ephemeral)
+		assert.True(t, strings.HasPrefix(session.AccountId, ephemeralAccountPrefix))
+		assert.Equal(t, account, session.AccountId)
+		assert.Equal(t, []string{checkout.FeatureName, refund.FeatureName}, session.Features)
+		assert.Len(t, session.Activations, 2)
+		assert.NotNil(t, session.ExpiresAt)
+	})
+
+	t.Run("account already in a session", func(t *testing.T) {
+		uc, r := newTestSessionUC(t)
+		expectE2EProfile(r)
+		r.sessions.EXPECT().FindByAccount(gomock.Any(), "acc-1").
+			Return(&domain.Session{ID: primitive.NewObjectID(), ProfileName: "other", AccountId: "acc-1"}, nil)
+
+		_, err := uc.Apply(ctx, "e2e", "acc-1", 0)
+		assert.True(t, errors.Is(err, ErrSessionConflict))
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		uc, r := newTestSessionUC(t)
+		expectE2EProfile(r)
+		r.sessions.EXPECT().FindByAccount(gomock.Any(), "acc-1").Return(nil, mongo.ErrNoDocuments)
+		first := primitive.NewObjectID()
+		gomock.InOrder(
+			r.activations.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, as *domain.AccountScenario) error { as.ID = first; return nil },
+			),
+			r.activations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("boom")),
+			r.activations.EXPECT().Delete(gomock.Any(), first).Return(nil),
+		)
+
+		_, err := uc.Apply(ctx, "e2e", "acc-1", 0)
+		assert.EqualError(t, err, "boom")
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		uc, r := newTestSessionUC(t)
+		r.profiles.EXPECT().FindByName(gomock.Any(), "nope").Return(nil, mongo.ErrNoDocuments)
+
+		_, err := uc.Apply(ctx, "nope", "acc-1", 0)
+		assert.True(t, errors.Is(err, ErrSessionProfileNotFound))
+	})
+}
+
+func TestSessionUC_Revert(t *testing.T) {
+	uc, r := newTestSessionUC(t)
+	ctx := context.Background()
+	session := &domain.Session{
+		ID:          primitive.NewObjectID(),
+		AccountId:   "acc-1",
+		Features:    []string{"checkout"},
+		Activations: []primitive.ObjectID{primitive.NewObjectID()},
+	}
+	r.sessions.EXPECT().FindByID(gomock.Any(), session.ID).Return(session, nil)
+	r.activations.EXPECT().Delete(gomock.Any(), session.Activations[0]).Return(nil)
+	r.sessions.EXPECT().Delete(gomock.Any(), session.ID).Return(nil)
+	r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:checkout:*:acc-1:*").Return(nil)
+
+	reverted, err := uc.Revert(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", reverted.AccountId)
+}
+
+func TestSessionUC_SaveProfile_RejectsUnknownScenario(t *testing.T) {
+	uc, r := newTestSessionUC(t)
+	r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "checkout", "missing").Return(&domain.Scenario{}, nil)
+
+	err := uc.SaveProfile(context.Background(), &domain.SessionProfile{
+		Name:    "e2e",
+		Entries: []domain.SessionProfileEntry{{FeatureName: "checkout", ScenarioName: "missing"}},
+	})
+	assert.True(t, errors.Is(err, ErrInvalidSessionProfile))
+}
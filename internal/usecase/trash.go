@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/namnv2496/mocktool/internal/configs"
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+var (
+	ErrDeleteNotFound = errors.New("nothing to delete")
+	ErrTrashNotFound  = errors.New("trash entry not found")
+	ErrTrashConflict  = errors.New("restore conflicts with an existing document")
+	ErrDeleteConflict = errors.New("delete conflicts with other documents")
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../../mocks/usecase/$GOFILE.mock.go -package=$GOPACKAGE
+type ITrashUC interface {
+	// DeleteFeature moves a feature with its scenarios, mocks and
+	// activations to the trash.
+	DeleteFeature(ctx context.Context, featureName, actor string) (*domain.TrashEntry, error)
+	// DeleteScenario moves a scenario with its mocks and activations to the
+	// trash. A scenario other scenarios inherit from is not deleted.
+	DeleteScenario(ctx context.Context, featureName, scenarioName, actor string) (*domain.TrashEntry, error)
+	List(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.TrashEntry, int64, error)
+	// Restore puts the documents of an entry back as they were deleted,
+	// except activations replaced since.
+	Restore(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error)
+	// Purge deletes an entry for good.
+	Purge(ctx context.Context, id primitive.ObjectID) error
+	// PurgeExpired deletes the entries past their retention at now.
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TrashUC makes deletes soft: the documents move to the trash in one
+// transaction and stay there for the retention period.
+type TrashUC struct {
+	retention    time.Duration
+	featureRepo  repository.IFeatureRepository
+	scenarioRepo repository.IScenarioRepository
+	trashRepo    repository.ITrashRepository
+	cacheRepo    repository.ICache
+}
+
+func NewTrashUC(
+	config *configs.Config,
+	featureRepo repository.IFeatureRepository,
+	scenarioRepo repository.IScenarioRepository,
+	trashRepo repository.ITrashRepository,
+	cacheRepo repository.ICache,
+) ITrashUC {
+	return &TrashUC{
+		retention:    config.AppConfig.TrashRetention,
+		featureRepo:  featureRepo,
+		scenarioRepo: scenarioRepo,
+		trashRepo:    trashRepo,
+		cacheRepo:    cacheRepo,
+	}
+}
+
+func (_self *TrashUC) newEntry(kind, featureName, scenarioName, actor string) *domain.TrashEntry {
+	now := time.Now().UTC()
+	return &domain.TrashEntry{
+		Kind:         kind,
+		FeatureName:  featureName,
+		ScenarioName: scenarioName,
+		DeletedBy:    actor,
+		DeletedAt:    now,
+		ExpiresAt:    now.Add(_self.retention),
+	}
+}
+
+func (_self *TrashUC) DeleteFeature(ctx context.Context, featureName, actor string) (*domain.TrashEntry, error) {
+	entry := _self.newEntry(domain.TrashKindFeature, featureName, "", actor)
+	if err := _self.trashRepo.TrashFeature(ctx, entry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: feature %q", ErrDeleteNotFound, featureName)
+		}
+		return nil, err
+	}
+	_self.invalidate(ctx, entry)
+	return entry, nil
+}
+
+func (_self *TrashUC) DeleteScenario(ctx context.Context, featureName, scenarioName, actor string) (*domain.TrashEntry, error) {
+	entry := _self.newEntry(domain.TrashKindScenario, featureName, scenarioName, actor)
+	if err := _self.trashRepo.TrashScenario(ctx, entry); err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return nil, fmt.Errorf("%w: scenario %q of feature %q", ErrDeleteNotFound, scenarioName, featureName)
+		case errors.Is(err, repository.ErrScenarioHasChildren):
+			return nil, fmt.Errorf("%w: other scenarios of feature %q inherit from %q, delete or reparent them first", ErrDeleteConflict, featureName, scenarioName)
+		}
+		return nil, err
+	}
+	_self.invalidate(ctx, entry)
+	return entry, nil
+}
+
+func (_self *TrashUC) List(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.TrashEntry, int64, error) {
+	return _self.trashRepo.List(ctx, featureName, params)
+}
+
+func (_self *TrashUC) Restore(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error) {
+	entry, err := _self.trashRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTrashNotFound
+		}
+		return nil, err
+	}
+	if err := _self.checkRestore(ctx, entry); err != nil {
+		return nil, err
+	}
+	restored, err := _self.trashRepo.Restore(ctx, id)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return nil, ErrTrashNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return nil, fmt.Errorf("%w: %v", ErrTrashConflict, err)
+	case err != nil:
+		return nil, err
+	}
+	_self.invalidate(ctx, restored)
+	return restored, nil
+}
+
+// checkRestore refuses to bring back a name that was reused since, or a
+// scenario whose feature or parent scenario is gone.
+func (_self *TrashUC) checkRestore(ctx context.Context, entry *domain.TrashEntry) error {
+	feature, err := _self.featureRepo.FindByName(ctx, entry.FeatureName)
+	if err != nil {
+		return err
+	}
+	featureExists := feature != nil && feature.Name != ""
+	if entry.Kind == domain.TrashKindFeature {
+		if featureExists {
+			return fmt.Errorf("%w: feature %q exists", ErrTrashConflict, entry.FeatureName)
+		}
+		return nil
+	}
+	if !featureExists {
+		return fmt.Errorf("%w: feature %q does not exist anymore, restore it first", ErrTrashConflict, entry.FeatureName)
+	}
+	scenario, err := _self.scenarioRepo.FindByFeatureNameAndName(ctx, entry.FeatureName, entry.ScenarioName)
+	if err != nil {
+		return err
+	}
+	if scenario != nil && scenario.Name != "" {
+		return fmt.Errorf("%w: scenario %q of feature %q exists", ErrTrashConflict, entry.ScenarioName, entry.FeatureName)
+	}
+	if entry.ParentID != nil {
+		if _, err := _self.scenarioRepo.GetByObjectID(ctx, *entry.ParentID); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return fmt.Errorf("%w: the parent scenario of %q does not exist anymore, restore it first", ErrTrashConflict, entry.ScenarioName)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (_self *TrashUC) Purge(ctx context.Context, id primitive.ObjectID) error {
+	if err := _self.trashRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrTrashNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (_self *TrashUC) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	return _self.trashRepo.DeleteExpired(ctx, now)
+}
+
+func (_self *TrashUC) invalidate(ctx context.Context, entry *domain.TrashEntry) {
+	if entry.Kind == domain.TrashKindFeature {
+		_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyFeatureTemplate, entry.FeatureName))
+		return
+	}
+	_ = _self.cacheRepo.InvalidAllKey(ctx, fmt.Sprintf(repository.KeyScnarioTemplate, entry.FeatureName, entry.ScenarioName))
+}
+
+// TrashReaper purges the trash entries past their retention.
+type TrashReaper struct {
+	trash ITrashUC
+
+	cancel context.CancelFunc
+}
+
+func NewTrashReaper(trash ITrashUC) *TrashReaper {
+	return &TrashReaper{trash: trash}
+}
+
+// StartWorker purges expired entries every interval until ctx is done or
+// StopWorker is called. A non-positive interval disables the worker.
+func (_self *TrashReaper) StartWorker(ctx context.Context, interval time.Duration) {
+	if _self == nil || interval <= 0 {
+		return
+	}
+	workerCtx, cancel := context.WithCancel(ctx)
+	_self.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		slog.Info("trash reaper started", "interval", interval)
+
+		for {
+			select {
+			case <-ticker.C:
+				purgeCtx, cancel := context.WithTimeout(workerCtx, 10*time.Second)
+				if n, err := _self.trash.PurgeExpired(purgeCtx, time.Now().UTC()); err != nil {
+					slog.Warn("purge expired trash entries", "error", err)
+				} else if n > 0 {
+					slog.Info("expired trash entries purged", "count", n)
+				}
+				cancel()
+			case <-workerCtx.Done():
+				slog.Info("trash reaper stopped")
+				return
+			}
+		}
+	}()
+}
+
+// StopWorker stops the reaper.
+func (_self *TrashReaper) StopWorker() {
+	if _self == nil || _self.cancel == nil {
+		return
+	}
+	_self.cancel()
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/mock/gomock"
+
+	"github.com/namnv2496/mocktool/internal/configs"
+	"github.com/namnv2496/mocktool/internal/domain"
+	"github.com/namnv2496/mocktool/internal/repository"
+)
+
+func newTestTrashUC(t *testing.T) (ITrashUC, *repoMocks) {
+	r := newRepoMocks(t)
+	config := &configs.Config{AppConfig: configs.AppConfig{TrashRetention: time.Hour}}
+	return NewTrashUC(config, r.features, r.scenarios, r.trash, r.cache), r
+}
+
+func TestTrashUC_DeleteFeature(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("moves to trash and invalidates", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		r.trash.EXPECT().TrashFeature(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, entry *domain.TrashEntry) error {
+				assert.Equal(t, domain.TrashKindFeature, entry.Kind)
+				assert.Equal(t, "insertAd", entry.FeatureName)
+				assert.Equal(t, "alice", entry.DeletedBy)
+				assert.Equal(t, time.Hour, entry.ExpiresAt.Sub(entry.DeletedAt))
+				entry.Counts = map[string]int{"features": 1}
+				return nil
+			},
+		)
+		r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:insertAd:*").Return(nil)
+
+		entry, err := uc.DeleteFeature(ctx, "insertAd", "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 1, entry.Counts["features"])
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		r.trash.EXPECT().TrashFeature(gomock.Any(), gomock.Any()).Return(mongo.ErrNoDocuments)
+
+		_, err := uc.DeleteFeature(ctx, "missing", "alice")
+		assert.True(t, errors.Is(err, ErrDeleteNotFound))
+	})
+}
+
+func TestTrashUC_DeleteScenario(t *testing.T) {
+	uc, r := newTestTrashUC(t)
+	r.trash.EXPECT().TrashScenario(gomock.Any(), gomock.Any()).Return(nil)
+	r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:insertAd:declined:*").Return(nil)
+
+	entry, err := uc.DeleteScenario(context.Background(), "insertAd", "declined", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, domain.TrashKindScenario, entry.Kind)
+	assert.Equal(t, "declined", entry.ScenarioName)
+
+	r.trash.EXPECT().TrashScenario(gomock.Any(), gomock.Any()).Return(repository.ErrScenarioHasChildren)
+	_, err = uc.DeleteScenario(context.Background(), "insertAd", "base", "alice")
+	assert.ErrorIs(t, err, ErrDeleteConflict)
+}
+
+func TestTrashUC_Restore(t *testing.T) {
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	scenarioEntry := &domain.TrashEntry{ID: id, Kind: domain.TrashKindScenario, FeatureName: "insertAd", ScenarioName: "declined"}
+
+	t.Run("restores and invalidates", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		r.trash.EXPECT().FindByID(gomock.Any(), id).Return(scenarioEntry, nil)
+		r.features.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{Name: "insertAd"}, nil)
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "insertAd", "declined").Return(&domain.Scenario{}, nil)
+		r.trash.EXPECT().Restore(gomock.Any(), id).Return(scenarioEntry, nil)
+		r.cache.EXPECT().InvalidAllKey(gomock.Any(), "mocktool:insertAd:declined:*").Return(nil)
+
+		entry, err := uc.Restore(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, entry.ID)
+	})
+
+	t.Run("name reused", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		r.trash.EXPECT().FindByID(gomock.Any(), id).Return(&domain.TrashEntry{ID: id, Kind: domain.TrashKindFeature, FeatureName: "insertAd"}, nil)
+		r.features.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{Name: "insertAd"}, nil)
+
+		_, err := uc.Restore(ctx, id)
+		assert.True(t, errors.Is(err, ErrTrashConflict))
+	})
+
+	t.Run("feature gone", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		r.trash.EXPECT().FindByID(gomock.Any(), id).Return(scenarioEntry, nil)
+		r.features.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{}, nil)
+
+		_, err := uc.Restore(ctx, id)
+		assert.True(t, errors.Is(err, ErrTrashConflict))
+	})
+
+	t.Run("parent scenario gone", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		parentID := primitive.NewObjectID()
+		child := *scenarioEntry
+		child.ParentID = &parentID
+		r.trash.EXPECT().FindByID(gomock.Any(), id).Return(&child, nil)
+		r.features.EXPECT().FindByName(gomock.Any(), "insertAd").Return(&domain.Feature{Name: "insertAd"}, nil)
+		r.scenarios.EXPECT().FindByFeatureNameAndName(gomock.Any(), "insertAd", "declined").Return(&domain.Scenario{}, nil)
+		r.scenarios.EXPECT().GetByObjectID(gomock.Any(), parentID).Return(nil, mongo.ErrNoDocuments)
+
+		_, err := uc.Restore(ctx, id)
+		assert.ErrorIs(t, err, ErrTrashConflict)
+	})
+
+	t.Run("unknown entry", func(t *testing.T) {
+		uc, r := newTestTrashUC(t)
+		r.trash.EXPECT().FindByID(gomock.Any(), id).Return(nil, mongo.ErrNoDocuments)
+
+		_, err := uc.Restore(ctx, id)
+		assert.Equal(t, ErrTrashNotFound, err)
+	})
+}
+
+func TestTrashUC_Purge(t *testing.T) {
+	uc, r := newTestTrashUC(t)
+	id := primitive.NewObjectID()
+	r.trash.EXPECT().Delete(gomock.Any(), id).Return(mongo.ErrNoDocuments)
+
+	assert.Equal(t, ErrTrashNotFound, uc.Purge(context.Background(), id))
+}
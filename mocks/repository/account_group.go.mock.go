@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: account_group.go
+//
+// Generated by this command:
+//
+//	mockgen -source=account_group.go -destination=../../mocks/repository/account_group.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccountGroupRepository is a mock of IAccountGroupRepository interface.
+type MockIAccountGroupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccountGroupRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIAccountGroupRepositoryMockRecorder is the mock recorder for MockIAccountGroupRepository.
+type MockIAccountGroupRepositoryMockRecorder struct {
+	mock *MockIAccountGroupRepository
+}
+
+// NewMockIAccountGroupRepository creates a new mock instance.
+func NewMockIAccountGroupRepository(ctrl *gomock.Controller) *MockIAccountGroupRepository {
+	mock := &MockIAccountGroupRepository{ctrl: ctrl}
+	mock.recorder = &MockIAccountGroupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccountGroupRepository) EXPECT() *MockIAccountGroupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByName mocks base method.
+func (m *MockIAccountGroupRepository) DeleteByName(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByName", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByName indicates an expected call of DeleteByName.
+func (mr *MockIAccountGroupRepositoryMockRecorder) DeleteByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByName", reflect.TypeOf((*MockIAccountGroupRepository)(nil).DeleteByName), ctx, name)
+}
+
+// FindByName mocks base method.
+func (m *MockIAccountGroupRepository) FindByName(ctx context.Context, name string) (*domain.AccountGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, name)
+	ret0, _ := ret[0].(*domain.AccountGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockIAccountGroupRepositoryMockRecorder) FindByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockIAccountGroupRepository)(nil).FindByName), ctx, name)
+}
+
+// ListAll mocks base method.
+func (m *MockIAccountGroupRepository) ListAll(ctx context.Context) ([]domain.AccountGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]domain.AccountGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockIAccountGroupRepositoryMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockIAccountGroupRepository)(nil).ListAll), ctx)
+}
+
+// ListNamesByAccount mocks base method.
+func (m *MockIAccountGroupRepository) ListNamesByAccount(ctx context.Context, accountId string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNamesByAccount", ctx, accountId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamesByAccount indicates an expected call of ListNamesByAccount.
+func (mr *MockIAccountGroupRepositoryMockRecorder) ListNamesByAccount(ctx, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamesByAccount", reflect.TypeOf((*MockIAccountGroupRepository)(nil).ListNamesByAccount), ctx, accountId)
+}
+
+// Upsert mocks base method.
+func (m *MockIAccountGroupRepository) Upsert(ctx context.Context, g *domain.AccountGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, g)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockIAccountGroupRepositoryMockRecorder) Upsert(ctx, g any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockIAccountGroupRepository)(nil).Upsert), ctx, g)
+}
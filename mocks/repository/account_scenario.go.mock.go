@@ -12,6 +12,7 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/namnv2496/mocktool/internal/domain"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -84,6 +85,34 @@ func (mr *MockIAccountScenarioRepositoryMockRecorder) DeactivateByFeatureAndAcco
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateByFeatureAndAccount", reflect.TypeOf((*MockIAccountScenarioRepository)(nil).DeactivateByFeatureAndAccount), ctx, featureName, accountId)
 }
 
+// DeactivateByTarget mocks base method.
+func (m *MockIAccountScenarioRepository) DeactivateByTarget(ctx context.Context, as *domain.AccountScenario) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateByTarget", ctx, as)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateByTarget indicates an expected call of DeactivateByTarget.
+func (mr *MockIAccountScenarioRepositoryMockRecorder) DeactivateByTarget(ctx, as any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateByTarget", reflect.TypeOf((*MockIAccountScenarioRepository)(nil).DeactivateByTarget), ctx, as)
+}
+
+// Delete mocks base method.
+func (m *MockIAccountScenarioRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIAccountScenarioRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIAccountScenarioRepository)(nil).Delete), ctx, id)
+}
+
 // DeleteByScenarioId mocks base method.
 func (m *MockIAccountScenarioRepository) DeleteByScenarioId(ctx context.Context, scenarioId primitive.ObjectID) error {
 	m.ctrl.T.Helper()
@@ -127,3 +156,33 @@ func (mr *MockIAccountScenarioRepositoryMockRecorder) GetActiveScenarioByName(ct
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveScenarioByName", reflect.TypeOf((*MockIAccountScenarioRepository)(nil).GetActiveScenarioByName), ctx, featureName, scenario)
 }
+
+// ListByFeature mocks base method.
+func (m *MockIAccountScenarioRepository) ListByFeature(ctx context.Context, featureName string) ([]domain.AccountScenario, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFeature", ctx, featureName)
+	ret0, _ := ret[0].([]domain.AccountScenario)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFeature indicates an expected call of ListByFeature.
+func (mr *MockIAccountScenarioRepositoryMockRecorder) ListByFeature(ctx, featureName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFeature", reflect.TypeOf((*MockIAccountScenarioRepository)(nil).ListByFeature), ctx, featureName)
+}
+
+// ListExpired mocks base method.
+func (m *MockIAccountScenarioRepository) ListExpired(ctx context.Context, now time.Time) ([]domain.AccountScenario, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpired", ctx, now)
+	ret0, _ := ret[0].([]domain.AccountScenario)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockIAccountScenarioRepositoryMockRecorder) ListExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockIAccountScenarioRepository)(nil).ListExpired), ctx, now)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: audit.go
+//
+// Generated by this command:
+//
+//	mockgen -source=audit.go -destination=../../mocks/repository/audit.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAuditRepository is a mock of IAuditRepository interface.
+type MockIAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIAuditRepositoryMockRecorder is the mock recorder for MockIAuditRepository.
+type MockIAuditRepositoryMockRecorder struct {
+	mock *MockIAuditRepository
+}
+
+// NewMockIAuditRepository creates a new mock instance.
+func NewMockIAuditRepository(ctrl *gomock.Controller) *MockIAuditRepository {
+	mock := &MockIAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockIAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditRepository) EXPECT() *MockIAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIAuditRepository) Create(ctx context.Context, e *domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIAuditRepositoryMockRecorder) Create(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIAuditRepository)(nil).Create), ctx, e)
+}
+
+// List mocks base method.
+func (m *MockIAuditRepository) List(ctx context.Context, filter domain.AuditFilter, params domain.PaginationParams) ([]domain.AuditEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter, params)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockIAuditRepositoryMockRecorder) List(ctx, filter, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIAuditRepository)(nil).List), ctx, filter, params)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bundle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=bundle.go -destination=../../mocks/repository/bundle.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	repository "github.com/namnv2496/mocktool/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIBundleRepository is a mock of IBundleRepository interface.
+type MockIBundleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBundleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIBundleRepositoryMockRecorder is the mock recorder for MockIBundleRepository.
+type MockIBundleRepositoryMockRecorder struct {
+	mock *MockIBundleRepository
+}
+
+// NewMockIBundleRepository creates a new mock instance.
+func NewMockIBundleRepository(ctrl *gomock.Controller) *MockIBundleRepository {
+	mock := &MockIBundleRepository{ctrl: ctrl}
+	mock.recorder = &MockIBundleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBundleRepository) EXPECT() *MockIBundleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockIBundleRepository) Apply(ctx context.Context, writes *repository.BundleWrites) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, writes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockIBundleRepositoryMockRecorder) Apply(ctx, writes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockIBundleRepository)(nil).Apply), ctx, writes)
+}
@@ -114,6 +114,20 @@ func (mr *MockICacheMockRecorder) InvalidAllKey(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidAllKey", reflect.TypeOf((*MockICache)(nil).InvalidAllKey), ctx, key)
 }
 
+// Ping mocks base method.
+func (m *MockICache) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockICacheMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockICache)(nil).Ping), ctx)
+}
+
 // Set mocks base method.
 func (m *MockICache) Set(ctx context.Context, key string, value any) error {
 	m.ctrl.T.Helper()
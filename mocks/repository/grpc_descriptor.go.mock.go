@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: grpc_descriptor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=grpc_descriptor.go -destination=../../mocks/repository/grpc_descriptor.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIGRPCDescriptorRepository is a mock of IGRPCDescriptorRepository interface.
+type MockIGRPCDescriptorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIGRPCDescriptorRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIGRPCDescriptorRepositoryMockRecorder is the mock recorder for MockIGRPCDescriptorRepository.
+type MockIGRPCDescriptorRepositoryMockRecorder struct {
+	mock *MockIGRPCDescriptorRepository
+}
+
+// NewMockIGRPCDescriptorRepository creates a new mock instance.
+func NewMockIGRPCDescriptorRepository(ctrl *gomock.Controller) *MockIGRPCDescriptorRepository {
+	mock := &MockIGRPCDescriptorRepository{ctrl: ctrl}
+	mock.recorder = &MockIGRPCDescriptorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIGRPCDescriptorRepository) EXPECT() *MockIGRPCDescriptorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByServiceName mocks base method.
+func (m *MockIGRPCDescriptorRepository) DeleteByServiceName(ctx context.Context, serviceName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByServiceName", ctx, serviceName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByServiceName indicates an expected call of DeleteByServiceName.
+func (mr *MockIGRPCDescriptorRepositoryMockRecorder) DeleteByServiceName(ctx, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByServiceName", reflect.TypeOf((*MockIGRPCDescriptorRepository)(nil).DeleteByServiceName), ctx, serviceName)
+}
+
+// FindByServiceName mocks base method.
+func (m *MockIGRPCDescriptorRepository) FindByServiceName(ctx context.Context, serviceName string) (*domain.GRPCDescriptor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByServiceName", ctx, serviceName)
+	ret0, _ := ret[0].(*domain.GRPCDescriptor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByServiceName indicates an expected call of FindByServiceName.
+func (mr *MockIGRPCDescriptorRepositoryMockRecorder) FindByServiceName(ctx, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByServiceName", reflect.TypeOf((*MockIGRPCDescriptorRepository)(nil).FindByServiceName), ctx, serviceName)
+}
+
+// ListAll mocks base method.
+func (m *MockIGRPCDescriptorRepository) ListAll(ctx context.Context) ([]domain.GRPCDescriptor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]domain.GRPCDescriptor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockIGRPCDescriptorRepositoryMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockIGRPCDescriptorRepository)(nil).ListAll), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockIGRPCDescriptorRepository) Upsert(ctx context.Context, d *domain.GRPCDescriptor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockIGRPCDescriptorRepositoryMockRecorder) Upsert(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockIGRPCDescriptorRepository)(nil).Upsert), ctx, d)
+}
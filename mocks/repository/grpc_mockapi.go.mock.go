@@ -101,6 +101,21 @@ func (mr *MockIGRPCMockAPIRepositoryMockRecorder) FindByID(ctx, id any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockIGRPCMockAPIRepository)(nil).FindByID), ctx, id)
 }
 
+// ListActiveByMethod mocks base method.
+func (m *MockIGRPCMockAPIRepository) ListActiveByMethod(ctx context.Context, featureName, scenarioName, serviceName, methodName string) ([]domain.GRPCMockAPI, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveByMethod", ctx, featureName, scenarioName, serviceName, methodName)
+	ret0, _ := ret[0].([]domain.GRPCMockAPI)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveByMethod indicates an expected call of ListActiveByMethod.
+func (mr *MockIGRPCMockAPIRepositoryMockRecorder) ListActiveByMethod(ctx, featureName, scenarioName, serviceName, methodName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveByMethod", reflect.TypeOf((*MockIGRPCMockAPIRepository)(nil).ListActiveByMethod), ctx, featureName, scenarioName, serviceName, methodName)
+}
+
 // ListAll mocks base method.
 func (m *MockIGRPCMockAPIRepository) ListAll(ctx context.Context) ([]domain.GRPCMockAPI, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +146,20 @@ func (mr *MockIGRPCMockAPIRepositoryMockRecorder) ListByFeatureAndScenario(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFeatureAndScenario", reflect.TypeOf((*MockIGRPCMockAPIRepository)(nil).ListByFeatureAndScenario), ctx, featureName, scenarioName)
 }
 
+// Replace mocks base method.
+func (m_2 *MockIGRPCMockAPIRepository) Replace(ctx context.Context, m *domain.GRPCMockAPI) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Replace", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockIGRPCMockAPIRepositoryMockRecorder) Replace(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockIGRPCMockAPIRepository)(nil).Replace), ctx, m)
+}
+
 // UpdateByID mocks base method.
 func (m *MockIGRPCMockAPIRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: grpc_proxy.go
+//
+// Generated by this command:
+//
+//	mockgen -source=grpc_proxy.go -destination=../../mocks/repository/grpc_proxy.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIGRPCProxyRepository is a mock of IGRPCProxyRepository interface.
+type MockIGRPCProxyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIGRPCProxyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIGRPCProxyRepositoryMockRecorder is the mock recorder for MockIGRPCProxyRepository.
+type MockIGRPCProxyRepositoryMockRecorder struct {
+	mock *MockIGRPCProxyRepository
+}
+
+// NewMockIGRPCProxyRepository creates a new mock instance.
+func NewMockIGRPCProxyRepository(ctrl *gomock.Controller) *MockIGRPCProxyRepository {
+	mock := &MockIGRPCProxyRepository{ctrl: ctrl}
+	mock.recorder = &MockIGRPCProxyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIGRPCProxyRepository) EXPECT() *MockIGRPCProxyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByServiceName mocks base method.
+func (m *MockIGRPCProxyRepository) DeleteByServiceName(ctx context.Context, serviceName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByServiceName", ctx, serviceName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByServiceName indicates an expected call of DeleteByServiceName.
+func (mr *MockIGRPCProxyRepositoryMockRecorder) DeleteByServiceName(ctx, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByServiceName", reflect.TypeOf((*MockIGRPCProxyRepository)(nil).DeleteByServiceName), ctx, serviceName)
+}
+
+// FindByServiceName mocks base method.
+func (m *MockIGRPCProxyRepository) FindByServiceName(ctx context.Context, serviceName string) (*domain.GRPCProxy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByServiceName", ctx, serviceName)
+	ret0, _ := ret[0].(*domain.GRPCProxy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByServiceName indicates an expected call of FindByServiceName.
+func (mr *MockIGRPCProxyRepositoryMockRecorder) FindByServiceName(ctx, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByServiceName", reflect.TypeOf((*MockIGRPCProxyRepository)(nil).FindByServiceName), ctx, serviceName)
+}
+
+// ListAll mocks base method.
+func (m *MockIGRPCProxyRepository) ListAll(ctx context.Context) ([]domain.GRPCProxy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]domain.GRPCProxy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockIGRPCProxyRepositoryMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockIGRPCProxyRepository)(nil).ListAll), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockIGRPCProxyRepository) Upsert(ctx context.Context, p *domain.GRPCProxy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockIGRPCProxyRepositoryMockRecorder) Upsert(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockIGRPCProxyRepository)(nil).Upsert), ctx, p)
+}
@@ -189,6 +189,21 @@ func (mr *MockIMockAPIRepositoryMockRecorder) ListAllActiveAPIs(ctx any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllActiveAPIs", reflect.TypeOf((*MockIMockAPIRepository)(nil).ListAllActiveAPIs), ctx)
 }
 
+// ListByFeatureAndScenario mocks base method.
+func (m *MockIMockAPIRepository) ListByFeatureAndScenario(ctx context.Context, featureName, scenarioName string) ([]domain.MockAPI, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFeatureAndScenario", ctx, featureName, scenarioName)
+	ret0, _ := ret[0].([]domain.MockAPI)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFeatureAndScenario indicates an expected call of ListByFeatureAndScenario.
+func (mr *MockIMockAPIRepositoryMockRecorder) ListByFeatureAndScenario(ctx, featureName, scenarioName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFeatureAndScenario", reflect.TypeOf((*MockIMockAPIRepository)(nil).ListByFeatureAndScenario), ctx, featureName, scenarioName)
+}
+
 // ListByScenarioNamePaginated mocks base method.
 func (m *MockIMockAPIRepository) ListByScenarioNamePaginated(ctx context.Context, scenarioName string, params domain.PaginationParams) ([]domain.MockAPI, int64, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +220,20 @@ func (mr *MockIMockAPIRepositoryMockRecorder) ListByScenarioNamePaginated(ctx, s
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByScenarioNamePaginated", reflect.TypeOf((*MockIMockAPIRepository)(nil).ListByScenarioNamePaginated), ctx, scenarioName, params)
 }
 
+// Replace mocks base method.
+func (m_2 *MockIMockAPIRepository) Replace(ctx context.Context, m *domain.MockAPI) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Replace", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockIMockAPIRepositoryMockRecorder) Replace(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockIMockAPIRepository)(nil).Replace), ctx, m)
+}
+
 // SearchByScenarioAndNameOrPath mocks base method.
 func (m *MockIMockAPIRepository) SearchByScenarioAndNameOrPath(ctx context.Context, scenarioName, query string, params domain.PaginationParams) ([]domain.MockAPI, int64, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rename.go
+//
+// Generated by this command:
+//
+//	mockgen -source=rename.go -destination=../../mocks/repository/rename.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRenameRepository is a mock of IRenameRepository interface.
+type MockIRenameRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRenameRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIRenameRepositoryMockRecorder is the mock recorder for MockIRenameRepository.
+type MockIRenameRepositoryMockRecorder struct {
+	mock *MockIRenameRepository
+}
+
+// NewMockIRenameRepository creates a new mock instance.
+func NewMockIRenameRepository(ctrl *gomock.Controller) *MockIRenameRepository {
+	mock := &MockIRenameRepository{ctrl: ctrl}
+	mock.recorder = &MockIRenameRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRenameRepository) EXPECT() *MockIRenameRepositoryMockRecorder {
+	return m.recorder
+}
+
+// RenameFeature mocks base method.
+func (m *MockIRenameRepository) RenameFeature(ctx context.Context, oldName, newName string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameFeature", ctx, oldName, newName)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameFeature indicates an expected call of RenameFeature.
+func (mr *MockIRenameRepositoryMockRecorder) RenameFeature(ctx, oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFeature", reflect.TypeOf((*MockIRenameRepository)(nil).RenameFeature), ctx, oldName, newName)
+}
+
+// RenameScenario mocks base method.
+func (m *MockIRenameRepository) RenameScenario(ctx context.Context, featureName, oldName, newName string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameScenario", ctx, featureName, oldName, newName)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameScenario indicates an expected call of RenameScenario.
+func (mr *MockIRenameRepositoryMockRecorder) RenameScenario(ctx, featureName, oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameScenario", reflect.TypeOf((*MockIRenameRepository)(nil).RenameScenario), ctx, featureName, oldName, newName)
+}
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revision.go
+//
+// Generated by this command:
+//
+//	mockgen -source=revision.go -destination=../../mocks/repository/revision.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRevisionRepository is a mock of IRevisionRepository interface.
+type MockIRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIRevisionRepositoryMockRecorder is the mock recorder for MockIRevisionRepository.
+type MockIRevisionRepositoryMockRecorder struct {
+	mock *MockIRevisionRepository
+}
+
+// NewMockIRevisionRepository creates a new mock instance.
+func NewMockIRevisionRepository(ctrl *gomock.Controller) *MockIRevisionRepository {
+	mock := &MockIRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockIRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRevisionRepository) EXPECT() *MockIRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIRevisionRepository) Create(ctx context.Context, r *domain.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIRevisionRepositoryMockRecorder) Create(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIRevisionRepository)(nil).Create), ctx, r)
+}
+
+// FindByVersion mocks base method.
+func (m *MockIRevisionRepository) FindByVersion(ctx context.Context, kind string, resourceID primitive.ObjectID, version int) (*domain.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", ctx, kind, resourceID, version)
+	ret0, _ := ret[0].(*domain.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion.
+func (mr *MockIRevisionRepositoryMockRecorder) FindByVersion(ctx, kind, resourceID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockIRevisionRepository)(nil).FindByVersion), ctx, kind, resourceID, version)
+}
+
+// FindLatest mocks base method.
+func (m *MockIRevisionRepository) FindLatest(ctx context.Context, kind string, resourceID primitive.ObjectID) (*domain.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatest", ctx, kind, resourceID)
+	ret0, _ := ret[0].(*domain.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatest indicates an expected call of FindLatest.
+func (mr *MockIRevisionRepositoryMockRecorder) FindLatest(ctx, kind, resourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatest", reflect.TypeOf((*MockIRevisionRepository)(nil).FindLatest), ctx, kind, resourceID)
+}
+
+// ListByResource mocks base method.
+func (m *MockIRevisionRepository) ListByResource(ctx context.Context, kind string, resourceID primitive.ObjectID, params domain.PaginationParams) ([]domain.Revision, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByResource", ctx, kind, resourceID, params)
+	ret0, _ := ret[0].([]domain.Revision)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByResource indicates an expected call of ListByResource.
+func (mr *MockIRevisionRepositoryMockRecorder) ListByResource(ctx, kind, resourceID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByResource", reflect.TypeOf((*MockIRevisionRepository)(nil).ListByResource), ctx, kind, resourceID, params)
+}
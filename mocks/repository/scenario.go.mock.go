@@ -115,6 +115,21 @@ func (mr *MockIScenarioRepositoryMockRecorder) GetByObjectID(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByObjectID", reflect.TypeOf((*MockIScenarioRepository)(nil).GetByObjectID), ctx, id)
 }
 
+// ListByFeatureName mocks base method.
+func (m *MockIScenarioRepository) ListByFeatureName(ctx context.Context, featureName string) ([]domain.Scenario, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFeatureName", ctx, featureName)
+	ret0, _ := ret[0].([]domain.Scenario)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFeatureName indicates an expected call of ListByFeatureName.
+func (mr *MockIScenarioRepositoryMockRecorder) ListByFeatureName(ctx, featureName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFeatureName", reflect.TypeOf((*MockIScenarioRepository)(nil).ListByFeatureName), ctx, featureName)
+}
+
 // ListByFeatureNamePaginated mocks base method.
 func (m *MockIScenarioRepository) ListByFeatureNamePaginated(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.Scenario, int64, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +146,20 @@ func (mr *MockIScenarioRepositoryMockRecorder) ListByFeatureNamePaginated(ctx, f
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFeatureNamePaginated", reflect.TypeOf((*MockIScenarioRepository)(nil).ListByFeatureNamePaginated), ctx, featureName, params)
 }
 
+// Replace mocks base method.
+func (m *MockIScenarioRepository) Replace(ctx context.Context, s *domain.Scenario) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockIScenarioRepositoryMockRecorder) Replace(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockIScenarioRepository)(nil).Replace), ctx, s)
+}
+
 // SearchByFeatureAndName mocks base method.
 func (m *MockIScenarioRepository) SearchByFeatureAndName(ctx context.Context, featureName, query string, params domain.PaginationParams) ([]domain.Scenario, int64, error) {
 	m.ctrl.T.Helper()
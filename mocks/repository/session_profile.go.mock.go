@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: session_profile.go
+//
+// Generated by this command:
+//
+//	mockgen -source=session_profile.go -destination=../../mocks/repository/session_profile.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockISessionProfileRepository is a mock of ISessionProfileRepository interface.
+type MockISessionProfileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionProfileRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockISessionProfileRepositoryMockRecorder is the mock recorder for MockISessionProfileRepository.
+type MockISessionProfileRepositoryMockRecorder struct {
+	mock *MockISessionProfileRepository
+}
+
+// NewMockISessionProfileRepository creates a new mock instance.
+func NewMockISessionProfileRepository(ctrl *gomock.Controller) *MockISessionProfileRepository {
+	mock := &MockISessionProfileRepository{ctrl: ctrl}
+	mock.recorder = &MockISessionProfileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionProfileRepository) EXPECT() *MockISessionProfileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByName mocks base method.
+func (m *MockISessionProfileRepository) DeleteByName(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByName", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByName indicates an expected call of DeleteByName.
+func (mr *MockISessionProfileRepositoryMockRecorder) DeleteByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByName", reflect.TypeOf((*MockISessionProfileRepository)(nil).DeleteByName), ctx, name)
+}
+
+// FindByName mocks base method.
+func (m *MockISessionProfileRepository) FindByName(ctx context.Context, name string) (*domain.SessionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, name)
+	ret0, _ := ret[0].(*domain.SessionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockISessionProfileRepositoryMockRecorder) FindByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockISessionProfileRepository)(nil).FindByName), ctx, name)
+}
+
+// ListAll mocks base method.
+func (m *MockISessionProfileRepository) ListAll(ctx context.Context) ([]domain.SessionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]domain.SessionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockISessionProfileRepositoryMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockISessionProfileRepository)(nil).ListAll), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockISessionProfileRepository) Upsert(ctx context.Context, p *domain.SessionProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockISessionProfileRepositoryMockRecorder) Upsert(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockISessionProfileRepository)(nil).Upsert), ctx, p)
+}
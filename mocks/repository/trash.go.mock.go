@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: trash.go
+//
+// Generated by this command:
+//
+//	mockgen -source=trash.go -destination=../../mocks/repository/trash.go.mock.go -package=repository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITrashRepository is a mock of ITrashRepository interface.
+type MockITrashRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITrashRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockITrashRepositoryMockRecorder is the mock recorder for MockITrashRepository.
+type MockITrashRepositoryMockRecorder struct {
+	mock *MockITrashRepository
+}
+
+// NewMockITrashRepository creates a new mock instance.
+func NewMockITrashRepository(ctrl *gomock.Controller) *MockITrashRepository {
+	mock := &MockITrashRepository{ctrl: ctrl}
+	mock.recorder = &MockITrashRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITrashRepository) EXPECT() *MockITrashRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockITrashRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockITrashRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockITrashRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockITrashRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockITrashRepositoryMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockITrashRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// FindByID mocks base method.
+func (m *MockITrashRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*domain.TrashEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockITrashRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockITrashRepository)(nil).FindByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockITrashRepository) List(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.TrashEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, featureName, params)
+	ret0, _ := ret[0].([]domain.TrashEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockITrashRepositoryMockRecorder) List(ctx, featureName, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockITrashRepository)(nil).List), ctx, featureName, params)
+}
+
+// Restore mocks base method.
+func (m *MockITrashRepository) Restore(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*domain.TrashEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockITrashRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockITrashRepository)(nil).Restore), ctx, id)
+}
+
+// TrashFeature mocks base method.
+func (m *MockITrashRepository) TrashFeature(ctx context.Context, entry *domain.TrashEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashFeature", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashFeature indicates an expected call of TrashFeature.
+func (mr *MockITrashRepositoryMockRecorder) TrashFeature(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashFeature", reflect.TypeOf((*MockITrashRepository)(nil).TrashFeature), ctx, entry)
+}
+
+// TrashScenario mocks base method.
+func (m *MockITrashRepository) TrashScenario(ctx context.Context, entry *domain.TrashEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashScenario", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashScenario indicates an expected call of TrashScenario.
+func (mr *MockITrashRepositoryMockRecorder) TrashScenario(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashScenario", reflect.TypeOf((*MockITrashRepository)(nil).TrashScenario), ctx, entry)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bundle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=bundle.go -destination=../../mocks/usecase/bundle.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	usecase "github.com/namnv2496/mocktool/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIBundleUC is a mock of IBundleUC interface.
+type MockIBundleUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBundleUCMockRecorder
+	isgomock struct{}
+}
+
+// MockIBundleUCMockRecorder is the mock recorder for MockIBundleUC.
+type MockIBundleUCMockRecorder struct {
+	mock *MockIBundleUC
+}
+
+// NewMockIBundleUC creates a new mock instance.
+func NewMockIBundleUC(ctrl *gomock.Controller) *MockIBundleUC {
+	mock := &MockIBundleUC{ctrl: ctrl}
+	mock.recorder = &MockIBundleUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBundleUC) EXPECT() *MockIBundleUCMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockIBundleUC) Export(ctx context.Context, featureName string, scenarios []string) (*domain.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, featureName, scenarios)
+	ret0, _ := ret[0].(*domain.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockIBundleUCMockRecorder) Export(ctx, featureName, scenarios any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockIBundleUC)(nil).Export), ctx, featureName, scenarios)
+}
+
+// Import mocks base method.
+func (m *MockIBundleUC) Import(ctx context.Context, bundle *domain.Bundle, dryRun bool, actor string) (*usecase.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, bundle, dryRun, actor)
+	ret0, _ := ret[0].(*usecase.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockIBundleUCMockRecorder) Import(ctx, bundle, dryRun, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockIBundleUC)(nil).Import), ctx, bundle, dryRun, actor)
+}
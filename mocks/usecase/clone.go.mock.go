@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: clone.go
+//
+// Generated by this command:
+//
+//	mockgen -source=clone.go -destination=../../mocks/usecase/clone.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/namnv2496/mocktool/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockICloneUC is a mock of ICloneUC interface.
+type MockICloneUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockICloneUCMockRecorder
+	isgomock struct{}
+}
+
+// MockICloneUCMockRecorder is the mock recorder for MockICloneUC.
+type MockICloneUCMockRecorder struct {
+	mock *MockICloneUC
+}
+
+// NewMockICloneUC creates a new mock instance.
+func NewMockICloneUC(ctrl *gomock.Controller) *MockICloneUC {
+	mock := &MockICloneUC{ctrl: ctrl}
+	mock.recorder = &MockICloneUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICloneUC) EXPECT() *MockICloneUCMockRecorder {
+	return m.recorder
+}
+
+// CloneFeature mocks base method.
+func (m *MockICloneUC) CloneFeature(ctx context.Context, source, target string, opts usecase.CloneOptions) (*usecase.CloneResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneFeature", ctx, source, target, opts)
+	ret0, _ := ret[0].(*usecase.CloneResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloneFeature indicates an expected call of CloneFeature.
+func (mr *MockICloneUCMockRecorder) CloneFeature(ctx, source, target, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneFeature", reflect.TypeOf((*MockICloneUC)(nil).CloneFeature), ctx, source, target, opts)
+}
+
+// CloneScenario mocks base method.
+func (m *MockICloneUC) CloneScenario(ctx context.Context, sourceFeature, sourceScenario, targetFeature, targetScenario string, opts usecase.CloneOptions) (*usecase.CloneResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneScenario", ctx, sourceFeature, sourceScenario, targetFeature, targetScenario, opts)
+	ret0, _ := ret[0].(*usecase.CloneResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloneScenario indicates an expected call of CloneScenario.
+func (mr *MockICloneUCMockRecorder) CloneScenario(ctx, sourceFeature, sourceScenario, targetFeature, targetScenario, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneScenario", reflect.TypeOf((*MockICloneUC)(nil).CloneScenario), ctx, sourceFeature, sourceScenario, targetFeature, targetScenario, opts)
+}
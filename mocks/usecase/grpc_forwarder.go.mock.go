@@ -13,9 +13,10 @@ import (
 	context "context"
 	reflect "reflect"
 
+	usecase "github.com/namnv2496/mocktool/internal/usecase"
 	gomock "go.uber.org/mock/gomock"
 	codes "google.golang.org/grpc/codes"
-	structpb "google.golang.org/protobuf/types/known/structpb"
+	proto "google.golang.org/protobuf/proto"
 )
 
 // MockIGRPCForwardUC is a mock of IGRPCForwardUC interface.
@@ -43,17 +44,32 @@ func (m *MockIGRPCForwardUC) EXPECT() *MockIGRPCForwardUCMockRecorder {
 }
 
 // HandleCall mocks base method.
-func (m *MockIGRPCForwardUC) HandleCall(ctx context.Context, fullMethod string, reqBytes []byte, featureName, scenario string) (*structpb.Struct, codes.Code, error) {
+func (m *MockIGRPCForwardUC) HandleCall(ctx context.Context, fullMethod string, reqBytes []byte, featureName string, accountId *string, scenarioOverride string) (proto.Message, codes.Code, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HandleCall", ctx, fullMethod, reqBytes, featureName, scenario)
-	ret0, _ := ret[0].(*structpb.Struct)
+	ret := m.ctrl.Call(m, "HandleCall", ctx, fullMethod, reqBytes, featureName, accountId, scenarioOverride)
+	ret0, _ := ret[0].(proto.Message)
 	ret1, _ := ret[1].(codes.Code)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
 // HandleCall indicates an expected call of HandleCall.
-func (mr *MockIGRPCForwardUCMockRecorder) HandleCall(ctx, fullMethod, reqBytes, featureName, scenario any) *gomock.Call {
+func (mr *MockIGRPCForwardUCMockRecorder) HandleCall(ctx, fullMethod, reqBytes, featureName, accountId, scenarioOverride any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCall", reflect.TypeOf((*MockIGRPCForwardUC)(nil).HandleCall), ctx, fullMethod, reqBytes, featureName, scenario)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCall", reflect.TypeOf((*MockIGRPCForwardUC)(nil).HandleCall), ctx, fullMethod, reqBytes, featureName, accountId, scenarioOverride)
+}
+
+// HandleStream mocks base method.
+func (m *MockIGRPCForwardUC) HandleStream(ctx context.Context, fullMethod string, stream usecase.GRPCStream, featureName string, accountId *string, scenarioOverride string) (codes.Code, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleStream", ctx, fullMethod, stream, featureName, accountId, scenarioOverride)
+	ret0, _ := ret[0].(codes.Code)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleStream indicates an expected call of HandleStream.
+func (mr *MockIGRPCForwardUCMockRecorder) HandleStream(ctx, fullMethod, stream, featureName, accountId, scenarioOverride any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleStream", reflect.TypeOf((*MockIGRPCForwardUC)(nil).HandleStream), ctx, fullMethod, stream, featureName, accountId, scenarioOverride)
 }
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: grpc_proxy.go
+//
+// Generated by this command:
+//
+//	mockgen -source=grpc_proxy.go -destination=../../mocks/usecase/grpc_proxy.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	usecase "github.com/namnv2496/mocktool/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+	codes "google.golang.org/grpc/codes"
+	metadata "google.golang.org/grpc/metadata"
+)
+
+// MockIGRPCProxyUC is a mock of IGRPCProxyUC interface.
+type MockIGRPCProxyUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockIGRPCProxyUCMockRecorder
+	isgomock struct{}
+}
+
+// MockIGRPCProxyUCMockRecorder is the mock recorder for MockIGRPCProxyUC.
+type MockIGRPCProxyUCMockRecorder struct {
+	mock *MockIGRPCProxyUC
+}
+
+// NewMockIGRPCProxyUC creates a new mock instance.
+func NewMockIGRPCProxyUC(ctrl *gomock.Controller) *MockIGRPCProxyUC {
+	mock := &MockIGRPCProxyUC{ctrl: ctrl}
+	mock.recorder = &MockIGRPCProxyUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIGRPCProxyUC) EXPECT() *MockIGRPCProxyUCMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIGRPCProxyUC) Delete(ctx context.Context, serviceName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, serviceName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIGRPCProxyUCMockRecorder) Delete(ctx, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIGRPCProxyUC)(nil).Delete), ctx, serviceName)
+}
+
+// Find mocks base method.
+func (m *MockIGRPCProxyUC) Find(serviceName string) *domain.GRPCProxy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", serviceName)
+	ret0, _ := ret[0].(*domain.GRPCProxy)
+	return ret0
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockIGRPCProxyUCMockRecorder) Find(serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockIGRPCProxyUC)(nil).Find), serviceName)
+}
+
+// Forward mocks base method.
+func (m *MockIGRPCProxyUC) Forward(ctx context.Context, fullMethod string, reqBytes []byte, md metadata.MD, stream usecase.GRPCStream) (codes.Code, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, fullMethod, reqBytes, md, stream)
+	ret0, _ := ret[0].(codes.Code)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockIGRPCProxyUCMockRecorder) Forward(ctx, fullMethod, reqBytes, md, stream any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockIGRPCProxyUC)(nil).Forward), ctx, fullMethod, reqBytes, md, stream)
+}
+
+// ForwardStream mocks base method.
+func (m *MockIGRPCProxyUC) ForwardStream(ctx context.Context, fullMethod string, md metadata.MD, stream usecase.GRPCStream) (codes.Code, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForwardStream", ctx, fullMethod, md, stream)
+	ret0, _ := ret[0].(codes.Code)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForwardStream indicates an expected call of ForwardStream.
+func (mr *MockIGRPCProxyUCMockRecorder) ForwardStream(ctx, fullMethod, md, stream any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForwardStream", reflect.TypeOf((*MockIGRPCProxyUC)(nil).ForwardStream), ctx, fullMethod, md, stream)
+}
+
+// List mocks base method.
+func (m *MockIGRPCProxyUC) List(ctx context.Context) ([]domain.GRPCProxy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.GRPCProxy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIGRPCProxyUCMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIGRPCProxyUC)(nil).List), ctx)
+}
+
+// Save mocks base method.
+func (m *MockIGRPCProxyUC) Save(ctx context.Context, p *domain.GRPCProxy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockIGRPCProxyUCMockRecorder) Save(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockIGRPCProxyUC)(nil).Save), ctx, p)
+}
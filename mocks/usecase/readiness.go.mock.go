@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: readiness.go
+//
+// Generated by this command:
+//
+//	mockgen -source=readiness.go -destination=../../mocks/usecase/readiness.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIReadinessUC is a mock of IReadinessUC interface.
+type MockIReadinessUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReadinessUCMockRecorder
+	isgomock struct{}
+}
+
+// MockIReadinessUCMockRecorder is the mock recorder for MockIReadinessUC.
+type MockIReadinessUCMockRecorder struct {
+	mock *MockIReadinessUC
+}
+
+// NewMockIReadinessUC creates a new mock instance.
+func NewMockIReadinessUC(ctrl *gomock.Controller) *MockIReadinessUC {
+	mock := &MockIReadinessUC{ctrl: ctrl}
+	mock.recorder = &MockIReadinessUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReadinessUC) EXPECT() *MockIReadinessUCMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockIReadinessUC) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockIReadinessUCMockRecorder) Check(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockIReadinessUC)(nil).Check), ctx)
+}
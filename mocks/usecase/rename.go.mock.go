@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rename.go
+//
+// Generated by this command:
+//
+//	mockgen -source=rename.go -destination=../../mocks/usecase/rename.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/namnv2496/mocktool/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRenameUC is a mock of IRenameUC interface.
+type MockIRenameUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRenameUCMockRecorder
+	isgomock struct{}
+}
+
+// MockIRenameUCMockRecorder is the mock recorder for MockIRenameUC.
+type MockIRenameUCMockRecorder struct {
+	mock *MockIRenameUC
+}
+
+// NewMockIRenameUC creates a new mock instance.
+func NewMockIRenameUC(ctrl *gomock.Controller) *MockIRenameUC {
+	mock := &MockIRenameUC{ctrl: ctrl}
+	mock.recorder = &MockIRenameUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRenameUC) EXPECT() *MockIRenameUCMockRecorder {
+	return m.recorder
+}
+
+// RenameFeature mocks base method.
+func (m *MockIRenameUC) RenameFeature(ctx context.Context, oldName, newName string) (*usecase.RenameResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameFeature", ctx, oldName, newName)
+	ret0, _ := ret[0].(*usecase.RenameResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameFeature indicates an expected call of RenameFeature.
+func (mr *MockIRenameUCMockRecorder) RenameFeature(ctx, oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFeature", reflect.TypeOf((*MockIRenameUC)(nil).RenameFeature), ctx, oldName, newName)
+}
+
+// RenameScenario mocks base method.
+func (m *MockIRenameUC) RenameScenario(ctx context.Context, featureName, oldName, newName string) (*usecase.RenameResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameScenario", ctx, featureName, oldName, newName)
+	ret0, _ := ret[0].(*usecase.RenameResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameScenario indicates an expected call of RenameScenario.
+func (mr *MockIRenameUCMockRecorder) RenameScenario(ctx, featureName, oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameScenario", reflect.TypeOf((*MockIRenameUC)(nil).RenameScenario), ctx, featureName, oldName, newName)
+}
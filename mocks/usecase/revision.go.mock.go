@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revision.go
+//
+// Generated by this command:
+//
+//	mockgen -source=revision.go -destination=../../mocks/usecase/revision.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	usecase "github.com/namnv2496/mocktool/internal/usecase"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRevisionUC is a mock of IRevisionUC interface.
+type MockIRevisionUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRevisionUCMockRecorder
+	isgomock struct{}
+}
+
+// MockIRevisionUCMockRecorder is the mock recorder for MockIRevisionUC.
+type MockIRevisionUCMockRecorder struct {
+	mock *MockIRevisionUC
+}
+
+// NewMockIRevisionUC creates a new mock instance.
+func NewMockIRevisionUC(ctrl *gomock.Controller) *MockIRevisionUC {
+	mock := &MockIRevisionUC{ctrl: ctrl}
+	mock.recorder = &MockIRevisionUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRevisionUC) EXPECT() *MockIRevisionUCMockRecorder {
+	return m.recorder
+}
+
+// Diff mocks base method.
+func (m *MockIRevisionUC) Diff(ctx context.Context, kind string, id primitive.ObjectID, from, to int) (*usecase.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, kind, id, from, to)
+	ret0, _ := ret[0].(*usecase.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockIRevisionUCMockRecorder) Diff(ctx, kind, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockIRevisionUC)(nil).Diff), ctx, kind, id, from, to)
+}
+
+// List mocks base method.
+func (m *MockIRevisionUC) List(ctx context.Context, kind string, id primitive.ObjectID, params domain.PaginationParams) ([]domain.Revision, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, kind, id, params)
+	ret0, _ := ret[0].([]domain.Revision)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockIRevisionUCMockRecorder) List(ctx, kind, id, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIRevisionUC)(nil).List), ctx, kind, id, params)
+}
+
+// Record mocks base method.
+func (m *MockIRevisionUC) Record(ctx context.Context, kind string, id primitive.ObjectID, action, author string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, kind, id, action, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockIRevisionUCMockRecorder) Record(ctx, kind, id, action, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockIRevisionUC)(nil).Record), ctx, kind, id, action, author)
+}
+
+// RecordDeleted mocks base method.
+func (m *MockIRevisionUC) RecordDeleted(ctx context.Context, kind string, id primitive.ObjectID, doc any, author string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDeleted", ctx, kind, id, doc, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDeleted indicates an expected call of RecordDeleted.
+func (mr *MockIRevisionUCMockRecorder) RecordDeleted(ctx, kind, id, doc, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDeleted", reflect.TypeOf((*MockIRevisionUC)(nil).RecordDeleted), ctx, kind, id, doc, author)
+}
+
+// Restore mocks base method.
+func (m *MockIRevisionUC) Restore(ctx context.Context, kind string, id primitive.ObjectID, version int, author string) (*domain.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, kind, id, version, author)
+	ret0, _ := ret[0].(*domain.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockIRevisionUCMockRecorder) Restore(ctx, kind, id, version, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockIRevisionUC)(nil).Restore), ctx, kind, id, version, author)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: session.go
+//
+// Generated by this command:
+//
+//	mockgen -source=session.go -destination=../../mocks/usecase/session.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockISessionUC is a mock of ISessionUC interface.
+type MockISessionUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionUCMockRecorder
+	isgomock struct{}
+}
+
+// MockISessionUCMockRecorder is the mock recorder for MockISessionUC.
+type MockISessionUCMockRecorder struct {
+	mock *MockISessionUC
+}
+
+// NewMockISessionUC creates a new mock instance.
+func NewMockISessionUC(ctrl *gomock.Controller) *MockISessionUC {
+	mock := &MockISessionUC{ctrl: ctrl}
+	mock.recorder = &MockISessionUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionUC) EXPECT() *MockISessionUCMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockISessionUC) Apply(ctx context.Context, profileName, accountId string, duration time.Duration) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, profileName, accountId, duration)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockISessionUCMockRecorder) Apply(ctx, profileName, accountId, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockISessionUC)(nil).Apply), ctx, profileName, accountId, duration)
+}
+
+// Revert mocks base method.
+func (m *MockISessionUC) Revert(ctx context.Context, id primitive.ObjectID) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, id)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockISessionUCMockRecorder) Revert(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockISessionUC)(nil).Revert), ctx, id)
+}
+
+// SaveProfile mocks base method.
+func (m *MockISessionUC) SaveProfile(ctx context.Context, p *domain.SessionProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProfile", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProfile indicates an expected call of SaveProfile.
+func (mr *MockISessionUCMockRecorder) SaveProfile(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockISessionUC)(nil).SaveProfile), ctx, p)
+}
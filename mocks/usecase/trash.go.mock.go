@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: trash.go
+//
+// Generated by this command:
+//
+//	mockgen -source=trash.go -destination=../../mocks/usecase/trash.go.mock.go -package=usecase
+//
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/namnv2496/mocktool/internal/domain"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITrashUC is a mock of ITrashUC interface.
+type MockITrashUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockITrashUCMockRecorder
+	isgomock struct{}
+}
+
+// MockITrashUCMockRecorder is the mock recorder for MockITrashUC.
+type MockITrashUCMockRecorder struct {
+	mock *MockITrashUC
+}
+
+// NewMockITrashUC creates a new mock instance.
+func NewMockITrashUC(ctrl *gomock.Controller) *MockITrashUC {
+	mock := &MockITrashUC{ctrl: ctrl}
+	mock.recorder = &MockITrashUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITrashUC) EXPECT() *MockITrashUCMockRecorder {
+	return m.recorder
+}
+
+// DeleteFeature mocks base method.
+func (m *MockITrashUC) DeleteFeature(ctx context.Context, featureName, actor string) (*domain.TrashEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeature", ctx, featureName, actor)
+	ret0, _ := ret[0].(*domain.TrashEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFeature indicates an expected call of DeleteFeature.
+func (mr *MockITrashUCMockRecorder) DeleteFeature(ctx, featureName, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeature", reflect.TypeOf((*MockITrashUC)(nil).DeleteFeature), ctx, featureName, actor)
+}
+
+// DeleteScenario mocks base method.
+func (m *MockITrashUC) DeleteScenario(ctx context.Context, featureName, scenarioName, actor string) (*domain.TrashEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScenario", ctx, featureName, scenarioName, actor)
+	ret0, _ := ret[0].(*domain.TrashEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScenario indicates an expected call of DeleteScenario.
+func (mr *MockITrashUCMockRecorder) DeleteScenario(ctx, featureName, scenarioName, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScenario", reflect.TypeOf((*MockITrashUC)(nil).DeleteScenario), ctx, featureName, scenarioName, actor)
+}
+
+// List mocks base method.
+func (m *MockITrashUC) List(ctx context.Context, featureName string, params domain.PaginationParams) ([]domain.TrashEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, featureName, params)
+	ret0, _ := ret[0].([]domain.TrashEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockITrashUCMockRecorder) List(ctx, featureName, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockITrashUC)(nil).List), ctx, featureName, params)
+}
+
+// Purge mocks base method.
+func (m *MockITrashUC) Purge(ctx context.Context, id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockITrashUCMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockITrashUC)(nil).Purge), ctx, id)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockITrashUC) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockITrashUCMockRecorder) PurgeExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockITrashUC)(nil).PurgeExpired), ctx, now)
+}
+
+// Restore mocks base method.
+func (m *MockITrashUC) Restore(ctx context.Context, id primitive.ObjectID) (*domain.TrashEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*domain.TrashEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockITrashUCMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockITrashUC)(nil).Restore), ctx, id)
+}
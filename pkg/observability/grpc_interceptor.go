@@ -0,0 +1,197 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/namnv2496/mocktool/pkg/utils"
+)
+
+// How a gRPC call was answered, recorded with SetGRPCMatch.
+const (
+	GRPCMatchMock     = "mock"      // a mock answered
+	GRPCMatchProxy    = "proxy"     // forwarded to the real upstream
+	GRPCMatchNotFound = "not_found" // no mock matched
+	GRPCMatchNone     = "none"      // no lookup happened (bad metadata, reflection, ...)
+)
+
+// GRPCUnknownLabel replaces the service and method labels of calls to
+// methods the server does not know, and the feature label of calls to
+// features it does not know, so clients cannot grow the metrics.
+const GRPCUnknownLabel = "unknown"
+
+// GRPCMethodFilter reports whether a method may be used as a metric label.
+type GRPCMethodFilter func(fullMethod string) bool
+
+// GRPCFeatureFilter reports whether a feature may be used as a metric label.
+type GRPCFeatureFilter func(feature string) bool
+
+// traceContext reads and writes W3C traceparent/tracestate metadata.
+var traceContext = propagation.TraceContext{}
+
+type grpcCallKey struct{}
+
+// grpcCall is filled in while a call runs and read by the interceptor when
+// it ends.
+type grpcCall struct {
+	match string
+}
+
+// SetGRPCMatch records how the current gRPC call was answered. It does
+// nothing outside the gRPC interceptors.
+func SetGRPCMatch(ctx context.Context, match string) {
+	if call, ok := ctx.Value(grpcCallKey{}).(*grpcCall); ok {
+		call.match = match
+	}
+}
+
+// GRPCUnaryServerInterceptor records metrics, a server span and a log line
+// for every unary call. Methods knownMethod rejects and features
+// knownFeature rejects are counted under GRPCUnknownLabel; a nil filter
+// accepts every value.
+func GRPCUnaryServerInterceptor(knownMethod GRPCMethodFilter, knownFeature GRPCFeatureFilter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx, done := StartGRPCCall(ctx, info.FullMethod, md, knownMethod, knownFeature)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// GRPCStreamServerInterceptor is the streaming counterpart of
+// GRPCUnaryServerInterceptor. It also covers the unknown service handler
+// that serves the mocks.
+func GRPCStreamServerInterceptor(knownMethod GRPCMethodFilter, knownFeature GRPCFeatureFilter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		ctx, done := StartGRPCCall(ss.Context(), info.FullMethod, md, knownMethod, knownFeature)
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+		done(err)
+		return err
+	}
+}
+
+// StartGRPCCall continues the caller's trace and returns the call context
+// with a function that ends the call. md is the call metadata; the gRPC-Web
+// and Connect handlers, which bypass the interceptors, pass their headers.
+// The feature comes from the client, so it is only a metric label when
+// knownFeature accepts it; the span and the log line always carry it.
+func StartGRPCCall(
+	ctx context.Context,
+	fullMethod string,
+	md metadata.MD,
+	knownMethod GRPCMethodFilter,
+	knownFeature GRPCFeatureFilter,
+) (context.Context, func(error)) {
+	start := time.Now()
+	service, method := utils.SplitGRPCMethod(fullMethod)
+	feature := firstMetadata(md, "x-feature-name")
+	serviceLabel, methodLabel, featureLabel := service, method, feature
+	if knownMethod != nil && !knownMethod(fullMethod) {
+		serviceLabel, methodLabel = GRPCUnknownLabel, GRPCUnknownLabel
+	}
+	if knownFeature != nil && !knownFeature(feature) {
+		featureLabel = GRPCUnknownLabel
+	}
+
+	ctx = traceContext.Extract(ctx, metadataCarrier(md))
+	ctx, span := GetTracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"), trace.WithSpanKind(trace.SpanKindServer))
+	span.SetAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+	)
+	if feature != "" {
+		span.SetAttributes(attribute.String("mocktool.feature", feature))
+	}
+	if accountID := firstMetadata(md, "x-account-id"); accountID != "" {
+		span.SetAttributes(attribute.String("mocktool.account_id", accountID))
+	}
+
+	call := &grpcCall{match: GRPCMatchNone}
+	ctx = context.WithValue(ctx, grpcCallKey{}, call)
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+		duration := time.Since(start)
+
+		GRPCRequestsTotal.WithLabelValues(featureLabel, serviceLabel, methodLabel, code.String(), call.match).Inc()
+		GRPCRequestDuration.WithLabelValues(featureLabel, serviceLabel, methodLabel).Observe(duration.Seconds())
+
+		span.SetAttributes(
+			attribute.Int("rpc.grpc.status_code", int(code)),
+			attribute.String("mocktool.match", call.match),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+		} else {
+			span.SetStatus(otelcodes.Ok, "")
+		}
+		span.End()
+
+		level := slog.LevelInfo
+		switch code {
+		case codes.Internal, codes.Unknown, codes.DataLoss:
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "gRPC call",
+			"method", fullMethod,
+			"feature", feature,
+			"account_id", firstMetadata(md, "x-account-id"),
+			"scenario", firstMetadata(md, "x-scenario"),
+			"code", code.String(),
+			"match", call.match,
+			"duration_ms", duration.Milliseconds(),
+			"trace_id", span.SpanContext().TraceID().String(),
+		)
+	}
+}
+
+// tracedServerStream hands the traced call context to the handler.
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier adapts incoming metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	return firstMetadata(metadata.MD(c), key)
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
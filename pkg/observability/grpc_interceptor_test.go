@@ -0,0 +1,101 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestGRPCStreamServerInterceptor(t *testing.T) {
+	cleanup, err := InitTracing("test-service", "1.0.0", false)
+	require.NoError(t, err)
+	defer cleanup()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-feature-name", "checkout",
+		"traceparent", "00-"+traceID+"-00f067aa0ba902b7-01",
+	))
+	counter := GRPCRequestsTotal.WithLabelValues("checkout", "demo.Svc", "Get", "NotFound", GRPCMatchNotFound)
+	before := testutil.ToFloat64(counter)
+
+	interceptor := GRPCStreamServerInterceptor(nil, nil)
+	err = interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/demo.Svc/Get"},
+		func(_ any, ss grpc.ServerStream) error {
+			span := trace.SpanFromContext(ss.Context())
+			assert.Equal(t, traceID, span.SpanContext().TraceID().String(), "the caller's trace continues")
+			SetGRPCMatch(ss.Context(), GRPCMatchNotFound)
+			return status.Error(codes.NotFound, "mock not found")
+		})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestGRPCUnaryServerInterceptor(t *testing.T) {
+	counter := GRPCRequestsTotal.WithLabelValues("", "grpc.health.v1.Health", "Check", "OK", GRPCMatchNone)
+	before := testutil.ToFloat64(counter)
+
+	interceptor := GRPCUnaryServerInterceptor(nil, nil)
+	resp, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+		func(ctx context.Context, req any) (any, error) {
+			assert.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+			return "resp", nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestStartGRPCCall_BucketsUnknownMethods(t *testing.T) {
+	known := func(fullMethod string) bool { return fullMethod == "/demo.Svc/Get" }
+	unknown := GRPCRequestsTotal.WithLabelValues("checkout", GRPCUnknownLabel, GRPCUnknownLabel, "NotFound", GRPCMatchNone)
+	before := testutil.ToFloat64(unknown)
+
+	md := metadata.Pairs("x-feature-name", "checkout")
+	for _, method := range []string{"/random.Svc/A", "/random.Svc/B"} {
+		_, done := StartGRPCCall(context.Background(), method, md, known, nil)
+		done(status.Error(codes.NotFound, "mock not found"))
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(unknown))
+
+	assert.Zero(t, testutil.ToFloat64(GRPCRequestsTotal.WithLabelValues("checkout", "random.Svc", "A", "NotFound", GRPCMatchNone)))
+}
+
+func TestStartGRPCCall_BucketsUnknownFeatures(t *testing.T) {
+	known := func(feature string) bool { return feature == "checkout" }
+	unknown := GRPCRequestsTotal.WithLabelValues(GRPCUnknownLabel, "demo.Svc", "Get", "OK", GRPCMatchNone)
+	before := testutil.ToFloat64(unknown)
+
+	for _, feature := range []string{"random-1", "random-2", ""} {
+		_, done := StartGRPCCall(context.Background(), "/demo.Svc/Get", metadata.Pairs("x-feature-name", feature), nil, known)
+		done(nil)
+	}
+	_, done := StartGRPCCall(context.Background(), "/demo.Svc/Get", metadata.Pairs("x-feature-name", "checkout"), nil, known)
+	done(nil)
+
+	assert.Equal(t, before+3, testutil.ToFloat64(unknown))
+	assert.Equal(t, float64(1), testutil.ToFloat64(GRPCRequestsTotal.WithLabelValues("checkout", "demo.Svc", "Get", "OK", GRPCMatchNone)))
+	assert.Zero(t, testutil.ToFloat64(GRPCRequestsTotal.WithLabelValues("random-1", "demo.Svc", "Get", "OK", GRPCMatchNone)))
+}
+
+func TestSetGRPCMatchOutsideInterceptor(t *testing.T) {
+	assert.NotPanics(t, func() { SetGRPCMatch(context.Background(), GRPCMatchMock) })
+}
@@ -74,6 +74,30 @@ var (
 	)
 )
 
+// gRPC Metrics
+var (
+	// GRPCRequestsTotal counts gRPC mock calls by feature, method, status code
+	// and how the call was answered (mock, proxy, not_found or none)
+	GRPCRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mocktool_grpc_requests_total",
+			Help: "Total number of gRPC requests",
+		},
+		[]string{"feature", "service", "method", "code", "match"},
+	)
+
+	// GRPCRequestDuration measures gRPC call duration in seconds by feature
+	// and method
+	GRPCRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mocktool_grpc_request_duration_seconds",
+			Help:    "gRPC request duration in seconds",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		[]string{"feature", "service", "method"},
+	)
+)
+
 // Cache metrics
 var (
 	MockAPICacheHits = promauto.NewCounterVec(
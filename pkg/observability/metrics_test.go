@@ -17,6 +17,8 @@ func TestMetricsAreRegistered(t *testing.T) {
 		{"HTTPRequestSize", HTTPRequestSize},
 		{"HTTPResponseSize", HTTPResponseSize},
 		{"HTTPErrorsTotal", HTTPErrorsTotal},
+		{"GRPCRequestsTotal", GRPCRequestsTotal},
+		{"GRPCRequestDuration", GRPCRequestDuration},
 		{"DBOperationsTotal", DBOperationsTotal},
 		{"DBOperationDuration", DBOperationDuration},
 		{"DBConnectionPoolSize", DBConnectionPoolSize},
@@ -0,0 +1,256 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Client certificate policies accepted by TLSOptions.ClientAuth.
+const (
+	ClientAuthNone    = "none"    // no client certificate is asked for
+	ClientAuthRequest = "request" // verified when the client sends one
+	ClientAuthRequire = "require" // every client must present a valid certificate
+)
+
+const (
+	caCertFile = "ca.crt"
+	caKeyFile  = "ca.key"
+
+	caValidity     = 10 * 365 * 24 * time.Hour
+	serverValidity = 365 * 24 * time.Hour
+)
+
+// TLSOptions describes where the server certificate comes from and how
+// clients are verified.
+type TLSOptions struct {
+	// CertFile and KeyFile are a PEM certificate and key. When both are empty
+	// a local CA in AutoCADir issues the server certificate.
+	CertFile string
+	KeyFile  string
+	// AutoCADir keeps the generated CA so clients only have to trust it once.
+	AutoCADir string
+	// Hosts are the DNS names and IPs of the generated server certificate.
+	Hosts []string
+	// ClientAuth is one of ClientAuthNone, ClientAuthRequest or ClientAuthRequire.
+	ClientAuth string
+	// ClientCAFile holds the CAs client certificates must chain to. Defaults
+	// to the generated CA.
+	ClientCAFile string
+}
+
+// ServerTLS is the TLS setup shared by every listener.
+type ServerTLS struct {
+	config *tls.Config
+	caPEM  []byte // generated CA, nil when the certificate was provided
+}
+
+// NewServerTLS loads or generates the server certificate described by opts.
+func NewServerTLS(opts TLSOptions) (*ServerTLS, error) {
+	var (
+		cert  tls.Certificate
+		ca    *x509.Certificate
+		caPEM []byte
+		err   error
+	)
+	switch {
+	case opts.CertFile != "" && opts.KeyFile != "":
+		cert, err = tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load server certificate: %w", err)
+		}
+	case opts.CertFile != "" || opts.KeyFile != "":
+		return nil, errors.New("both the certificate and the key file are required")
+	default:
+		var caKey *ecdsa.PrivateKey
+		ca, caKey, caPEM, err = loadOrCreateCA(opts.AutoCADir)
+		if err != nil {
+			return nil, err
+		}
+		cert, err = issueServerCert(ca, caKey, opts.Hosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	switch opts.ClientAuth {
+	case "", ClientAuthNone:
+		return &ServerTLS{config: config, caPEM: caPEM}, nil
+	case ClientAuthRequest:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth %q, expected none, request or require", opts.ClientAuth)
+	}
+
+	pool := x509.NewCertPool()
+	switch {
+	case opts.ClientCAFile != "":
+		data, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", opts.ClientCAFile)
+		}
+	case ca != nil:
+		pool.AddCert(ca)
+	default:
+		return nil, errors.New("client verification needs a client CA file when the server certificate is provided")
+	}
+	config.ClientCAs = pool
+	return &ServerTLS{config: config, caPEM: caPEM}, nil
+}
+
+// Config returns a copy of the server TLS config. It offers h2 and
+// http/1.1 through ALPN.
+func (_self *ServerTLS) Config() *tls.Config {
+	if _self == nil {
+		return nil
+	}
+	return _self.config.Clone()
+}
+
+// CACertPEM returns the generated CA certificate, or nil when the server
+// certificate was provided.
+func (_self *ServerTLS) CACertPEM() []byte {
+	if _self == nil {
+		return nil
+	}
+	return _self.caPEM
+}
+
+// loadOrCreateCA reuses the CA stored in dir, creating it on first use.
+func loadOrCreateCA(dir string) (*x509.Certificate, *ecdsa.PrivateKey, []byte, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	keyPath := filepath.Join(dir, caKeyFile)
+
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+	if certErr == nil && keyErr == nil {
+		ca, key, err := parseCA(certPEM, keyPEM)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("load CA from %s: %w", dir, err)
+		}
+		return ca, key, certPEM, nil
+	}
+	if certErr != nil && !errors.Is(certErr, os.ErrNotExist) {
+		return nil, nil, nil, certErr
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "mocktool local CA", Organization: []string{"mocktool"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := os.WriteFile(certPath, certPEM, 0o644); err != nil {
+		return nil, nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ca, key, certPEM, nil
+}
+
+func parseCA(certPEM, keyPEM []byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errors.New("invalid PEM data")
+	}
+	ca, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ca, key, nil
+}
+
+// issueServerCert signs a certificate for hosts. It is not stored: a new
+// one is issued on every start.
+func issueServerCert(ca *x509.Certificate, caKey *ecdsa.PrivateKey, hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: "mocktool", Organization: []string{"mocktool"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(serverValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der, ca.Raw}, PrivateKey: key}, nil
+}
+
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
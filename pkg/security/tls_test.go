@@ -0,0 +1,117 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handshake runs a TLS handshake against a listener using serverConfig.
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) (tls.ConnectionState, error) {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer lis.Close()
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.(*tls.Conn).Handshake()
+		_, _ = conn.Read(make([]byte, 1))
+	}()
+
+	conn, err := tls.Dial("tcp", lis.Addr().String(), clientConfig)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+	// TLS 1.3 reports a rejected client certificate on the first read.
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return tls.ConnectionState{}, err
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return tls.ConnectionState{}, err
+	}
+	return conn.ConnectionState(), nil
+}
+
+func TestNewServerTLS_AutoCA(t *testing.T) {
+	dir := t.TempDir()
+	serverTLS, err := NewServerTLS(TLSOptions{AutoCADir: dir, Hosts: []string{"localhost", "127.0.0.1"}})
+	require.NoError(t, err)
+	require.NotNil(t, serverTLS.CACertPEM())
+
+	// The CA is kept on disk and reused on the next start.
+	again, err := NewServerTLS(TLSOptions{AutoCADir: dir, Hosts: []string{"localhost"}})
+	require.NoError(t, err)
+	assert.Equal(t, serverTLS.CACertPEM(), again.CACertPEM())
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(serverTLS.CACertPEM()))
+	state, err := handshake(t, serverTLS.Config(), &tls.Config{
+		RootCAs:    pool,
+		ServerName: "127.0.0.1",
+		NextProtos: []string{"h2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "h2", state.NegotiatedProtocol)
+}
+
+func TestNewServerTLS_RequireClientCert(t *testing.T) {
+	dir := t.TempDir()
+	serverTLS, err := NewServerTLS(TLSOptions{AutoCADir: dir, Hosts: []string{"127.0.0.1"}, ClientAuth: ClientAuthRequire})
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(serverTLS.CACertPEM()))
+
+	_, err = handshake(t, serverTLS.Config(), &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"})
+	assert.Error(t, err, "a client without certificate is rejected")
+
+	// A client certificate signed by the generated CA is accepted.
+	ca, caKey, _, err := loadOrCreateCA(dir)
+	require.NoError(t, err)
+	clientCert := issueClientCert(t, ca, caKey)
+	_, err = handshake(t, serverTLS.Config(), &tls.Config{
+		RootCAs:      pool,
+		ServerName:   "127.0.0.1",
+		Certificates: []tls.Certificate{clientCert},
+	})
+	assert.NoError(t, err)
+}
+
+func TestNewServerTLS_InvalidOptions(t *testing.T) {
+	_, err := NewServerTLS(TLSOptions{CertFile: "server.crt"})
+	assert.Error(t, err)
+
+	_, err = NewServerTLS(TLSOptions{AutoCADir: t.TempDir(), ClientAuth: "always"})
+	assert.Error(t, err)
+
+	var nilTLS *ServerTLS
+	assert.Nil(t, nilTLS.Config())
+	assert.Nil(t, nilTLS.CACertPEM())
+}
+
+// issueClientCert reuses the server certificate template with client usage.
+func issueClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey) tls.Certificate {
+	t.Helper()
+	cert, err := issueServerCert(ca, caKey, nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	leaf.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	leaf.SerialNumber = randomSerial()
+	der, err := x509.CreateCertificate(rand.Reader, leaf, ca, cert.PrivateKey.(*ecdsa.PrivateKey).Public(), caKey)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: cert.PrivateKey}
+}
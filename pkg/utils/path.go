@@ -54,3 +54,13 @@ func splitPath(p string) []string {
 	}
 	return out
 }
+
+// SplitGRPCMethod splits a gRPC full method name (/package.Service/Method)
+// into its service and method names.
+func SplitGRPCMethod(fullMethod string) (service, method string) {
+	trimmed := strings.TrimPrefix(fullMethod, "/")
+	if idx := strings.LastIndex(trimmed, "/"); idx >= 0 {
+		return trimmed[:idx], trimmed[idx+1:]
+	}
+	return trimmed, ""
+}
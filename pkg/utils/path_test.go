@@ -39,3 +39,21 @@ func TestMatchPath(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitGRPCMethod(t *testing.T) {
+	cases := []struct {
+		input   string
+		service string
+		method  string
+	}{
+		{"/com.example.UserService/GetUser", "com.example.UserService", "GetUser"},
+		{"/svc/Method", "svc", "Method"},
+		{"NoSlashAtAll", "NoSlashAtAll", ""},
+	}
+	for _, tc := range cases {
+		service, method := SplitGRPCMethod(tc.input)
+		if service != tc.service || method != tc.method {
+			t.Errorf("SplitGRPCMethod(%q) = %q, %q, want %q, %q", tc.input, service, method, tc.service, tc.method)
+		}
+	}
+}